@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 	"postless/src"
 )
 
@@ -16,5 +17,10 @@ func main() {
 
 	runner := src.NewRunner(fileManager, utils, viewBuilder)
 
+	// Subcommands run headless, without the TUI
+	if len(os.Args) > 1 {
+		os.Exit(runner.RunCommand(os.Args[1:]))
+	}
+
 	runner.Start()
 }
@@ -0,0 +1,662 @@
+package src
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Navigation messages exchanged between screens and the app
+
+type navigateBackMsg struct{}
+
+type requestSelectedMsg struct {
+	item RequestItem
+}
+
+type settingSelectedMsg struct {
+	key string
+}
+
+type editBodyMsg struct {
+	item *RequestItem
+}
+
+type bodyEditedMsg struct {
+	item   *RequestItem
+	fields []BodyField
+}
+
+type executeRequestMsg struct {
+	item     *RequestItem
+	resolved *ResolvedRequest // Send exactly this request instead of resolving the item again
+	replace  bool             // Replace the current screen instead of pushing a new one (re-send)
+}
+
+type requestExecutedMsg struct {
+	item     *RequestItem
+	response *HTTPResponse
+	captures []CaptureResult
+}
+
+type filterChangedMsg struct {
+	item   *RequestItem
+	filter string
+}
+
+type jsonTreeMsg struct {
+	title string
+	data  interface{}
+}
+
+type copyToClipboardMsg struct {
+	text        string
+	description string // What was copied, for the status message
+}
+
+// importCurlMsg asks for a cURL command to import into a collection
+type importCurlMsg struct {
+	collection string
+}
+
+// collectionsReloadedMsg is broadcast when request files were added or changed on disk
+type collectionsReloadedMsg struct {
+	collections []Collection
+}
+
+// statusMsg flashes a message below the current screen
+type statusMsg struct {
+	text    string
+	isError bool
+}
+
+type listSelectedMsg struct {
+	id   string
+	item ListItem
+}
+
+type textInputSubmittedMsg struct {
+	id    string
+	value string
+}
+
+func emit(msg tea.Msg) tea.Cmd {
+	return func() tea.Msg { return msg }
+}
+
+// Text input ids for cURL imports carry the target collection
+const importCurlInputPrefix = "import-curl:"
+
+// AppModel runs the whole interactive flow in a single program.
+// Screens are kept on a back stack so returning to a previous screen restores its state.
+type AppModel struct {
+	collections   []Collection
+	config        *ConfigJSON
+	secret        *SecretJSON
+	configLoader  *ConfigLoader
+	fileManager   FileManagerInterface
+	utils         UtilsInterface
+	stack         []tea.Model
+	width         int
+	height        int
+	status        string // Flash message shown below the current screen until the next key press
+	statusIsError bool
+	codeItem      *RequestItem // Request waiting for a code generation format to be picked
+	styles        *Styles
+}
+
+func NewAppModel(collections []Collection, config *ConfigJSON, secret *SecretJSON, configLoader *ConfigLoader, fileManager FileManagerInterface, utils UtilsInterface) AppModel {
+	root := NewCollectionsViewModel(collections, config, secret, configLoader, fileManager)
+
+	return AppModel{
+		collections:  collections,
+		config:       config,
+		secret:       secret,
+		configLoader: configLoader,
+		fileManager:  fileManager,
+		utils:        utils,
+		stack:        []tea.Model{root},
+		width:        80,
+		height:       24,
+		styles:       DefaultStyles(),
+	}
+}
+
+func (m AppModel) Init() tea.Cmd {
+	return m.top().Init()
+}
+
+func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m.broadcast(m.screenSize())
+
+	case tea.KeyMsg:
+		m.status = ""
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+
+	case navigateBackMsg:
+		return m.pop()
+
+	case requestSelectedMsg:
+		item := msg.item
+		return m.push(NewRequestPreviewViewModel(&item, m.config, m.secret, m.configLoader))
+
+	case settingSelectedMsg:
+		return m.openSetting(msg.key)
+
+	case editBodyMsg:
+		if msg.item.Request.Body == nil {
+			m.setStatus("⚠️  This request has no body to edit", true)
+			return m, nil
+		}
+		return m.push(NewBodyEditorViewModel(msg.item))
+
+	case bodyEditedMsg:
+		if err := m.applyBodyEdits(msg.item, msg.fields); err != nil {
+			m.setStatus(fmt.Sprintf("⚠️  Failed to save changes: %v", err), true)
+		}
+		return m.pop()
+
+	case executeRequestMsg:
+		return m.executeRequest(msg.item, msg.resolved, msg.replace)
+
+	case requestExecutedMsg:
+		captures, err := m.configLoader.ApplyCaptures(msg.item.Request.Captures, msg.response, m.secret)
+		if err != nil {
+			m.setStatus("⚠️  Failed to save captures: "+err.Error(), true)
+		}
+		msg.captures = captures
+
+		entry, err := m.configLoader.RecordHistory(m.config, msg.item, msg.response)
+		if err != nil {
+			m.setStatus("⚠️  Failed to record history: "+err.Error(), true)
+		}
+		model, cmd := m.broadcast(historyRecordedMsg{entry: entry})
+		model, topCmd := model.(AppModel).updateTop(msg)
+		return model, tea.Batch(cmd, topCmd)
+
+	case historySelectedMsg:
+		item := m.historyItem(msg.entry)
+		return m.push(NewHistoryResponseViewModel(item, msg.entry, m.configLoader.GetRequestFilter(item.RequestPath())))
+
+	case historyResendMsg:
+		if msg.entry.Request == nil {
+			m.setStatus("⚠️  This execution failed before the request was built, nothing to re-send", true)
+			return m, nil
+		}
+		return m.executeRequest(m.historyItem(msg.entry), msg.entry.Request, false)
+
+	case diffResponsesMsg:
+		diff := DiffResponses(msg.before.Response(), msg.after.Response(), DefaultIgnoredDiffHeaders)
+		title := msg.after.Name
+		if msg.before.RequestFile != msg.after.RequestFile {
+			title = msg.before.Name + " → " + msg.after.Name
+		}
+		return m.push(NewDiffViewModel(title, historyEntryLabel(msg.before), historyEntryLabel(msg.after), diff))
+
+	case diffPreviousMsg:
+		return m.diffPrevious(msg.item, msg.response)
+
+	case filterChangedMsg:
+		if err := m.configLoader.SetRequestFilter(msg.item.RequestPath(), msg.filter); err != nil {
+			m.setStatus("⚠️  Failed to save state: "+err.Error(), true)
+		}
+		return m, nil
+
+	case jsonTreeMsg:
+		return m.push(NewJSONTreeViewModel(msg.title, msg.data))
+
+	case showDiagnosticsMsg:
+		return m.push(NewDiagnosticsViewModel(m.configLoader))
+
+	case reloadCollectionsMsg:
+		collections, err := m.configLoader.LoadCollections()
+		if err != nil {
+			m.setStatus("⚠️  Failed to reload collections: "+err.Error(), true)
+			return m, nil
+		}
+		m.collections = collections
+		if diagnostics := m.configLoader.Diagnostics(); len(diagnostics) > 0 {
+			m.setStatus(fmt.Sprintf("⚠️  Reloaded, %s left", pluralize(len(diagnostics), "problem")), true)
+		} else {
+			m.setStatus("✓ Reloaded, no problems left", false)
+		}
+		return m.broadcast(collectionsReloadedMsg{collections: collections})
+
+	case copyToClipboardMsg:
+		if err := m.utils.CopyToClipboard(msg.text); err != nil {
+			m.setStatus(fmt.Sprintf("⚠️  Failed to copy to clipboard: %v", err), true)
+		} else {
+			m.setStatus("✓ Copied "+msg.description, false)
+		}
+		return m, nil
+
+	case statusMsg:
+		m.setStatus(msg.text, msg.isError)
+		return m, nil
+
+	case listSelectedMsg:
+		if msg.id == "environment" {
+			if err := m.switchEnvironment(msg.item.T); err != nil {
+				m.setStatus("Failed to save state: "+err.Error(), true)
+			} else {
+				m.setStatus("✓ Active environment: "+m.configLoader.GetEnvironmentLabel(m.config), false)
+			}
+		}
+		if msg.id == "codegen" {
+			model, cmd := m.pop()
+			return model.(AppModel).generateCode(msg.item.T, cmd)
+		}
+		return m.pop()
+
+	case generateCodeMsg:
+		options := make([]ListItem, len(codeGenerators))
+		for i, generator := range codeGenerators {
+			options[i] = ListItem{T: generator.Name, D: generator.Label}
+		}
+		m.codeItem = msg.item
+		return m.push(NewEmbeddedListViewModel("codegen", "Generate code for "+msg.item.Name, options, 20))
+
+	case importCurlMsg:
+		screen := NewEmbeddedTextInputViewModel(importCurlInputPrefix+msg.collection, fmt.Sprintf("Paste a cURL command to import into %s", msg.collection), "")
+		screen.textInput.CharLimit = 0
+		return m.push(screen)
+
+	case textInputSubmittedMsg:
+		if collection, ok := strings.CutPrefix(msg.id, importCurlInputPrefix); ok {
+			model, cmd := m.pop()
+			if msg.value == "" {
+				return model, cmd
+			}
+			return model.(AppModel).importCurl(collection, msg.value)
+		}
+		if msg.value != "" {
+			if err := m.applySetting(msg.id, msg.value); err != nil {
+				m.setStatus(err.Error(), true)
+			} else {
+				m.setStatus("✓ Settings updated successfully!", false)
+			}
+		}
+		return m.pop()
+	}
+
+	return m.updateTop(msg)
+}
+
+func (m AppModel) View() string {
+	view := m.top().View()
+	if m.status != "" {
+		color := m.styles.AquamarineColor
+		if m.statusIsError {
+			color = m.styles.ErrorColor
+		}
+		view = strings.TrimRight(view, "\n") + "\n\n" + m.styles.Text("  "+m.status, color) + "\n"
+	}
+	return view
+}
+
+func (m AppModel) top() tea.Model {
+	return m.stack[len(m.stack)-1]
+}
+
+func (m AppModel) updateTop(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.stack[len(m.stack)-1], cmd = m.top().Update(msg)
+	return m, cmd
+}
+
+// screenSize is the space left to screens, keeping room for the status line
+func (m AppModel) screenSize() tea.WindowSizeMsg {
+	return tea.WindowSizeMsg{Width: m.width, Height: max(m.height-2, 1)}
+}
+
+// broadcast sends a message to every screen on the stack, not only the visible one
+func (m AppModel) broadcast(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmds []tea.Cmd
+	for i := range m.stack {
+		var cmd tea.Cmd
+		m.stack[i], cmd = m.stack[i].Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m AppModel) push(screen tea.Model) (tea.Model, tea.Cmd) {
+	screen, sizeCmd := screen.Update(m.screenSize())
+	m.stack = append(m.stack, screen)
+	return m, tea.Batch(sizeCmd, screen.Init())
+}
+
+// replace swaps the current screen, keeping the rest of the stack
+func (m AppModel) replace(screen tea.Model) (tea.Model, tea.Cmd) {
+	m.stack = m.stack[:len(m.stack)-1]
+	return m.push(screen)
+}
+
+func (m AppModel) pop() (tea.Model, tea.Cmd) {
+	if len(m.stack) == 1 {
+		return m, tea.Quit
+	}
+	m.stack = m.stack[:len(m.stack)-1]
+	return m, nil
+}
+
+func (m *AppModel) setStatus(status string, isError bool) {
+	m.status = status
+	m.statusIsError = isError
+}
+
+// generateCode resolves the request picked for code generation and shows the snippet
+func (m AppModel) generateCode(format string, cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	item := m.codeItem
+	m.codeItem = nil
+	generator, ok := findCodeGenerator(format)
+	if item == nil || !ok {
+		return m, cmd
+	}
+
+	resolved, err := NewHTTPClient(m.config, m.secret, m.configLoader).ResolveRequest(item.Request, item.Variables)
+	if err != nil {
+		m.setStatus("⚠️  Failed to resolve variables: "+err.Error(), true)
+		return m, cmd
+	}
+	code, err := generator.Generate(resolved)
+	if err != nil {
+		m.setStatus("⚠️  Code generation failed: "+err.Error(), true)
+		return m, cmd
+	}
+	return m.push(NewCodeViewModel(item.Name, generator.Label, code))
+}
+
+// executeRequest resolves variables synchronously and sends the request in the background.
+// A non-nil resolved request (from history) is sent as recorded, with current credentials.
+func (m AppModel) executeRequest(item *RequestItem, resolved *ResolvedRequest, replace bool) (tea.Model, tea.Cmd) {
+	screen := NewResponseViewModel(item, m.configLoader.GetRequestFilter(item.RequestPath()))
+	screen.resolved = resolved
+
+	var model tea.Model
+	var cmd tea.Cmd
+	if replace {
+		model, cmd = m.replace(screen)
+	} else {
+		model, cmd = m.push(screen)
+	}
+
+	httpClient := NewHTTPClient(m.config, m.secret, m.configLoader)
+	if resolved == nil {
+		var err error
+		resolved, err = httpClient.ResolveRequest(item.Request, item.Variables)
+		if err != nil {
+			response := &HTTPResponse{Error: fmt.Errorf("failed to resolve variables: %v", err)}
+			return model, tea.Batch(cmd, emit(requestExecutedMsg{item: item, response: response}))
+		}
+	} else {
+		// Requests re-sent from history are sent with the current credentials
+		resolved = httpClient.RestoreCredentials(resolved, item)
+	}
+
+	send := func() tea.Msg {
+		response, _ := httpClient.ExecuteResolved(resolved)
+		return requestExecutedMsg{item: item, response: response}
+	}
+
+	return model, tea.Batch(cmd, send)
+}
+
+// historyItem finds the request a history entry was recorded for, so captures and filters still apply.
+// Requests that no longer exist are rebuilt from the entry.
+func (m AppModel) historyItem(entry HistoryEntryJSON) *RequestItem {
+	for _, collection := range m.collections {
+		for _, item := range collection.Requests {
+			if m.configLoader.RequestKey(item.RequestPath()) == entry.RequestFile {
+				return &item
+			}
+		}
+	}
+
+	request := &RequestJSON{Name: entry.Name}
+	if entry.Request != nil {
+		request.Method = entry.Request.Method
+		request.URL = entry.Request.URL
+	}
+	requestFile, section, _ := strings.Cut(entry.RequestFile, "#")
+	return &RequestItem{
+		Name:     entry.Name,
+		FileName: filepath.Base(requestFile),
+		FilePath: m.configLoader.RequestFilePath(requestFile),
+		Section:  section,
+		Request:  request,
+	}
+}
+
+// importCurl saves a pasted cURL command into a collection and opens the new request
+func (m AppModel) importCurl(collection, command string) (tea.Model, tea.Cmd) {
+	request, filePath, warnings, err := m.configLoader.ImportCurl(m.config, collection, "", command)
+	if err != nil {
+		m.setStatus("⚠️  Import failed: "+err.Error(), true)
+		return m, nil
+	}
+
+	collections, err := m.configLoader.LoadCollections()
+	if err != nil {
+		m.setStatus("⚠️  Imported, but failed to reload collections: "+err.Error(), true)
+		return m, nil
+	}
+	m.collections = collections
+
+	status := fmt.Sprintf("✓ Imported %s into %s", request.Name, collection)
+	if len(warnings) > 0 {
+		status += " (" + strings.Join(warnings, "; ") + ")"
+	}
+	m.setStatus(status, false)
+
+	model, cmd := m.broadcast(collectionsReloadedMsg{collections: collections})
+	for _, c := range collections {
+		for _, item := range c.Requests {
+			if item.FilePath == filePath {
+				model, pushCmd := model.(AppModel).push(NewRequestPreviewViewModel(&item, m.config, m.secret, m.configLoader))
+				return model, tea.Batch(cmd, pushCmd)
+			}
+		}
+	}
+	return model, cmd
+}
+
+// diffPrevious compares a response with the previous recorded execution of the same request
+func (m AppModel) diffPrevious(item *RequestItem, response *HTTPResponse) (tea.Model, tea.Cmd) {
+	entries, err := m.configLoader.LoadHistory(m.config)
+	if err != nil {
+		m.setStatus("⚠️  Failed to load history: "+err.Error(), true)
+		return m, nil
+	}
+
+	startedAt := response.StartedAt
+	if startedAt.IsZero() {
+		startedAt = time.Now()
+	}
+	requestFile := m.configLoader.RequestKey(item.RequestPath())
+	previous, ok := PreviousHistoryEntry(entries, requestFile, startedAt)
+	if !ok {
+		m.setStatus("⚠️  No previous run of this request in history to compare with", true)
+		return m, nil
+	}
+
+	// Label the response with its own history entry when it was recorded
+	current := NewHistoryEntry(requestFile, item.Name, m.configLoader.GetEnvironmentLabel(m.config), response)
+	for _, entry := range entries {
+		if entry.RequestFile == requestFile && entry.Timestamp.Equal(startedAt) {
+			current = entry
+		}
+	}
+	diff := DiffResponses(previous.Response(), response, DefaultIgnoredDiffHeaders)
+	return m.push(NewDiffViewModel(item.Name, historyEntryLabel(previous), historyEntryLabel(current), diff))
+}
+
+// applyBodyEdits writes edited top-level body fields back into the request file
+func (m AppModel) applyBodyEdits(item *RequestItem, fields []BodyField) error {
+	bodyMap, ok := item.Request.Body.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	for _, field := range fields {
+		// Untouched fields keep their value, so their type ("007" stays a string) and formatting do not change
+		if original, ok := bodyMap[field.Key]; ok && bodyFieldValue(original) == field.Value {
+			continue
+		}
+
+		// Try to parse as number
+		var parsedValue interface{}
+		if numValue, err := strconv.ParseFloat(field.Value, 64); err == nil {
+			parsedValue = numValue
+		} else if field.Value == "true" || field.Value == "false" {
+			parsedValue = (field.Value == "true")
+		} else {
+			parsedValue = field.Value
+		}
+
+		bodyMap[field.Key] = parsedValue
+	}
+
+	// Create a NEW map to ensure no reference issues
+	newBodyMap := make(map[string]interface{})
+	for k, v := range bodyMap {
+		newBodyMap[k] = v
+	}
+	item.Request.Body = newBodyMap
+
+	// Requests of .http files only have their body rewritten
+	if item.Section != "" {
+		return m.configLoader.SaveHTTPFileBody(item)
+	}
+
+	// Save changes to file
+	if err := m.fileManager.SaveRequestJSON(item.FilePath, item.Request); err != nil {
+		return err
+	}
+
+	// Reload the request from file to ensure consistency
+	content, err := m.fileManager.ReadFileContent(item.FilePath)
+	if err == nil {
+		reloadedRequest, err := ParseDocumentContent[RequestJSON](content, IsYAMLFile(item.FilePath))
+		if err == nil {
+			*item.Request = *reloadedRequest
+		}
+	}
+
+	return nil
+}
+
+func (m AppModel) openSetting(settingKey string) (tea.Model, tea.Cmd) {
+	var currentValue string
+	var prompt string
+
+	switch settingKey {
+	case "environment":
+		names := m.config.GetEnvironmentNames()
+		if len(names) == 0 {
+			m.setStatus("⚠️  No environments configured in config.json", true)
+			return m, nil
+		}
+
+		options := []ListItem{
+			{T: DefaultEnvironment, D: m.config.BaseUrl},
+		}
+		for _, name := range names {
+			env := m.config.Environments[name]
+			baseUrl := env.BaseUrl
+			if baseUrl == "" {
+				baseUrl = m.config.BaseUrl
+			}
+			options = append(options, ListItem{T: name, D: baseUrl})
+		}
+		return m.push(NewEmbeddedListViewModel("environment", "Select environment", options, 14))
+
+	case "baseUrl":
+		currentValue = m.configLoader.GetBaseURL(m.config)
+		prompt = fmt.Sprintf("Current Base URL: %s\nEnter new Base URL (or press ESC to cancel):", currentValue)
+	case "jwt":
+		currentValue = m.configLoader.GetJWT(m.secret)
+		if currentValue != "" {
+			prompt = fmt.Sprintf("Current JWT: %s...\nEnter new JWT token (field starts empty, paste new token):", currentValue[:min(20, len(currentValue))])
+		} else {
+			prompt = "Enter JWT token (or press ESC to cancel):"
+		}
+		// For JWT, start with empty field (easier to paste new token)
+		currentValue = ""
+	case "timeout":
+		currentValue = fmt.Sprintf("%d", m.config.GetTimeout())
+		prompt = fmt.Sprintf("Current Timeout: %s seconds\nEnter new timeout in seconds (or press ESC to cancel):", currentValue)
+	default:
+		return m, nil
+	}
+
+	return m.push(NewEmbeddedTextInputViewModel(settingKey, prompt, currentValue))
+}
+
+// applySetting updates config.json or secret.json with a value entered on the settings page
+func (m AppModel) applySetting(settingKey, newValue string) error {
+	switch settingKey {
+	case "baseUrl":
+		// Edit the active environment's baseUrl when it defines one
+		if env := m.config.GetActiveEnvironment(); env != nil && env.BaseUrl != "" {
+			env.BaseUrl = newValue
+			m.config.Environments[m.config.ActiveEnvironment] = *env
+		} else {
+			m.config.BaseUrl = newValue
+		}
+	case "jwt":
+		m.secret.JWT = strings.TrimSpace(newValue)
+		// Save secret.json
+		secretJSON, err := ToJSON(m.secret)
+		if err != nil {
+			return fmt.Errorf("Failed to serialize secret: %v", err)
+		}
+		if err := m.fileManager.WriteSecretContent(secretJSON); err != nil {
+			return fmt.Errorf("Failed to save secret: %v", err)
+		}
+		return nil
+	case "timeout":
+		// Parse timeout
+		var timeout int
+		fmt.Sscanf(newValue, "%d", &timeout)
+		if timeout > 0 {
+			m.config.Timeout = timeout
+		}
+	default:
+		return nil
+	}
+
+	// Save config if baseUrl or timeout changed
+	if err := m.configLoader.SaveConfigJSON(m.config); err != nil {
+		return fmt.Errorf("Failed to save config: %v", err)
+	}
+
+	return nil
+}
+
+func (m AppModel) switchEnvironment(name string) error {
+	if name == DefaultEnvironment {
+		name = ""
+	}
+	return m.configLoader.SetActiveEnvironment(m.config, name)
+}
+
+func AppView(collections []Collection, config *ConfigJSON, secret *SecretJSON, configLoader *ConfigLoader, fileManager FileManagerInterface, utils UtilsInterface) {
+	m := NewAppModel(collections, config, secret, configLoader, fileManager, utils)
+
+	if _, err := tea.NewProgram(m, tea.WithAltScreen()).Run(); err != nil {
+		fmt.Println("AppView -> ", err)
+		os.Exit(1)
+	}
+}
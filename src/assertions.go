@@ -0,0 +1,211 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+type AssertionResult struct {
+	Description string `json:"description"`
+	Passed      bool   `json:"passed"`
+	Expected    string `json:"expected"`
+	Actual      string `json:"actual"`
+}
+
+// EvaluateAssertions checks a response against every expectation of a request
+func EvaluateAssertions(assertions *AssertionsJSON, response *HTTPResponse) []AssertionResult {
+	if assertions == nil {
+		return nil
+	}
+
+	var results []AssertionResult
+
+	if assertions.Status != 0 {
+		results = append(results, AssertionResult{
+			Description: fmt.Sprintf("status == %d", assertions.Status),
+			Passed:      response.StatusCode == assertions.Status,
+			Expected:    fmt.Sprintf("%d", assertions.Status),
+			Actual:      fmt.Sprintf("%d", response.StatusCode),
+		})
+	}
+
+	headers := http.Header(response.Headers)
+
+	for _, name := range sortedStringKeys(assertions.Headers) {
+		expected := assertions.Headers[name]
+		actual := headers.Get(name)
+		results = append(results, AssertionResult{
+			Description: fmt.Sprintf("header %s == %s", name, expected),
+			Passed:      actual == expected,
+			Expected:    expected,
+			Actual:      describeHeader(headers, name),
+		})
+	}
+
+	for _, name := range sortedStringKeys(assertions.HeadersMatch) {
+		pattern := assertions.HeadersMatch[name]
+		result := AssertionResult{
+			Description: fmt.Sprintf("header %s matches %s", name, pattern),
+			Expected:    pattern,
+			Actual:      describeHeader(headers, name),
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			result.Actual = fmt.Sprintf("invalid regular expression: %v", err)
+		} else {
+			result.Passed = len(headers.Values(name)) > 0 && re.MatchString(headers.Get(name))
+		}
+		results = append(results, result)
+	}
+
+	for _, assertion := range assertions.JSON {
+		results = append(results, evaluateJSONAssertion(assertion, response)...)
+	}
+
+	if assertions.MaxDurationMs > 0 {
+		limit := time.Duration(assertions.MaxDurationMs) * time.Millisecond
+		results = append(results, AssertionResult{
+			Description: fmt.Sprintf("duration < %s", limit),
+			Passed:      response.Duration < limit,
+			Expected:    fmt.Sprintf("< %s", limit),
+			Actual:      response.Duration.Round(time.Millisecond).String(),
+		})
+	}
+
+	for _, text := range assertions.BodyContains {
+		results = append(results, AssertionResult{
+			Description: fmt.Sprintf("body contains %q", text),
+			Passed:      strings.Contains(response.BodyString, text),
+			Expected:    text,
+			Actual:      truncateForDisplay(response.BodyString, 120),
+		})
+	}
+
+	return results
+}
+
+func evaluateJSONAssertion(assertion JSONAssertionJSON, response *HTTPResponse) []AssertionResult {
+	var results []AssertionResult
+
+	fail := func(description, expected, actual string) []AssertionResult {
+		return []AssertionResult{{Description: description, Expected: expected, Actual: actual}}
+	}
+
+	if !response.IsJSON {
+		return fail(fmt.Sprintf("%s is valid", assertion.Path), "JSON body", "body is not JSON")
+	}
+
+	value, found, err := QueryJSONPath(response.BodyJSON, assertion.Path)
+	if err != nil {
+		return fail(assertion.Path, "valid JSONPath", err.Error())
+	}
+
+	// A bare path asserts that it exists
+	exists := assertion.Exists
+	if exists == nil && assertion.Type == "" && len(assertion.Equals) == 0 {
+		implicit := true
+		exists = &implicit
+	}
+
+	if exists != nil {
+		description := fmt.Sprintf("%s exists", assertion.Path)
+		if !*exists {
+			description = fmt.Sprintf("%s does not exist", assertion.Path)
+		}
+		results = append(results, AssertionResult{
+			Description: description,
+			Passed:      found == *exists,
+			Expected:    fmt.Sprintf("exists: %t", *exists),
+			Actual:      fmt.Sprintf("exists: %t", found),
+		})
+	}
+
+	if assertion.Type != "" {
+		actual := "missing"
+		if found {
+			actual = jsonTypeName(value)
+		}
+		results = append(results, AssertionResult{
+			Description: fmt.Sprintf("%s is %s", assertion.Path, assertion.Type),
+			Passed:      found && actual == assertion.Type,
+			Expected:    assertion.Type,
+			Actual:      actual,
+		})
+	}
+
+	if len(assertion.Equals) > 0 {
+		var expected interface{}
+		result := AssertionResult{
+			Description: fmt.Sprintf("%s == %s", assertion.Path, string(assertion.Equals)),
+			Expected:    string(assertion.Equals),
+			Actual:      "missing",
+		}
+		if err := json.Unmarshal(assertion.Equals, &expected); err != nil {
+			result.Actual = fmt.Sprintf("invalid expected value: %v", err)
+		} else if found {
+			result.Actual = compactJSON(value)
+			result.Passed = reflect.DeepEqual(expected, value)
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func describeHeader(headers http.Header, name string) string {
+	values := headers.Values(name)
+	if len(values) == 0 {
+		return "(missing)"
+	}
+	return strings.Join(values, ", ")
+}
+
+func compactJSON(value interface{}) string {
+	text, err := ToCompactJSON(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return text
+}
+
+func truncateForDisplay(text string, limit int) string {
+	text = strings.TrimSpace(text)
+	if len(text) <= limit {
+		return text
+	}
+	return text[:limit] + "..."
+}
+
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,122 @@
+package src
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEvaluateAssertions(t *testing.T) {
+	const body = `{"id": 7, "name": "Ada", "tags": ["a"], "owner": null}`
+	response := &HTTPResponse{
+		StatusCode: 201,
+		Headers:    map[string][]string{"Content-Type": {"application/json; charset=utf-8"}, "X-Request-Id": {"r1", "r2"}},
+		BodyString: body,
+		BodyJSON:   decodeTestJSON(t, body),
+		IsJSON:     true,
+		Duration:   120 * time.Millisecond,
+	}
+	yes, no := true, false
+
+	tests := []struct {
+		name       string
+		assertions *AssertionsJSON
+		response   *HTTPResponse // The JSON response above when nil
+		want       []AssertionResult
+	}{
+		{name: "no assertions"},
+		{
+			name:       "status",
+			assertions: &AssertionsJSON{Status: 200},
+			want:       []AssertionResult{{Description: "status == 200", Expected: "200", Actual: "201"}},
+		},
+		{
+			name: "headers are sorted and matched case-insensitively",
+			assertions: &AssertionsJSON{
+				Headers:      map[string]string{"x-request-id": "r1", "ETag": "v1"},
+				HeadersMatch: map[string]string{"content-type": "^application/json", "X-Missing": ".*", "X-Request-Id": "["},
+			},
+			want: []AssertionResult{
+				{Description: "header ETag == v1", Expected: "v1", Actual: "(missing)"},
+				{Description: "header x-request-id == r1", Passed: true, Expected: "r1", Actual: "r1, r2"},
+				{Description: "header X-Missing matches .*", Expected: ".*", Actual: "(missing)"},
+				{Description: "header X-Request-Id matches [", Expected: "[", Actual: "invalid regular expression: error parsing regexp: missing closing ]: `[`"},
+				{Description: "header content-type matches ^application/json", Passed: true, Expected: "^application/json", Actual: "application/json; charset=utf-8"},
+			},
+		},
+		{
+			name: "JSON paths",
+			assertions: &AssertionsJSON{JSON: []JSONAssertionJSON{
+				{Path: "$.id", Equals: json.RawMessage(`7`), Type: "number"},
+				{Path: "$.tags", Equals: json.RawMessage(`["a"]`)},
+				{Path: "$.name", Equals: json.RawMessage(`"Grace"`)},
+				{Path: "$.owner"},
+				{Path: "$.deleted", Exists: &no},
+				{Path: "$.missing", Exists: &yes, Type: "string"},
+				{Path: "$.id", Equals: json.RawMessage(`{`)},
+			}},
+			want: []AssertionResult{
+				{Description: "$.id is number", Passed: true, Expected: "number", Actual: "number"},
+				{Description: "$.id == 7", Passed: true, Expected: "7", Actual: "7"},
+				{Description: `$.tags == ["a"]`, Passed: true, Expected: `["a"]`, Actual: `["a"]`},
+				{Description: `$.name == "Grace"`, Expected: `"Grace"`, Actual: `"Ada"`},
+				{Description: "$.owner exists", Passed: true, Expected: "exists: true", Actual: "exists: true"},
+				{Description: "$.deleted does not exist", Passed: true, Expected: "exists: false", Actual: "exists: false"},
+				{Description: "$.missing exists", Expected: "exists: true", Actual: "exists: false"},
+				{Description: "$.missing is string", Expected: "string", Actual: "missing"},
+				{Description: "$.id == {", Expected: "{", Actual: "invalid expected value: unexpected end of JSON input"},
+			},
+		},
+		{
+			name:       "JSON paths on a body that is not JSON",
+			assertions: &AssertionsJSON{JSON: []JSONAssertionJSON{{Path: "$.id"}}},
+			response:   &HTTPResponse{BodyString: "<html>"},
+			want:       []AssertionResult{{Description: "$.id is valid", Expected: "JSON body", Actual: "body is not JSON"}},
+		},
+		{
+			name:       "duration and body",
+			assertions: &AssertionsJSON{MaxDurationMs: 100, BodyContains: []string{`"Ada"`, "Grace"}},
+			want: []AssertionResult{
+				{Description: "duration < 100ms", Expected: "< 100ms", Actual: "120ms"},
+				{Description: `body contains "\"Ada\""`, Passed: true, Expected: `"Ada"`, Actual: body},
+				{Description: `body contains "Grace"`, Expected: "Grace", Actual: body},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := tt.response
+			if target == nil {
+				target = response
+			}
+			got := EvaluateAssertions(tt.assertions, target)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONTypeName(t *testing.T) {
+	tests := []struct {
+		json string
+		want string
+	}{
+		{json: `null`, want: "null"},
+		{json: `true`, want: "boolean"},
+		{json: `1.5`, want: "number"},
+		{json: `"a"`, want: "string"},
+		{json: `[1]`, want: "array"},
+		{json: `{"a": 1}`, want: "object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.json, func(t *testing.T) {
+			if got := jsonTypeName(decodeTestJSON(t, tt.json)); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
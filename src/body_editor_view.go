@@ -3,7 +3,7 @@ package src
 import (
 	"encoding/json"
 	"fmt"
-	"os"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -12,6 +12,7 @@ import (
 )
 
 type BodyEditorViewModel struct {
+	item          *RequestItem
 	bodyFields    []BodyField
 	cursor        int
 	viewportStart int
@@ -19,8 +20,6 @@ type BodyEditorViewModel struct {
 	editMode      bool
 	textInput     textinput.Model
 	editingIndex  int
-	selected      *string
-	quitting      bool
 	styles        *Styles
 }
 
@@ -29,31 +28,33 @@ type BodyField struct {
 	Value string
 }
 
-func NewBodyEditorViewModel(body interface{}) BodyEditorViewModel {
+// bodyFieldValue converts a body value to the text edited in the body editor
+func bodyFieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return fmt.Sprintf("%t", v)
+	default:
+		jsonBytes, _ := json.Marshal(v)
+		return string(jsonBytes)
+	}
+}
+
+func NewBodyEditorViewModel(item *RequestItem) BodyEditorViewModel {
 	var fields []BodyField
+	body := item.Request.Body
 
 	// Convert body to map
 	if body != nil {
 		bodyMap, ok := body.(map[string]interface{})
 		if ok {
 			for key, value := range bodyMap {
-				// Convert value to string
-				var valueStr string
-				switch v := value.(type) {
-				case string:
-					valueStr = v
-				case float64:
-					valueStr = fmt.Sprintf("%.0f", v)
-				case bool:
-					valueStr = fmt.Sprintf("%t", v)
-				default:
-					jsonBytes, _ := json.Marshal(v)
-					valueStr = string(jsonBytes)
-				}
-
 				fields = append(fields, BodyField{
 					Key:   key,
-					Value: valueStr,
+					Value: bodyFieldValue(value),
 				})
 			}
 		}
@@ -64,6 +65,7 @@ func NewBodyEditorViewModel(body interface{}) BodyEditorViewModel {
 	ti.Width = 60
 
 	return BodyEditorViewModel{
+		item:          item,
 		bodyFields:    fields,
 		cursor:        0,
 		viewportStart: 0,
@@ -71,7 +73,6 @@ func NewBodyEditorViewModel(body interface{}) BodyEditorViewModel {
 		editMode:      false,
 		textInput:     ti,
 		editingIndex:  -1,
-		quitting:      false,
 		styles:        DefaultStyles(),
 	}
 }
@@ -113,23 +114,14 @@ func (m BodyEditorViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "ctrl+c", "q":
-			*m.selected = ExitSignal
-			m.quitting = true
-			return m, tea.Quit
+		case "q":
+			// Discard changes
+			return m, emit(navigateBackMsg{})
 
 		case "esc":
 			// Save and exit
-			result := ""
-			for i, field := range m.bodyFields {
-				if i > 0 {
-					result += "||"
-				}
-				result += field.Key + "|" + field.Value
-			}
-			*m.selected = result
-			m.quitting = true
-			return m, tea.Quit
+			fields := append([]BodyField{}, m.bodyFields...)
+			return m, emit(bodyEditedMsg{item: m.item, fields: fields})
 
 		case "up", "k":
 			if m.cursor > 0 {
@@ -163,10 +155,6 @@ func (m BodyEditorViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m BodyEditorViewModel) View() string {
-	if m.quitting {
-		return ""
-	}
-
 	var view string
 
 	view += "\n"
@@ -218,13 +206,3 @@ func (m BodyEditorViewModel) View() string {
 
 	return view
 }
-
-func BodyEditorView(body interface{}, selected *string) {
-	m := NewBodyEditorViewModel(body)
-	m.selected = selected
-
-	if _, err := tea.NewProgram(m).Run(); err != nil {
-		fmt.Println("BodyEditorView -> ", err)
-		os.Exit(1)
-	}
-}
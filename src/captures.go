@@ -0,0 +1,153 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	// CaptureJWTTarget is the reserved capture name that updates secret.json
+	CaptureJWTTarget = "jwt"
+
+	captureHeaderPrefix = "header:"
+)
+
+type CaptureResult struct {
+	Name    string
+	From    string
+	Value   string
+	Persist bool
+	Error   error
+}
+
+// UnmarshalJSON accepts the short string form as well as the object form
+func (c *CaptureJSON) UnmarshalJSON(data []byte) error {
+	var from string
+	if err := json.Unmarshal(data, &from); err == nil {
+		c.From = from
+		c.Persist = false
+		return nil
+	}
+
+	type captureAlias CaptureJSON
+	var alias captureAlias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return fmt.Errorf("capture must be a string or an object with \"from\": %v", err)
+	}
+	*c = CaptureJSON(alias)
+	return nil
+}
+
+// MarshalJSON writes the short string form back when possible
+func (c CaptureJSON) MarshalJSON() ([]byte, error) {
+	if !c.Persist {
+		return json.Marshal(c.From)
+	}
+	type captureAlias CaptureJSON
+	return json.Marshal(captureAlias(c))
+}
+
+// ExtractCapture reads a single capture source from a response
+func ExtractCapture(from string, response *HTTPResponse) (string, error) {
+	from = strings.TrimSpace(from)
+
+	if strings.HasPrefix(from, captureHeaderPrefix) {
+		name := strings.TrimSpace(strings.TrimPrefix(from, captureHeaderPrefix))
+		value := http.Header(response.Headers).Get(name)
+		if value == "" {
+			return "", fmt.Errorf("header %s not found in response", name)
+		}
+		return value, nil
+	}
+
+	if !response.IsJSON {
+		return "", fmt.Errorf("response body is not JSON")
+	}
+
+	value, found, err := QueryJSONPath(response.BodyJSON, from)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("%s not found in response body", from)
+	}
+
+	return captureValueString(value), nil
+}
+
+func captureValueString(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(jsonBytes)
+}
+
+// ApplyCaptures stores captured values as session variables, persisted variables in state.json,
+// or the JWT in secret.json for the reserved "jwt" name. Failed requests and non-2xx responses
+// capture nothing, so an error body never replaces a token.
+func (cl *ConfigLoader) ApplyCaptures(captures map[string]CaptureJSON, response *HTTPResponse, secret *SecretJSON) ([]CaptureResult, error) {
+	if len(captures) == 0 || response == nil || response.Error != nil || response.StatusCode < 200 || response.StatusCode > 299 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(captures))
+	for name := range captures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var results []CaptureResult
+	persistState := false
+	persistSecret := false
+
+	for _, name := range names {
+		capture := captures[name]
+		result := CaptureResult{Name: name, From: capture.From, Persist: capture.Persist}
+
+		value, err := ExtractCapture(capture.From, response)
+		if err != nil {
+			result.Error = err
+			results = append(results, result)
+			continue
+		}
+		result.Value = value
+
+		switch {
+		case name == CaptureJWTTarget && secret != nil:
+			secret.JWT = strings.TrimSpace(value)
+			persistSecret = true
+		case capture.Persist:
+			cl.SetPersistedVariable(name, value)
+			persistState = true
+		default:
+			cl.SetSessionVariable(name, value)
+		}
+
+		results = append(results, result)
+	}
+
+	if persistSecret {
+		content, err := ToJSON(secret)
+		if err != nil {
+			return results, fmt.Errorf("ApplyCaptures -> %v", err)
+		}
+		if err := cl.fileManager.WriteSecretContent(content); err != nil {
+			return results, fmt.Errorf("ApplyCaptures -> %v", err)
+		}
+	}
+
+	if persistState {
+		if err := cl.SaveStateJSON(cl.state); err != nil {
+			return results, fmt.Errorf("ApplyCaptures -> %v", err)
+		}
+	}
+
+	return results, nil
+}
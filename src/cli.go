@@ -0,0 +1,265 @@
+package src
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	ExitCodeOK      = 0
+	ExitCodeError   = 1 // Usage, configuration or transport errors
+	ExitCodeFailure = 2 // The request ran but its result was not accepted
+)
+
+const cliUsage = `Usage:
+  postless                         Open the interactive TUI
+  postless run <request> [flags]   Execute a saved request and print the response
+  postless test [collection...]    Run requests in order and check their assertions
+  postless lint [collection...]    List request files that fail to load or look wrong
+  postless diff <request> [flags]  Compare a fresh response with the last recorded one
+  postless import curl [flags]     Save a cURL command as a request
+  postless import postman <file>   Convert Postman collections and environments
+  postless import openapi <spec>   Generate requests from an OpenAPI 3 document
+  postless import har <file>       Turn entries of a browser HAR capture into requests
+  postless export <fmt> <request>  Print a request as curl, go, python, js or httpie code
+  postless export openapi|md|html  Document collections with sample responses
+  postless export har [request...] Write recorded executions as a HAR archive
+  postless export http <name>      Write a collection as a REST Client .http file
+
+Requests are referenced as <collection>/<request name or file name>, or by file path.
+
+Run "postless <command> -h" for the flags of a command.
+`
+
+// RunCommand executes a non-interactive subcommand and returns the process exit code
+func (r *Runner) RunCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, cliUsage)
+		return ExitCodeError
+	}
+
+	switch args[0] {
+	case "run":
+		return r.runCommand(args[1:])
+	case "test":
+		return r.testCommand(args[1:])
+	case "diff":
+		return r.diffCommand(args[1:])
+	case "lint":
+		return r.lintCommand(args[1:])
+	case "import":
+		return r.importCommand(args[1:])
+	case "export":
+		return r.exportCommand(args[1:])
+	case "help", "-h", "--help":
+		fmt.Print(cliUsage)
+		return ExitCodeOK
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", args[0], cliUsage)
+		return ExitCodeError
+	}
+}
+
+// parseCommandFlags parses flags that may appear before or after positional arguments
+func parseCommandFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func newCommandFlagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: postless %s\n\nFlags:\n", usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// loadProjectForCommand loads the project and applies a --env override
+func (r *Runner) loadProjectForCommand(environment string) ([]Collection, error) {
+	collections, err := r.loadProject()
+	if err != nil {
+		return nil, err
+	}
+
+	if environment != "" {
+		if _, ok := r.config.Environments[environment]; !ok && environment != DefaultEnvironment {
+			return nil, fmt.Errorf("unknown environment %q", environment)
+		}
+		if environment == DefaultEnvironment {
+			environment = ""
+		}
+		r.config.ActiveEnvironment = environment
+	}
+
+	return collections, nil
+}
+
+// findRequest resolves "collection/request" (by name or file name) or a request file path.
+// Requests of .http files are also found by their section: "auth/login" or "auth.http#login".
+func (r *Runner) findRequest(collections []Collection, ref string) (*RequestItem, error) {
+	filePath, section, _ := strings.Cut(ref, "#")
+	if info, err := os.Stat(filePath); err == nil && !info.IsDir() {
+		absRef, _ := filepath.Abs(filePath)
+		var matches []*RequestItem
+		for i := range collections {
+			for j := range collections[i].Requests {
+				item := &collections[i].Requests[j]
+				if absPath, _ := filepath.Abs(item.FilePath); absPath == absRef && (section == "" || item.Section == section) {
+					matches = append(matches, item)
+				}
+			}
+		}
+
+		if len(matches) == 0 {
+			content, err := r.fileManager.ReadFileContent(filePath)
+			if err != nil {
+				return nil, err
+			}
+			if IsHTTPFile(filePath) {
+				for _, item := range httpFileItems(filepath.Base(filePath), filePath, ParseHTTPFile(content), nil) {
+					if section == "" || item.Section == section {
+						matches = append(matches, &item)
+					}
+				}
+			} else {
+				request, err := ParseDocumentContent[RequestJSON](content, IsYAMLFile(filePath))
+				if err != nil {
+					return nil, fmt.Errorf("invalid request file %s: %v", filePath, err)
+				}
+				return &RequestItem{
+					Name:     request.Name,
+					FileName: filepath.Base(filePath),
+					FilePath: filePath,
+					Request:  request,
+				}, nil
+			}
+		}
+
+		switch {
+		case len(matches) == 1:
+			return matches[0], nil
+		case len(matches) == 0 && section != "":
+			return nil, fmt.Errorf("request %q not found in %s", section, filePath)
+		case len(matches) == 0:
+			return nil, fmt.Errorf("no request found in %s", filePath)
+		}
+		var sections []string
+		for _, item := range matches {
+			sections = append(sections, filePath+"#"+item.Section)
+		}
+		return nil, fmt.Errorf("%s holds %s, use one of: %s", filePath, pluralize(len(matches), "request"), strings.Join(sections, ", "))
+	}
+
+	collectionName, requestName, ok := strings.Cut(ref, "/")
+	if !ok {
+		return nil, fmt.Errorf("request %q not found, use <collection>/<request> or a file path", ref)
+	}
+
+	for i := range collections {
+		if collections[i].Name != collectionName {
+			continue
+		}
+		// File paths are unique in a collection, names may repeat across folders
+		var matches []*RequestItem
+		for j := range collections[i].Requests {
+			item := &collections[i].Requests[j]
+			fileName := strings.TrimSuffix(item.FileName, filepath.Ext(item.FileName))
+			if item.Section != "" {
+				if item.FileName+"#"+item.Section == requestName || fileName+"#"+item.Section == requestName {
+					return item, nil
+				}
+				if item.Section == requestName || strings.EqualFold(item.Name, requestName) {
+					matches = append(matches, item)
+				}
+				continue
+			}
+			if item.FileName == requestName || fileName == requestName {
+				return item, nil
+			}
+			if strings.EqualFold(item.Name, requestName) {
+				matches = append(matches, item)
+			}
+		}
+
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("request %q not found in collection %q", requestName, collectionName)
+		case 1:
+			return matches[0], nil
+		}
+		var paths []string
+		for _, item := range matches {
+			path := collectionName + "/" + item.FileName
+			if item.Section != "" {
+				path += "#" + item.Section
+			}
+			paths = append(paths, path)
+		}
+		return nil, fmt.Errorf("%d requests are named %q in collection %q, use one of: %s",
+			len(matches), requestName, collectionName, strings.Join(paths, ", "))
+	}
+
+	return nil, fmt.Errorf("collection %q not found", collectionName)
+}
+
+// statusMatcher checks status codes against a list like "2xx,304,404"
+type statusMatcher struct {
+	exact  map[int]bool
+	ranges map[int]bool // Hundreds digit of "Nxx" entries
+}
+
+func parseStatusMatcher(spec string) (*statusMatcher, error) {
+	matcher := &statusMatcher{exact: map[int]bool{}, ranges: map[int]bool{}}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+
+		if len(part) == 3 && strings.HasSuffix(part, "xx") {
+			digit, err := strconv.Atoi(part[:1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid status %q", part)
+			}
+			matcher.ranges[digit] = true
+			continue
+		}
+
+		code, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status %q", part)
+		}
+		matcher.exact[code] = true
+	}
+
+	return matcher, nil
+}
+
+func (s *statusMatcher) Match(statusCode int) bool {
+	return s.exact[statusCode] || s.ranges[statusCode/100]
+}
+
+func writeRawResponse(w io.Writer, response *HTTPResponse) {
+	w.Write(response.Body)
+	if len(response.Body) > 0 && response.Body[len(response.Body)-1] != '\n' {
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,113 @@
+package src
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// generateCodeMsg asks for a code snippet of a request, the format is picked from a list
+type generateCodeMsg struct {
+	item *RequestItem
+}
+
+// CodeViewModel shows a generated code snippet that can be copied to the clipboard
+type CodeViewModel struct {
+	title  string
+	label  string
+	code   string
+	lines  []responseLine
+	offset int
+	width  int
+	height int
+	styles *Styles
+}
+
+func NewCodeViewModel(title, label, code string) CodeViewModel {
+	m := CodeViewModel{
+		title:  title,
+		label:  label,
+		code:   code,
+		width:  80,
+		height: 24,
+		styles: DefaultStyles(),
+	}
+	m.refresh()
+	return m
+}
+
+func (m CodeViewModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m CodeViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.refresh()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc":
+			return m, emit(navigateBackMsg{})
+		case "c", "y":
+			return m, emit(copyToClipboardMsg{text: m.code, description: m.label + " snippet"})
+		case "up", "k":
+			m.scrollTo(m.offset - 1)
+		case "down", "j":
+			m.scrollTo(m.offset + 1)
+		case "pgup", "b":
+			m.scrollTo(m.offset - m.pageHeight())
+		case "pgdown", " ":
+			m.scrollTo(m.offset + m.pageHeight())
+		case "g", "home":
+			m.scrollTo(0)
+		case "G", "end":
+			m.scrollTo(len(m.lines))
+		}
+	}
+
+	return m, nil
+}
+
+func (m CodeViewModel) View() string {
+	var b strings.Builder
+
+	b.WriteString("\n")
+	b.WriteString(m.styles.Text("  </> "+m.label+": "+m.title, m.styles.SelectedTitleColor))
+	b.WriteString("\n\n")
+
+	end := min(m.offset+m.pageHeight(), len(m.lines))
+	for i := m.offset; i < end; i++ {
+		b.WriteString(m.styles.Text("  "+m.lines[i].text, m.lines[i].color))
+		b.WriteString("\n")
+	}
+	for i := end - m.offset; i < m.pageHeight(); i++ {
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.styles.FooterStyle.Render("  c/y copy • ↑↓/jk/pgup/pgdn scroll • q/esc back"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// pageHeight is the number of code lines that fit between header and footer
+func (m CodeViewModel) pageHeight() int {
+	return max(m.height-6, 3)
+}
+
+func (m *CodeViewModel) scrollTo(offset int) {
+	m.offset = max(0, min(offset, len(m.lines)-m.pageHeight()))
+}
+
+func (m *CodeViewModel) refresh() {
+	var lines []responseLine
+	for _, line := range strings.Split(m.code, "\n") {
+		lines = append(lines, responseLine{text: strings.ReplaceAll(line, "\t", "    "), color: m.styles.FooterColor})
+	}
+	m.lines = wrapResponseLines(lines, max(m.width-4, 20))
+	m.scrollTo(m.offset)
+}
@@ -0,0 +1,341 @@
+package src
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CodeGenerator turns a resolved request into a snippet for another client
+type CodeGenerator struct {
+	Name     string // Used on the command line: postless export <name>
+	Label    string
+	Generate func(*ResolvedRequest) (string, error)
+}
+
+var codeGenerators = []CodeGenerator{
+	{Name: "curl", Label: "cURL", Generate: ExportCurl},
+	{Name: "go", Label: "Go net/http", Generate: GenerateGo},
+	{Name: "python", Label: "Python requests", Generate: GeneratePython},
+	{Name: "js", Label: "JavaScript fetch", Generate: GenerateJavaScript},
+	{Name: "httpie", Label: "HTTPie", Generate: GenerateHTTPie},
+}
+
+func findCodeGenerator(name string) (CodeGenerator, bool) {
+	for _, generator := range codeGenerators {
+		if generator.Name == name || strings.EqualFold(generator.Label, name) {
+			return generator, true
+		}
+	}
+	return CodeGenerator{}, false
+}
+
+func codeGeneratorNames() []string {
+	names := make([]string, len(codeGenerators))
+	for i, generator := range codeGenerators {
+		names[i] = generator.Name
+	}
+	return names
+}
+
+// requestBodyText returns the body as sent: raw text or compact JSON, empty without body
+func requestBodyText(resolved *ResolvedRequest) (string, error) {
+	if resolved.RawBody != "" {
+		return resolved.RawBody, nil
+	}
+	if resolved.Body == nil {
+		return "", nil
+	}
+	return ToCompactJSON(resolved.Body)
+}
+
+// formFields splits form fields into plain values and file uploads ("@path")
+func formFields(form map[string]string) (values, files [][2]string) {
+	for _, name := range sortedStringKeys(form) {
+		if path, ok := strings.CutPrefix(form[name], "@"); ok {
+			files = append(files, [2]string{name, path})
+		} else {
+			values = append(values, [2]string{name, form[name]})
+		}
+	}
+	return values, files
+}
+
+// headerPairs flattens headers in name order
+func headerPairs(headers http.Header) [][2]string {
+	var pairs [][2]string
+	for _, name := range sortedHeaderNames(headers) {
+		for _, value := range headers[name] {
+			pairs = append(pairs, [2]string{name, value})
+		}
+	}
+	return pairs
+}
+
+// GenerateGo emits a standalone Go program using net/http
+func GenerateGo(resolved *ResolvedRequest) (string, error) {
+	imports := map[string]bool{"fmt": true, "io": true, "net/http": true}
+	var b strings.Builder
+
+	body, err := requestBodyText(resolved)
+	if err != nil {
+		return "", err
+	}
+
+	bodyArg := "nil"
+	switch {
+	case len(resolved.Form) > 0:
+		imports["bytes"], imports["mime/multipart"] = true, true
+		values, files := formFields(resolved.Form)
+		b.WriteString("\tvar body bytes.Buffer\n")
+		b.WriteString("\twriter := multipart.NewWriter(&body)\n")
+		for _, field := range values {
+			fmt.Fprintf(&b, "\twriter.WriteField(%s, %s)\n", goString(field[0]), goString(field[1]))
+		}
+		for _, file := range files {
+			imports["os"], imports["path/filepath"] = true, true
+			fmt.Fprintf(&b, "\tif content, err := os.ReadFile(%s); err != nil {\n\t\tpanic(err)\n\t} else {\n", goString(file[1]))
+			fmt.Fprintf(&b, "\t\tpart, _ := writer.CreateFormFile(%s, filepath.Base(%s))\n\t\tpart.Write(content)\n\t}\n", goString(file[0]), goString(file[1]))
+		}
+		b.WriteString("\twriter.Close()\n\n")
+		bodyArg = "&body"
+	case body != "":
+		imports["strings"] = true
+		fmt.Fprintf(&b, "\tbody := strings.NewReader(%s)\n\n", goString(body))
+		bodyArg = "body"
+	}
+
+	fmt.Fprintf(&b, "\treq, err := http.NewRequest(%s, %s, %s)\n", goString(resolved.Method), goString(resolved.URL), bodyArg)
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	for _, header := range headerPairs(resolved.Headers) {
+		fmt.Fprintf(&b, "\treq.Header.Add(%s, %s)\n", goString(header[0]), goString(header[1]))
+	}
+	if len(resolved.Form) > 0 {
+		b.WriteString("\treq.Header.Set(\"Content-Type\", writer.FormDataContentType())\n")
+	}
+	b.WriteString("\n")
+
+	if resolved.Insecure {
+		imports["crypto/tls"] = true
+		b.WriteString("\tclient := &http.Client{Transport: &http.Transport{\n")
+		b.WriteString("\t\tTLSClientConfig: &tls.Config{InsecureSkipVerify: true},\n")
+		b.WriteString("\t}}\n")
+	} else {
+		b.WriteString("\tclient := http.DefaultClient\n")
+	}
+	b.WriteString("\tresp, err := client.Do(req)\n")
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\tdefer resp.Body.Close()\n\n")
+	b.WriteString("\trespBody, err := io.ReadAll(resp.Body)\n")
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\tfmt.Println(resp.Status)\n")
+	b.WriteString("\tfmt.Println(string(respBody))\n")
+
+	var sortedImports []string
+	for name := range imports {
+		sortedImports = append(sortedImports, name)
+	}
+	sort.Strings(sortedImports)
+
+	var program strings.Builder
+	program.WriteString("package main\n\nimport (\n")
+	for _, name := range sortedImports {
+		fmt.Fprintf(&program, "\t%q\n", name)
+	}
+	program.WriteString(")\n\nfunc main() {\n")
+	program.WriteString(b.String())
+	program.WriteString("}")
+	return program.String(), nil
+}
+
+// goString quotes a string as a Go literal, as a raw string when that reads better
+func goString(text string) string {
+	if strings.ContainsAny(text, "\"\\") && !strings.ContainsAny(text, "`\r") {
+		return "`" + text + "`"
+	}
+	return strconv.Quote(text)
+}
+
+// GeneratePython emits a script using the requests library
+func GeneratePython(resolved *ResolvedRequest) (string, error) {
+	var b strings.Builder
+	b.WriteString("import requests\n\n")
+	fmt.Fprintf(&b, "url = %s\n", pythonLiteral(resolved.URL, 0))
+
+	args := []string{"url"}
+	if len(resolved.Headers) > 0 {
+		b.WriteString("headers = {\n")
+		for _, header := range headerPairs(resolved.Headers) {
+			fmt.Fprintf(&b, "    %s: %s,\n", pythonLiteral(header[0], 0), pythonLiteral(header[1], 0))
+		}
+		b.WriteString("}\n")
+		args = append(args, "headers=headers")
+	}
+
+	switch {
+	case len(resolved.Form) > 0:
+		values, files := formFields(resolved.Form)
+		if len(values) > 0 {
+			b.WriteString("data = {\n")
+			for _, field := range values {
+				fmt.Fprintf(&b, "    %s: %s,\n", pythonLiteral(field[0], 0), pythonLiteral(field[1], 0))
+			}
+			b.WriteString("}\n")
+			args = append(args, "data=data")
+		}
+		if len(files) > 0 {
+			b.WriteString("files = {\n")
+			for _, file := range files {
+				fmt.Fprintf(&b, "    %s: open(%s, \"rb\"),\n", pythonLiteral(file[0], 0), pythonLiteral(file[1], 0))
+			}
+			b.WriteString("}\n")
+			args = append(args, "files=files")
+		}
+	case resolved.RawBody != "":
+		fmt.Fprintf(&b, "data = %s\n", pythonLiteral(resolved.RawBody, 0))
+		args = append(args, "data=data")
+	case resolved.Body != nil:
+		fmt.Fprintf(&b, "payload = %s\n", pythonLiteral(resolved.Body, 0))
+		args = append(args, "json=payload")
+	}
+	if resolved.Insecure {
+		args = append(args, "verify=False")
+	}
+
+	b.WriteString("\n")
+	switch resolved.Method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead, http.MethodOptions:
+		fmt.Fprintf(&b, "response = requests.%s(%s)\n", strings.ToLower(resolved.Method), strings.Join(args, ", "))
+	default:
+		fmt.Fprintf(&b, "response = requests.request(%s, %s)\n", pythonLiteral(resolved.Method, 0), strings.Join(args, ", "))
+	}
+	b.WriteString("print(response.status_code)\n")
+	b.WriteString("print(response.text)")
+	return b.String(), nil
+}
+
+// pythonLiteral writes a JSON value as a Python literal (True, False, None, dicts and lists)
+func pythonLiteral(value interface{}, depth int) string {
+	indent := strings.Repeat("    ", depth+1)
+	closing := strings.Repeat("    ", depth)
+
+	switch v := value.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if v {
+			return "True"
+		}
+		return "False"
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return "{}"
+		}
+		var b strings.Builder
+		b.WriteString("{\n")
+		for _, key := range sortedKeys(v) {
+			fmt.Fprintf(&b, "%s%s: %s,\n", indent, compactJSON(key), pythonLiteral(v[key], depth+1))
+		}
+		return b.String() + closing + "}"
+	case []interface{}:
+		if len(v) == 0 {
+			return "[]"
+		}
+		var b strings.Builder
+		b.WriteString("[\n")
+		for _, item := range v {
+			fmt.Fprintf(&b, "%s%s,\n", indent, pythonLiteral(item, depth+1))
+		}
+		return b.String() + closing + "]"
+	}
+	// Strings and numbers are written the same way in JSON and Python
+	return compactJSON(value)
+}
+
+// GenerateJavaScript emits a fetch call for browsers and Node.js 18+
+func GenerateJavaScript(resolved *ResolvedRequest) (string, error) {
+	var b strings.Builder
+	options := []string{"  method: " + compactJSON(resolved.Method) + ","}
+
+	if len(resolved.Headers) > 0 {
+		var headers strings.Builder
+		headers.WriteString("  headers: {\n")
+		for _, header := range headerPairs(resolved.Headers) {
+			fmt.Fprintf(&headers, "    %s: %s,\n", compactJSON(header[0]), compactJSON(header[1]))
+		}
+		headers.WriteString("  },")
+		options = append(options, headers.String())
+	}
+
+	switch {
+	case len(resolved.Form) > 0:
+		values, files := formFields(resolved.Form)
+		if len(files) > 0 {
+			b.WriteString("import { readFile } from \"node:fs/promises\";\n\n")
+		}
+		b.WriteString("const form = new FormData();\n")
+		for _, field := range values {
+			fmt.Fprintf(&b, "form.append(%s, %s);\n", compactJSON(field[0]), compactJSON(field[1]))
+		}
+		for _, file := range files {
+			name := file[1][strings.LastIndexAny(file[1], `/\`)+1:]
+			fmt.Fprintf(&b, "form.append(%s, new Blob([await readFile(%s)]), %s);\n", compactJSON(file[0]), compactJSON(file[1]), compactJSON(name))
+		}
+		b.WriteString("\n")
+		options = append(options, "  body: form,")
+	case resolved.RawBody != "":
+		options = append(options, "  body: "+compactJSON(resolved.RawBody)+",")
+	case resolved.Body != nil:
+		body, err := ToJSON(resolved.Body)
+		if err != nil {
+			return "", err
+		}
+		options = append(options, "  body: JSON.stringify("+strings.ReplaceAll(body, "\n", "\n  ")+"),")
+	}
+
+	if resolved.Insecure {
+		b.WriteString("// fetch cannot skip TLS verification per request; in Node.js run with NODE_TLS_REJECT_UNAUTHORIZED=0\n")
+	}
+	fmt.Fprintf(&b, "const response = await fetch(%s, {\n%s\n});\n\n", compactJSON(resolved.URL), strings.Join(options, "\n"))
+	b.WriteString("console.log(response.status);\n")
+	b.WriteString("console.log(await response.text());")
+	return b.String(), nil
+}
+
+// GenerateHTTPie emits an http command line
+func GenerateHTTPie(resolved *ResolvedRequest) (string, error) {
+	first := "http "
+	if resolved.Insecure {
+		first += "--verify=no "
+	}
+	if len(resolved.Form) > 0 {
+		first += "--form "
+	}
+	parts := []string{first + resolved.Method + " " + shellQuote(resolved.URL)}
+
+	for _, header := range headerPairs(resolved.Headers) {
+		parts = append(parts, shellQuote(header[0]+":"+header[1]))
+	}
+
+	if len(resolved.Form) > 0 {
+		values, files := formFields(resolved.Form)
+		for _, field := range values {
+			parts = append(parts, shellQuote(field[0]+"="+field[1]))
+		}
+		for _, file := range files {
+			parts = append(parts, shellQuote(file[0]+"@"+file[1]))
+		}
+	} else {
+		body, err := requestBodyText(resolved)
+		if err != nil {
+			return "", err
+		}
+		if body != "" {
+			parts = append(parts, "--raw "+shellQuote(body))
+		}
+	}
+
+	return strings.Join(parts, " \\\n  "), nil
+}
@@ -2,7 +2,7 @@ package src
 
 import (
 	"fmt"
-	"os"
+	"path"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -19,18 +19,28 @@ type CollectionsViewModel struct {
 	cursor        int
 	viewportStart int
 	maxVisible    int
-	selected      *string
-	quitting      bool
 	styles        *Styles
 	searchMode    bool
 	searchQuery   string
 	filteredList  []RequestItem
-	totalPages    int // Collections + Settings page
+	totalPages    int // Collections + History + Settings pages
+	history       HistoryViewModel
+	historyLoaded bool
+	expanded      map[string]bool // Open folders, by collection name and folder path
+	width         int
+	height        int
+}
+
+// collectionRow is a line of a page: a request, or a folder of the collection tree
+type collectionRow struct {
+	item   RequestItem
+	folder *Folder
+	depth  int
 }
 
 func NewCollectionsViewModel(collections []Collection, config *ConfigJSON, secret *SecretJSON, configLoader *ConfigLoader, fileManager FileManagerInterface) CollectionsViewModel {
-	// Total pages = collections + 1 (settings page)
-	totalPages := len(collections) + 1
+	// Total pages = collections + history page + settings page
+	totalPages := len(collections) + 2
 
 	m := CollectionsViewModel{
 		collections:   collections,
@@ -42,12 +52,14 @@ func NewCollectionsViewModel(collections []Collection, config *ConfigJSON, secre
 		cursor:        0,
 		viewportStart: 0,
 		maxVisible:    10,
-		quitting:      false,
 		styles:        DefaultStyles(),
 		searchMode:    false,
 		searchQuery:   "",
 		filteredList:  []RequestItem{},
 		totalPages:    totalPages,
+		expanded:      map[string]bool{},
+		width:         80,
+		height:        24,
 	}
 
 	return m
@@ -59,147 +71,184 @@ func (m CollectionsViewModel) Init() tea.Cmd {
 
 func (m CollectionsViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.history, _ = m.history.Update(m.historySize())
+		return m, nil
+
+	case collectionsReloadedMsg:
+		m.collections = msg.collections
+		m.totalPages = len(m.collections) + 2
+		m.currentPage = min(m.currentPage, m.totalPages-1)
+		if m.searchMode {
+			m.updateFilteredList()
+		}
+		m.cursor = max(0, min(m.cursor, len(m.getRows())-1))
+		m.scrollToCursor()
+		return m, nil
+
+	case historyRecordedMsg:
+		if m.historyLoaded {
+			m.history, _ = m.history.Update(msg)
+		}
+		return m, nil
+
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
-			if m.searchMode {
-				m.searchMode = false
-				m.searchQuery = ""
-				m.filteredList = []RequestItem{}
-				m.cursor = 0
-				m.viewportStart = 0
-				return m, nil
-			}
-			*m.selected = ExitSignal
-			m.quitting = true
-			return m, tea.Quit
-
-		case "esc":
-			if m.searchMode {
-				m.searchMode = false
-				m.searchQuery = ""
-				m.filteredList = []RequestItem{}
-				m.cursor = 0
-				m.viewportStart = 0
-				return m, nil
-			}
-			*m.selected = ExitSignal
-			m.quitting = true
-			return m, tea.Quit
-
-		case "/":
-			if !m.searchMode {
-				m.searchMode = true
-				m.searchQuery = ""
-				m.filteredList = []RequestItem{}
-				m.cursor = 0
-				m.viewportStart = 0
-				return m, nil
+		// The history tab handles its own keys, except for switching tabs and quitting
+		if m.isHistoryPage() {
+			switch msg.String() {
+			case "left", "h", "right", "l", "q", "esc":
+				if m.history.capturingInput() {
+					break
+				}
+				return m.updatePages(msg)
 			}
+			var cmd tea.Cmd
+			m.history, cmd = m.history.Update(msg)
+			return m, cmd
+		}
+		return m.updatePages(msg)
+	}
 
-		case "backspace":
-			if m.searchMode && len(m.searchQuery) > 0 {
-				m.searchQuery = m.searchQuery[:len(m.searchQuery)-1]
-				m.updateFilteredList()
-				m.cursor = 0
-				m.viewportStart = 0
-				return m, nil
-			}
+	return m, nil
+}
 
-		case "left", "h":
-			if m.searchMode {
-				return m, nil
-			}
-			if m.currentPage > 0 {
-				m.currentPage--
-			} else {
-				m.currentPage = m.totalPages - 1
-			}
+// updatePages handles keys for the collection and settings pages
+func (m CollectionsViewModel) updatePages(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc":
+		if m.searchMode {
+			m.searchMode = false
+			m.searchQuery = ""
+			m.filteredList = []RequestItem{}
 			m.cursor = 0
 			m.viewportStart = 0
+			return m, nil
+		}
+		return m, tea.Quit
 
-		case "right", "l":
-			if m.searchMode {
-				return m, nil
-			}
-			if m.currentPage < m.totalPages-1 {
-				m.currentPage++
-			} else {
-				m.currentPage = 0
-			}
+	case "/":
+		if !m.searchMode {
+			m.searchMode = true
+			m.searchQuery = ""
+			m.filteredList = []RequestItem{}
+			m.cursor = 0
+			m.viewportStart = 0
+			return m, nil
+		}
+
+	case "backspace":
+		if m.searchMode && len(m.searchQuery) > 0 {
+			m.searchQuery = m.searchQuery[:len(m.searchQuery)-1]
+			m.updateFilteredList()
 			m.cursor = 0
 			m.viewportStart = 0
+			return m, nil
+		}
+		if !m.searchMode {
+			m.closeParentFolder()
+			return m, nil
+		}
 
-		case "up", "k":
-			items := m.getActiveList()
-			if m.cursor > 0 {
-				m.cursor--
-				if m.cursor < m.viewportStart+2 && m.viewportStart > 0 {
-					m.viewportStart--
-				}
-			} else {
-				m.cursor = len(items) - 1
-				if len(items) > m.maxVisible {
-					m.viewportStart = len(items) - m.maxVisible
-				} else {
-					m.viewportStart = 0
-				}
-			}
+	case "left", "h":
+		if m.searchMode {
+			return m, nil
+		}
+		if m.currentPage > 0 {
+			m.currentPage--
+		} else {
+			m.currentPage = m.totalPages - 1
+		}
+		m.cursor = 0
+		m.viewportStart = 0
+		m.loadHistory()
 
-		case "down", "j":
-			items := m.getActiveList()
-			if m.cursor < len(items)-1 {
-				m.cursor++
-				if m.cursor >= m.viewportStart+m.maxVisible-2 {
-					m.viewportStart++
-				}
+	case "right", "l":
+		if m.searchMode {
+			return m, nil
+		}
+		if m.currentPage < m.totalPages-1 {
+			m.currentPage++
+		} else {
+			m.currentPage = 0
+		}
+		m.cursor = 0
+		m.viewportStart = 0
+		m.loadHistory()
+
+	case "up", "k":
+		items := m.getRows()
+		if m.cursor > 0 {
+			m.cursor--
+			if m.cursor < m.viewportStart+2 && m.viewportStart > 0 {
+				m.viewportStart--
+			}
+		} else {
+			m.cursor = len(items) - 1
+			if len(items) > m.maxVisible {
+				m.viewportStart = len(items) - m.maxVisible
 			} else {
-				m.cursor = 0
 				m.viewportStart = 0
 			}
+		}
 
-		case "enter":
-			items := m.getActiveList()
-			if len(items) > 0 && m.cursor < len(items) {
-				// Check if we're on settings page
-				if m.isSettingsPage() {
-					settingsItem := m.getSettingsItems()[m.cursor]
-					result := fmt.Sprintf("settings|%s", settingsItem.Key)
-					*m.selected = result
-					m.quitting = true
-					return m, tea.Quit
-				}
+	case "down", "j":
+		items := m.getRows()
+		if m.cursor < len(items)-1 {
+			m.cursor++
+			if m.cursor >= m.viewportStart+m.maxVisible-2 {
+				m.viewportStart++
+			}
+		} else {
+			m.cursor = 0
+			m.viewportStart = 0
+		}
 
-				// Regular request selection
-				selectedItem := items[m.cursor]
-				result := fmt.Sprintf("%s|%s", m.collections[m.currentPage].Name, selectedItem.Name)
-				*m.selected = result
-				m.quitting = true
-				return m, tea.Quit
+	case "enter":
+		rows := m.getRows()
+		if len(rows) > 0 && m.cursor < len(rows) {
+			// Check if we're on settings page
+			if m.isSettingsPage() {
+				settingsItem := m.getSettingsItems()[m.cursor]
+				return m, emit(settingSelectedMsg{key: settingsItem.Key})
 			}
 
-		default:
-			if m.searchMode {
-				key := msg.String()
-				if len(key) == 1 {
-					m.searchQuery += key
-					m.updateFilteredList()
-					m.cursor = 0
-					m.viewportStart = 0
-					return m, nil
-				}
+			if folder := rows[m.cursor].folder; folder != nil {
+				key := m.folderKey(folder.Path)
+				m.expanded[key] = !m.expanded[key]
+				m.scrollToCursor()
+				return m, nil
+			}
+
+			// Regular request selection
+			selectedItem := rows[m.cursor].item
+			return m, emit(requestSelectedMsg{item: selectedItem})
+		}
+
+	default:
+		if m.searchMode {
+			key := msg.String()
+			if len(key) == 1 {
+				m.searchQuery += key
+				m.updateFilteredList()
+				m.cursor = 0
+				m.viewportStart = 0
+				return m, nil
 			}
 		}
+		if msg.String() == "i" && m.currentPage < len(m.collections) {
+			return m, emit(importCurlMsg{collection: m.collections[m.currentPage].Name})
+		}
+		if msg.String() == "!" && len(m.configLoader.Diagnostics()) > 0 {
+			return m, emit(showDiagnosticsMsg{})
+		}
 	}
 
 	return m, nil
 }
 
 func (m CollectionsViewModel) View() string {
-	if m.quitting {
-		return ""
-	}
-
 	var b strings.Builder
 
 	// Header with tabs
@@ -212,18 +261,49 @@ func (m CollectionsViewModel) View() string {
 		}
 	}
 
+	// Add history tab
+	if m.isHistoryPage() {
+		tabViews = append(tabViews, m.styles.Text("[ history 🕘 ]", m.styles.SelectedTitleColor))
+	} else {
+		tabViews = append(tabViews, m.styles.Text("  history 🕘  ", m.styles.MutedTitleColor))
+	}
+
 	// Add settings tab
-	settingsPageIndex := len(m.collections)
-	if settingsPageIndex == m.currentPage {
+	if m.isSettingsPage() {
 		tabViews = append(tabViews, m.styles.Text("[ settings ⚙️ ]", m.styles.SettingsSelectedTitleColor))
 	} else {
 		tabViews = append(tabViews, m.styles.Text("  settings ⚙️  ", m.styles.SettingsTitleColor))
 	}
 
+	// Active environment badge, so it is always clear where requests will go
+	envBadge := m.styles.Text(fmt.Sprintf("  🌐 %s", m.configLoader.GetEnvironmentLabel(m.config)), m.styles.AquamarineColor)
+	tabViews = append(tabViews, envBadge)
+
 	b.WriteString("\n")
 	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, tabViews...))
 	b.WriteString("\n\n")
 
+	if m.isHistoryPage() {
+		b.WriteString(m.history.View())
+		return b.String()
+	}
+
+	// Request files that failed to load are not listed, so say so rather than let them go missing
+	if diagnostics := m.configLoader.Diagnostics(); len(diagnostics) > 0 && !m.isSettingsPage() {
+		errorCount, _ := CountDiagnostics(diagnostics)
+		color := m.styles.PeachColor
+		if errorCount > 0 {
+			color = m.styles.ErrorColor
+		}
+		b.WriteString(m.styles.Text(fmt.Sprintf("  ⚠️  %s in request files • ! to see them", pluralize(len(diagnostics), "problem")), color))
+		b.WriteString("\n\n")
+	}
+
+	if breadcrumb := m.breadcrumb(); breadcrumb != "" {
+		b.WriteString(m.styles.Text("  "+breadcrumb, m.styles.ThistleColor))
+		b.WriteString("\n\n")
+	}
+
 	// Show search box if in search mode
 	if m.searchMode {
 		searchBox := lipgloss.NewStyle().
@@ -242,8 +322,8 @@ func (m CollectionsViewModel) View() string {
 	}
 
 	// Current page items
-	items := m.getActiveList()
-	if len(items) == 0 {
+	rows := m.getRows()
+	if len(rows) == 0 {
 		if m.searchMode {
 			b.WriteString(m.styles.FooterStyle.Render("  No matches found\n"))
 		} else {
@@ -251,8 +331,8 @@ func (m CollectionsViewModel) View() string {
 		}
 	} else {
 		visibleEnd := m.viewportStart + m.maxVisible
-		if visibleEnd > len(items) {
-			visibleEnd = len(items)
+		if visibleEnd > len(rows) {
+			visibleEnd = len(rows)
 		}
 
 		if m.viewportStart > 0 {
@@ -266,6 +346,7 @@ func (m CollectionsViewModel) View() string {
 		for i := m.viewportStart; i < visibleEnd; i++ {
 			var itemBox lipgloss.Style
 			var borderColor lipgloss.Color
+			indent := rows[i].depth * 3 // Folder contents are indented below their folder
 
 			if m.cursor == i {
 				if isSettings {
@@ -277,8 +358,8 @@ func (m CollectionsViewModel) View() string {
 					Border(lipgloss.RoundedBorder()).
 					BorderForeground(borderColor).
 					Padding(0, 1).
-					Width(70).
-					MarginLeft(2)
+					Width(70 - indent).
+					MarginLeft(2 + indent)
 			} else {
 				if isSettings {
 					borderColor = m.styles.SettingsBorderColor
@@ -289,7 +370,8 @@ func (m CollectionsViewModel) View() string {
 					Border(lipgloss.RoundedBorder()).
 					BorderForeground(borderColor).
 					Padding(0, 1).
-					Width(70)
+					Width(70 - indent).
+					MarginLeft(indent)
 			}
 
 			titleStyle := lipgloss.NewStyle().Bold(true)
@@ -314,7 +396,7 @@ func (m CollectionsViewModel) View() string {
 
 			valueStyle := lipgloss.NewStyle().
 				Foreground(valueColor).
-				Width(66).
+				Width(66 - indent).
 				Italic(true)
 
 			var content string
@@ -333,12 +415,30 @@ func (m CollectionsViewModel) View() string {
 					titleStyle.Render(titleText),
 					valueStyle.Render(settingsItem.Value),
 				)
+			} else if folder := rows[i].folder; folder != nil {
+				// Render folder, ▾ when its contents are listed below it
+				arrow := "▸"
+				if m.expanded[m.folderKey(folder.Path)] {
+					arrow = "▾"
+				}
+				summary := pluralize(len(folder.AllRequests()), "request")
+				if len(folder.Folders) > 0 {
+					summary += " • " + pluralize(len(folder.Folders), "folder")
+				}
+				content = fmt.Sprintf("%s\n%s",
+					titleStyle.Render(arrow+" 📁 "+folder.Name),
+					valueStyle.Render(summary),
+				)
 			} else {
 				// Render request item
-				item := items[i]
+				item := rows[i].item
 				var titleText string
 				if m.searchMode && m.searchQuery != "" {
 					titleText = m.highlightMatches(item.Name, m.searchQuery)
+					// Results come from every folder, say which one
+					if item.Folder != "" {
+						titleText += m.styles.Text("  📁 "+item.Folder, m.styles.MutedTitleColor)
+					}
 				} else {
 					titleText = item.Name
 				}
@@ -348,7 +448,7 @@ func (m CollectionsViewModel) View() string {
 					Foreground(methodColor).
 					Bold(true)
 
-				displayURL := m.configLoader.ReplaceVariables(item.Request.URL, m.config)
+				displayURL := m.configLoader.ReplaceVariables(item.Request.URL, m.config, item.Variables)
 
 				content = fmt.Sprintf("%s\n%s %s",
 					titleStyle.Render(titleText),
@@ -361,7 +461,7 @@ func (m CollectionsViewModel) View() string {
 			b.WriteString("\n")
 		}
 
-		if visibleEnd < len(items) {
+		if visibleEnd < len(rows) {
 			b.WriteString("\n")
 			b.WriteString(m.styles.FooterStyle.Render("  ⬇ More items below..."))
 		}
@@ -373,8 +473,11 @@ func (m CollectionsViewModel) View() string {
 	if m.searchMode {
 		helpText = "  type to search • ↑↓/jk navigate • enter select • esc cancel"
 	} else {
-		helpText = "  / search • ↑↓/jk navigate • enter select • q/esc quit"
-		if m.totalPages > 1 {
+		helpText = "  / search • ←→/hl switch • ↑↓/jk navigate • enter select • i import cURL • q/esc quit"
+		if m.currentPage < len(m.collections) && len(m.collections[m.currentPage].Root.Folders) > 0 {
+			helpText = "  / search • ←→/hl switch • ↑↓/jk navigate • enter select/open folder • ⌫ close folder • i import cURL • q/esc quit"
+		}
+		if m.isSettingsPage() {
 			helpText = "  / search • ←→/hl switch • ↑↓/jk navigate • enter select • q/esc quit"
 		}
 	}
@@ -410,10 +513,124 @@ func (m CollectionsViewModel) getActiveList() []RequestItem {
 	return []RequestItem{}
 }
 
-func (m CollectionsViewModel) isSettingsPage() bool {
+// getRows lists the lines of the current page: the collection tree with its open folders
+// expanded, or search results and settings as they are
+func (m CollectionsViewModel) getRows() []collectionRow {
+	if m.currentPage >= len(m.collections) || (m.searchMode && m.searchQuery != "") {
+		items := m.getActiveList()
+		rows := make([]collectionRow, len(items))
+		for i, item := range items {
+			rows[i] = collectionRow{item: item}
+		}
+		return rows
+	}
+	return m.folderRows(&m.collections[m.currentPage].Root, 0, nil)
+}
+
+// folderRows adds the requests of a folder, then its subfolders and the contents of the open ones
+func (m CollectionsViewModel) folderRows(folder *Folder, depth int, rows []collectionRow) []collectionRow {
+	for _, item := range folder.Requests {
+		rows = append(rows, collectionRow{item: item, depth: depth})
+	}
+	for i := range folder.Folders {
+		subfolder := &folder.Folders[i]
+		rows = append(rows, collectionRow{folder: subfolder, depth: depth})
+		if m.expanded[m.folderKey(subfolder.Path)] {
+			rows = m.folderRows(subfolder, depth+1, rows)
+		}
+	}
+	return rows
+}
+
+// folderKey identifies a folder of the current collection in the expanded set
+func (m CollectionsViewModel) folderKey(folderPath string) string {
+	if m.currentPage >= len(m.collections) {
+		return folderPath
+	}
+	return m.collections[m.currentPage].Name + "/" + folderPath
+}
+
+// closeParentFolder closes the folder holding the selected line and selects that folder
+func (m *CollectionsViewModel) closeParentFolder() {
+	rows := m.getRows()
+	if m.cursor >= len(rows) || m.currentPage >= len(m.collections) {
+		return
+	}
+	parent := rows[m.cursor].item.Folder
+	if folder := rows[m.cursor].folder; folder != nil {
+		if parent = path.Dir(folder.Path); parent == "." {
+			parent = ""
+		}
+	}
+	if parent == "" {
+		return
+	}
+
+	m.expanded[m.folderKey(parent)] = false
+	for i, row := range m.getRows() {
+		if row.folder != nil && row.folder.Path == parent {
+			m.cursor = i
+			break
+		}
+	}
+	m.scrollToCursor()
+}
+
+// scrollToCursor moves the viewport so the selected line is visible after the list changed
+func (m *CollectionsViewModel) scrollToCursor() {
+	rows := len(m.getRows())
+	m.viewportStart = max(0, min(m.viewportStart, rows-m.maxVisible))
+	if m.cursor < m.viewportStart {
+		m.viewportStart = m.cursor
+	}
+	if m.cursor >= m.viewportStart+m.maxVisible {
+		m.viewportStart = m.cursor - m.maxVisible + 1
+	}
+}
+
+// breadcrumb shows where the selected line sits in the collection tree, for collections with folders
+func (m CollectionsViewModel) breadcrumb() string {
+	if m.currentPage >= len(m.collections) || len(m.collections[m.currentPage].Root.Folders) == 0 || (m.searchMode && m.searchQuery != "") {
+		return ""
+	}
+	crumbs := []string{m.collections[m.currentPage].Name}
+	if rows := m.getRows(); m.cursor < len(rows) {
+		location := rows[m.cursor].item.Folder
+		if rows[m.cursor].folder != nil {
+			location = rows[m.cursor].folder.Path
+		}
+		if location != "" {
+			crumbs = append(crumbs, strings.Split(location, "/")...)
+		}
+	}
+	return "📂 " + strings.Join(crumbs, " › ")
+}
+
+func (m CollectionsViewModel) isHistoryPage() bool {
 	return m.currentPage == len(m.collections)
 }
 
+func (m CollectionsViewModel) isSettingsPage() bool {
+	return m.currentPage == len(m.collections)+1
+}
+
+// historySize is the space left to the history list below the tab bar
+func (m CollectionsViewModel) historySize() tea.WindowSizeMsg {
+	return tea.WindowSizeMsg{Width: m.width, Height: max(m.height-3, 1)}
+}
+
+// loadHistory reads history.jsonl the first time the history tab is opened.
+// Later executions are added through historyRecordedMsg.
+func (m *CollectionsViewModel) loadHistory() {
+	if !m.isHistoryPage() || m.historyLoaded {
+		return
+	}
+	entries, err := m.configLoader.LoadHistory(m.config)
+	m.history = NewHistoryViewModel(entries, err)
+	m.history, _ = m.history.Update(m.historySize())
+	m.historyLoaded = true
+}
+
 type SettingsItem struct {
 	Key   string
 	Label string
@@ -421,11 +638,21 @@ type SettingsItem struct {
 }
 
 func (m CollectionsViewModel) getSettingsItems() []SettingsItem {
+	environmentValue := m.configLoader.GetEnvironmentLabel(m.config)
+	if len(m.config.Environments) == 0 {
+		environmentValue = "default (no environments configured)"
+	}
+
 	items := []SettingsItem{
+		{
+			Key:   "environment",
+			Label: "Environment",
+			Value: environmentValue,
+		},
 		{
 			Key:   "baseUrl",
 			Label: "Base URL",
-			Value: m.config.BaseUrl,
+			Value: m.configLoader.GetBaseURL(m.config),
 		},
 		{
 			Key:   "jwt",
@@ -525,13 +752,3 @@ func (m CollectionsViewModel) getMethodColor(method string) lipgloss.Color {
 		return m.styles.MutedTitleColor
 	}
 }
-
-func CollectionsView(collections []Collection, config *ConfigJSON, secret *SecretJSON, configLoader *ConfigLoader, fileManager FileManagerInterface, selected *string) {
-	m := NewCollectionsViewModel(collections, config, secret, configLoader, fileManager)
-	m.selected = selected
-
-	if _, err := tea.NewProgram(m).Run(); err != nil {
-		fmt.Println("CollectionsView -> ", err)
-		os.Exit(1)
-	}
-}
@@ -3,16 +3,24 @@ package src
 import (
 	"encoding/json"
 	"fmt"
+	"path"
 	"path/filepath"
 	"strings"
 )
 
 type ConfigLoader struct {
-	fileManager FileManagerInterface
+	fileManager      FileManagerInterface
+	state            *StateJSON
+	sessionVariables map[string]string // Captured values kept in memory for this run
+	diagnostics      []Diagnostic      // Problems found in request files by the last LoadCollections
 }
 
 func NewConfigLoader(fm FileManagerInterface) *ConfigLoader {
-	return &ConfigLoader{fileManager: fm}
+	return &ConfigLoader{
+		fileManager:      fm,
+		state:            GetDefaultStateJSON(),
+		sessionVariables: map[string]string{},
+	}
 }
 
 func (cl *ConfigLoader) LoadConfigJSON() (*ConfigJSON, error) {
@@ -21,21 +29,51 @@ func (cl *ConfigLoader) LoadConfigJSON() (*ConfigJSON, error) {
 		return nil, fmt.Errorf("LoadConfigJSON -> %v", err)
 	}
 
-	var config ConfigJSON
-	if err := json.Unmarshal([]byte(content), &config); err != nil {
-		return nil, fmt.Errorf("LoadConfigJSON -> failed to parse JSON: %v", err)
+	config, err := ParseDocumentContent[ConfigJSON](content, IsYAMLFile(cl.fileManager.GetConfigPath()))
+	if err != nil {
+		return nil, fmt.Errorf("LoadConfigJSON -> failed to parse %s: %v", filepath.Base(cl.fileManager.GetConfigPath()), err)
 	}
 
-	// Validate required fields
-	if config.BaseUrl == "" {
+	// Validate required fields (environments may provide their own baseUrl)
+	if config.BaseUrl == "" && len(config.Environments) == 0 {
 		return nil, fmt.Errorf("LoadConfigJSON -> baseUrl is required")
 	}
+	if _, ok := config.Environments[DefaultEnvironment]; ok {
+		return nil, fmt.Errorf("LoadConfigJSON -> environment name %q is reserved for the top-level baseUrl, rename it", DefaultEnvironment)
+	}
 
-	return &config, nil
+	return config, nil
 }
 
+// SaveConfigJSON writes config.json, or config.yaml in YAML. Only the values that changed are
+// rewritten, the rest of the file (YAML comments included) is kept as it is.
+func (cl *ConfigLoader) SaveConfigJSON(config *ConfigJSON) error {
+	isYAML := IsYAMLFile(cl.fileManager.GetConfigPath())
+	content, err := ToDocument(config, isYAML)
+	if err != nil {
+		return fmt.Errorf("SaveConfigJSON -> %v", err)
+	}
+	if existing, err := cl.fileManager.GetConfigContent(); err == nil {
+		updated, err := UpdateDocumentContent(existing, config, isYAML)
+		switch {
+		case err == nil:
+			content = updated
+		case isYAML:
+			// Rewriting would drop the comments of the file
+			return fmt.Errorf("SaveConfigJSON -> %s cannot be updated in place: %v", filepath.Base(cl.fileManager.GetConfigPath()), err)
+		}
+	}
+	if err := cl.fileManager.WriteConfigContent(content); err != nil {
+		return fmt.Errorf("SaveConfigJSON -> %v", err)
+	}
+	return nil
+}
+
+// LoadCollections reads every collection. Files that cannot be loaded are left out and, with
+// requests missing required fields or sharing a name, reported by Diagnostics.
 func (cl *ConfigLoader) LoadCollections() ([]Collection, error) {
 	fm := cl.fileManager.(*FileManager)
+	cl.diagnostics = nil
 
 	collectionNames, err := cl.fileManager.GetCollections()
 	if err != nil {
@@ -50,39 +88,171 @@ func (cl *ConfigLoader) LoadCollections() ([]Collection, error) {
 			Requests: []RequestItem{},
 		}
 
+		variables, err := cl.loadCollectionVariables(collection.Path)
+		if err != nil {
+			variablesPath, _, _ := cl.collectionVariablesPath(collection.Path)
+			cl.diagnostics = append(cl.diagnostics, documentDiagnostic(collName, variablesPath, err))
+		}
+		collection.Variables = variables
+
 		files, err := cl.fileManager.GetRequestFiles(collName)
 		if err != nil {
 			return nil, fmt.Errorf("LoadCollections -> failed to get files for %s: %v", collName, err)
 		}
 
+		lines := make(map[string]int) // Where the requests of .http files start
 		for _, file := range files {
-			filePath := filepath.Join(collection.Path, file)
+			filePath := filepath.Join(collection.Path, filepath.FromSlash(file))
+			folder := path.Dir(file)
+			if folder == "." {
+				folder = ""
+			}
 			content, err := cl.fileManager.ReadFileContent(filePath)
 			if err != nil {
-				continue // Skip files that can't be read
+				cl.diagnostics = append(cl.diagnostics, documentDiagnostic(collName, filePath, err))
+				continue
+			}
+
+			if IsHTTPFile(file) {
+				httpFile := ParseHTTPFile(content)
+				for _, problem := range httpFile.Problems {
+					cl.diagnostics = append(cl.diagnostics, Diagnostic{
+						Collection: collName, FilePath: filePath, Line: problem.Line, Column: problem.Column,
+						Severity: DiagnosticWarning, Message: problem.Message,
+					})
+				}
+				items := httpFileItems(file, filePath, httpFile, collection.Variables)
+				for i, parsed := range httpFile.Requests {
+					items[i].Folder = folder
+					lines[items[i].RequestPath()] = parsed.Line
+					cl.diagnostics = append(cl.diagnostics, requestDiagnostics(collName, filePath, parsed.Line, parsed.Request)...)
+				}
+				collection.Requests = append(collection.Requests, items...)
+				continue
 			}
 
-			var request RequestJSON
-			if err := json.Unmarshal([]byte(content), &request); err != nil {
-				continue // Skip files that can't be parsed
+			request, err := ParseDocumentContent[RequestJSON](content, IsYAMLFile(file))
+			if err != nil {
+				cl.diagnostics = append(cl.diagnostics, documentDiagnostic(collName, filePath, err))
+				continue
 			}
+			cl.diagnostics = append(cl.diagnostics, unknownFieldDiagnostics(collName, filePath, content, IsYAMLFile(file))...)
+			cl.diagnostics = append(cl.diagnostics, requestDiagnostics(collName, filePath, 0, request)...)
 
 			requestItem := RequestItem{
-				Name:     request.Name,
-				FileName: file,
-				FilePath: filePath,
-				Request:  &request,
+				Name:      request.Name,
+				FileName:  file,
+				FilePath:  filePath,
+				Folder:    folder,
+				Variables: collection.Variables,
+				Request:   request,
 			}
 
 			collection.Requests = append(collection.Requests, requestItem)
 		}
 
+		// Files come in name order, folders and their requests keep it
+		collection.Root = Folder{Name: collName}
+		for _, item := range collection.Requests {
+			collection.Root.add(item)
+		}
+		collection.Requests = collection.Root.AllRequests()
+
+		cl.diagnostics = append(cl.diagnostics, duplicateNameDiagnostics(collection, lines)...)
 		collections = append(collections, collection)
 	}
 
+	SortDiagnostics(cl.diagnostics)
 	return collections, nil
 }
 
+// Diagnostics lists the problems found in request files by the last LoadCollections
+func (cl *ConfigLoader) Diagnostics() []Diagnostic {
+	return cl.diagnostics
+}
+
+// httpFileItems lists the requests of an .http file; file variables override collection variables
+func httpFileItems(fileName, filePath string, file *HTTPFile, collectionVariables map[string]string) []RequestItem {
+	variables := collectionVariables
+	if len(file.Variables) > 0 {
+		variables = make(map[string]string, len(collectionVariables)+len(file.Variables))
+		for name, value := range collectionVariables {
+			variables[name] = value
+		}
+		for name, value := range file.Variables {
+			variables[name] = value
+		}
+	}
+
+	items := make([]RequestItem, 0, len(file.Requests))
+	for _, parsed := range file.Requests {
+		items = append(items, RequestItem{
+			Name:      parsed.Request.Name,
+			FileName:  fileName,
+			FilePath:  filePath,
+			Section:   parsed.Section,
+			Variables: variables,
+			Request:   parsed.Request,
+		})
+	}
+	return items
+}
+
+// SaveHTTPFileBody writes the body of a request of an .http file back into the file
+func (cl *ConfigLoader) SaveHTTPFileBody(item *RequestItem) error {
+	content, err := cl.fileManager.ReadFileContent(item.FilePath)
+	if err != nil {
+		return fmt.Errorf("SaveHTTPFileBody -> %v", err)
+	}
+
+	updated, err := ReplaceHTTPFileBody(content, item.Section, item.Request)
+	if err != nil {
+		return fmt.Errorf("SaveHTTPFileBody -> %v", err)
+	}
+
+	if err := cl.fileManager.WriteFileContent(item.FilePath, updated); err != nil {
+		return fmt.Errorf("SaveHTTPFileBody -> %v", err)
+	}
+	return nil
+}
+
+// loadCollectionVariables reads the optional _variables.json of a collection
+func (cl *ConfigLoader) loadCollectionVariables(collectionPath string) (map[string]string, error) {
+	variablesPath, exists, err := cl.collectionVariablesPath(collectionPath)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	content, err := cl.fileManager.ReadFileContent(variablesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	variables, err := ParseDocumentContent[map[string]string](content, IsYAMLFile(variablesPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", filepath.Base(variablesPath), err)
+	}
+
+	return *variables, nil
+}
+
+// collectionVariablesPath finds the variables file of a collection: _variables.json, .yaml or .yml.
+// Without any, it returns where _variables.json would be.
+func (cl *ConfigLoader) collectionVariablesPath(collectionPath string) (string, bool, error) {
+	base := strings.TrimSuffix(CollectionVariablesFileName, filepath.Ext(CollectionVariablesFileName))
+	for _, name := range []string{CollectionVariablesFileName, base + ".yaml", base + ".yml"} {
+		variablesPath := filepath.Join(collectionPath, name)
+		exists, err := cl.fileManager.CheckIfPathExists(variablesPath)
+		if err != nil {
+			return "", false, err
+		}
+		if exists {
+			return variablesPath, true, nil
+		}
+	}
+	return filepath.Join(collectionPath, CollectionVariablesFileName), false, nil
+}
+
 func (cl *ConfigLoader) LoadSecretJSON() (*SecretJSON, error) {
 	// Try to load existing secret.json
 	content, err := cl.fileManager.GetSecretContent()
@@ -110,10 +280,104 @@ func (cl *ConfigLoader) LoadSecretJSON() (*SecretJSON, error) {
 	return &secret, nil
 }
 
+// LoadStateJSON loads state.json, falling back to an empty state when the file does not exist
+func (cl *ConfigLoader) LoadStateJSON() (*StateJSON, error) {
+	content, err := cl.fileManager.GetStateContent()
+	if err != nil {
+		cl.state = GetDefaultStateJSON()
+		return cl.state, nil
+	}
+
+	var state StateJSON
+	if err := json.Unmarshal([]byte(content), &state); err != nil {
+		return nil, fmt.Errorf("LoadStateJSON -> failed to parse JSON: %v", err)
+	}
+
+	cl.state = &state
+	return cl.state, nil
+}
+
+func (cl *ConfigLoader) SaveStateJSON(state *StateJSON) error {
+	content, err := ToJSON(state)
+	if err != nil {
+		return fmt.Errorf("SaveStateJSON -> %v", err)
+	}
+
+	if err := cl.fileManager.WriteStateContent(content); err != nil {
+		return fmt.Errorf("SaveStateJSON -> %v", err)
+	}
+
+	cl.state = state
+	return nil
+}
+
+// SetActiveEnvironment selects an environment ("" for the default one) and persists it in state.json
+func (cl *ConfigLoader) SetActiveEnvironment(config *ConfigJSON, name string) error {
+	config.ActiveEnvironment = name
+	cl.state.ActiveEnvironment = name
+	return cl.SaveStateJSON(cl.state)
+}
+
+func (cl *ConfigLoader) SetSessionVariable(name, value string) {
+	cl.sessionVariables[name] = value
+}
+
+// SetPersistedVariable stores a variable in the in-memory state, call SaveStateJSON to write it
+func (cl *ConfigLoader) SetPersistedVariable(name, value string) {
+	if cl.state.Variables == nil {
+		cl.state.Variables = map[string]string{}
+	}
+	cl.state.Variables[name] = value
+	delete(cl.sessionVariables, name)
+}
+
+// RequestKey identifies a request file in local state: its path relative to the requests directory
+func (cl *ConfigLoader) RequestKey(filePath string) string {
+	if fm, ok := cl.fileManager.(*FileManager); ok {
+		if rel, err := filepath.Rel(fm.RequestsDir, filePath); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(filePath))
+}
+
+// GetRequestFilter returns the last response filter used for a request file
+func (cl *ConfigLoader) GetRequestFilter(filePath string) string {
+	return cl.state.Filters[cl.RequestKey(filePath)]
+}
+
+// SetRequestFilter remembers a response filter for a request file ("" forgets it) and saves state.json
+func (cl *ConfigLoader) SetRequestFilter(filePath, filter string) error {
+	key := cl.RequestKey(filePath)
+	if filter == "" {
+		if _, ok := cl.state.Filters[key]; !ok {
+			return nil
+		}
+		delete(cl.state.Filters, key)
+	} else {
+		if cl.state.Filters == nil {
+			cl.state.Filters = map[string]string{}
+		}
+		cl.state.Filters[key] = filter
+	}
+	return cl.SaveStateJSON(cl.state)
+}
+
 func (cl *ConfigLoader) GetBaseURL(config *ConfigJSON) string {
+	if env := config.GetActiveEnvironment(); env != nil && env.BaseUrl != "" {
+		return env.BaseUrl
+	}
 	return config.BaseUrl
 }
 
+// GetEnvironmentLabel returns the active environment name for display
+func (cl *ConfigLoader) GetEnvironmentLabel(config *ConfigJSON) string {
+	if config.GetActiveEnvironment() == nil {
+		return DefaultEnvironment
+	}
+	return config.ActiveEnvironment
+}
+
 func (cl *ConfigLoader) GetJWT(secret *SecretJSON) string {
 	if secret == nil {
 		return ""
@@ -121,12 +385,38 @@ func (cl *ConfigLoader) GetJWT(secret *SecretJSON) string {
 	return strings.TrimSpace(secret.JWT)
 }
 
-func (cl *ConfigLoader) ReplaceVariables(text string, config *ConfigJSON) string {
-	result := text
+// NewResolver builds the variable scope for a request.
+// Precedence (lowest to highest): baseUrl, config variables, active environment variables, scope,
+// persisted captures, session captures.
+func (cl *ConfigLoader) NewResolver(config *ConfigJSON, scope map[string]string) *VariableResolver {
+	return cl.newResolver(config, scope, true)
+}
+
+// NewStaticResolver builds the variable scope of NewResolver without captured values, for
+// output that is shared with others such as generated documentation
+func (cl *ConfigLoader) NewStaticResolver(config *ConfigJSON, scope map[string]string) *VariableResolver {
+	return cl.newResolver(config, scope, false)
+}
+
+// newResolver stacks the variable scopes of a request, captures on top when includeCaptures is set
+func (cl *ConfigLoader) newResolver(config *ConfigJSON, scope map[string]string, includeCaptures bool) *VariableResolver {
+	builtin := map[string]string{
+		"baseUrl": cl.GetBaseURL(config),
+	}
+
+	var envVariables map[string]string
+	if env := config.GetActiveEnvironment(); env != nil {
+		envVariables = env.Variables
+	}
 
-	// Replace {{baseUrl}}
-	baseUrl := cl.GetBaseURL(config)
-	result = strings.ReplaceAll(result, "{{baseUrl}}", baseUrl)
+	scopes := []map[string]string{builtin, config.Variables, envVariables, scope}
+	if includeCaptures {
+		scopes = append(scopes, cl.state.Variables, cl.sessionVariables)
+	}
+	return NewVariableResolver(scopes...)
+}
 
-	return result
+// ReplaceVariables resolves variables for display, leaving undefined references untouched
+func (cl *ConfigLoader) ReplaceVariables(text string, config *ConfigJSON, scope map[string]string) string {
+	return cl.NewResolver(config, scope).ResolveLenient(text)
 }
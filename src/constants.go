@@ -3,7 +3,22 @@ package src
 const (
 	PostlessDirName = ".postless"
 	ConfigFileName  = "config.json"
+	ConfigYAMLName  = "config.yaml" // Used when there is no config.json, like config.yml
+	ConfigYMLName   = "config.yml"
 	SecretFileName  = "secret.json"
+	StateFileName   = "state.json"
+	HistoryFileName = "history.jsonl"
 	RequestsDirName = "requests"
 	ExitSignal      = "EXIT_SIGNAL"
+
+	// Per-collection variables file, skipped when listing request files.
+	// Like config and request files, it may be written in YAML: _variables.yaml or _variables.yml.
+	CollectionVariablesFileName = "_variables.json"
+
+	// Response snapshots stored next to request files, skipped when listing request files
+	SnapshotFileSuffix = ".snap.json"
+
+	// VS Code REST Client files, holding several requests each
+	HTTPFileExtension = ".http"
+	RestFileExtension = ".rest"
 )
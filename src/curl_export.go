@@ -0,0 +1,65 @@
+package src
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ExportCurl builds a cURL command reproducing a resolved request: same method, URL,
+// merged headers and body, with one option per line
+func ExportCurl(resolved *ResolvedRequest) (string, error) {
+	first := "curl "
+	switch resolved.Method {
+	case http.MethodGet:
+	case http.MethodHead:
+		first += "--head " // -X HEAD makes curl wait for a body that never comes
+	default:
+		first += "-X " + resolved.Method + " "
+	}
+	parts := []string{first + shellQuote(resolved.URL)}
+
+	for _, name := range sortedHeaderNames(resolved.Headers) {
+		for _, value := range resolved.Headers[name] {
+			parts = append(parts, "-H "+shellQuote(name+": "+value))
+		}
+	}
+
+	switch {
+	case len(resolved.Form) > 0:
+		for _, name := range sortedStringKeys(resolved.Form) {
+			parts = append(parts, "-F "+shellQuote(name+"="+resolved.Form[name]))
+		}
+	case resolved.RawBody != "":
+		parts = append(parts, "--data-raw "+shellQuote(resolved.RawBody))
+	case resolved.Body != nil:
+		body, err := ToCompactJSON(resolved.Body)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, "--data-raw "+shellQuote(body))
+	}
+
+	if resolved.Insecure {
+		parts = append(parts, "-k")
+	}
+
+	return strings.Join(parts, " \\\n  "), nil
+}
+
+// shellQuote quotes a word for POSIX shells, leaving plain words as they are
+func shellQuote(word string) string {
+	if word != "" && strings.Trim(word, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:@%+=,") == "" {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+func sortedHeaderNames(headers http.Header) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,426 @@
+package src
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// cURL options taking a value, by long name; short aliases are mapped in curlShortOptions
+var curlValueOptions = map[string]bool{
+	"request": true, "header": true, "data": true, "data-raw": true, "data-binary": true,
+	"data-ascii": true, "data-urlencode": true, "json": true, "user": true, "form": true,
+	"form-string": true, "user-agent": true, "referer": true, "cookie": true, "url": true,
+	"oauth2-bearer": true,
+
+	// Accepted and ignored
+	"output": true, "max-time": true, "connect-timeout": true, "write-out": true, "retry": true,
+	"cacert": true, "cert": true, "key": true, "proxy": true, "max-redirs": true, "resolve": true,
+}
+
+// cURL options without a value that are accepted and ignored
+var curlIgnoredFlags = map[string]bool{
+	"location": true, "silent": true, "show-error": true, "verbose": true, "include": true,
+	"compressed": true, "fail": true, "globoff": true, "http1.1": true, "http2": true,
+	"no-progress-meter": true, "progress-bar": true, "location-trusted": true,
+}
+
+var curlShortOptions = map[byte]string{
+	'X': "request", 'H': "header", 'd': "data", 'u': "user", 'F': "form", 'A': "user-agent",
+	'e': "referer", 'b': "cookie", 'o': "output", 'm': "max-time", 'w': "write-out", 'x': "proxy",
+	'E': "cert", 'k': "insecure", 'G': "get", 'I': "head", 'L': "location", 's': "silent",
+	'S': "show-error", 'v': "verbose", 'i': "include", 'f': "fail", 'g': "globoff", '#': "progress-bar",
+}
+
+// ParseCurlCommand turns a cURL command line into a request.
+// Supported: -X, -H, -d/--data/--data-raw/--data-binary/--data-urlencode, --json, -u, -F, -k, -G,
+// -I, -A, -e, -b and --oauth2-bearer. Options that do not affect the request are ignored;
+// anything else is reported in the returned warnings.
+func ParseCurlCommand(command string) (*RequestJSON, []string, error) {
+	words, err := splitShellWords(command)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(words) > 0 && (words[0] == "curl" || strings.HasSuffix(words[0], "/curl")) {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return nil, nil, fmt.Errorf("empty cURL command")
+	}
+
+	var warnings []string
+	var rawURL, method string
+	var data []string
+	var jsonBody, get, head bool
+	headers := map[string]string{}
+	form := map[string]string{}
+	request := &RequestJSON{}
+
+	setHeader := func(name, value string) {
+		// A header given again replaces the earlier one, whatever its case; the last spelling is kept
+		for existing := range headers {
+			if strings.EqualFold(existing, name) {
+				delete(headers, existing)
+			}
+		}
+		headers[name] = value
+	}
+
+	apply := func(option, value string) error {
+		switch option {
+		case "request":
+			method = strings.ToUpper(value)
+		case "header":
+			name, headerValue, ok := strings.Cut(value, ":")
+			if !ok {
+				// "Name;" sends an empty header
+				if name, ok := strings.CutSuffix(strings.TrimSpace(value), ";"); ok {
+					setHeader(name, "")
+					return nil
+				}
+				return fmt.Errorf("invalid header %q", value)
+			}
+			if strings.TrimSpace(headerValue) == "" {
+				return nil // "Name:" removes a default header in cURL
+			}
+			setHeader(strings.TrimSpace(name), strings.TrimSpace(headerValue))
+		case "data", "data-ascii", "data-binary", "json":
+			if path, ok := strings.CutPrefix(value, "@"); ok {
+				content, err := os.ReadFile(path)
+				if err != nil {
+					return fmt.Errorf("--%s: %v", option, err)
+				}
+				value = string(content)
+				if option == "data" || option == "data-ascii" {
+					value = strings.NewReplacer("\r", "", "\n", "").Replace(value)
+				}
+			}
+			data = append(data, value)
+			jsonBody = jsonBody || option == "json"
+		case "data-raw":
+			data = append(data, value)
+		case "data-urlencode":
+			name, content, hasName := strings.Cut(value, "=")
+			if !hasName {
+				data = append(data, url.QueryEscape(value))
+			} else if name == "" {
+				data = append(data, url.QueryEscape(content))
+			} else {
+				data = append(data, name+"="+url.QueryEscape(content))
+			}
+		case "user":
+			if !strings.Contains(value, ":") {
+				warnings = append(warnings, "-u without a password, an empty password is used")
+				value += ":"
+			}
+			setHeader("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(value)))
+		case "oauth2-bearer":
+			setHeader("Authorization", "Bearer "+value)
+		case "form", "form-string":
+			name, field, ok := strings.Cut(value, "=")
+			if !ok {
+				return fmt.Errorf("invalid form field %q", value)
+			}
+			if option == "form" {
+				if strings.HasPrefix(field, "<") {
+					warnings = append(warnings, fmt.Sprintf("form field %s reads its value from a file, kept as text", name))
+				}
+				if before, _, found := strings.Cut(field, ";"); found && strings.HasPrefix(field, "@") {
+					warnings = append(warnings, fmt.Sprintf("form field %s: ;type= and other file options are not supported", name))
+					field = before
+				}
+			}
+			form[name] = field
+		case "user-agent":
+			setHeader("User-Agent", value)
+		case "referer":
+			setHeader("Referer", value)
+		case "cookie":
+			if !strings.Contains(value, "=") {
+				warnings = append(warnings, "cookie files (-b <file>) are not supported")
+				return nil
+			}
+			setHeader("Cookie", value)
+		case "url":
+			rawURL = value
+		case "insecure":
+			request.Insecure = true
+		case "get":
+			get = true
+		case "head":
+			head = true
+		}
+		return nil
+	}
+
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+
+		switch {
+		case strings.HasPrefix(word, "--") && len(word) > 2:
+			option := word[2:]
+			if curlValueOptions[option] {
+				if i+1 >= len(words) {
+					return nil, warnings, fmt.Errorf("%s requires a value", word)
+				}
+				i++
+				if err := apply(option, words[i]); err != nil {
+					return nil, warnings, err
+				}
+			} else if option == "insecure" || option == "get" || option == "head" {
+				apply(option, "")
+			} else if !curlIgnoredFlags[option] {
+				warnings = append(warnings, fmt.Sprintf("ignored unsupported option %s", word))
+			}
+
+		case strings.HasPrefix(word, "-") && len(word) > 1:
+			// Short options can be combined (-sSL) and take their value attached or separately (-XPOST)
+			for j := 1; j < len(word); j++ {
+				option, known := curlShortOptions[word[j]]
+				if !known {
+					warnings = append(warnings, fmt.Sprintf("ignored unsupported option -%c", word[j]))
+					continue
+				}
+				if !curlValueOptions[option] {
+					apply(option, "")
+					continue
+				}
+				value := word[j+1:]
+				if value == "" {
+					if i+1 >= len(words) {
+						return nil, warnings, fmt.Errorf("-%c requires a value", word[j])
+					}
+					i++
+					value = words[i]
+				}
+				if err := apply(option, value); err != nil {
+					return nil, warnings, err
+				}
+				break
+			}
+
+		default:
+			if rawURL != "" {
+				warnings = append(warnings, fmt.Sprintf("ignored extra argument %q", word))
+				continue
+			}
+			rawURL = word
+		}
+	}
+
+	if rawURL == "" {
+		return nil, warnings, fmt.Errorf("no URL found in the cURL command")
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL // cURL's default scheme
+	}
+
+	body := strings.Join(data, "&")
+	if jsonBody {
+		body = strings.Join(data, "")
+		if _, ok := lookupHeader(headers, "Accept"); !ok {
+			setHeader("Accept", "application/json")
+		}
+	}
+
+	switch {
+	case get && len(data) > 0:
+		separator := "?"
+		if strings.Contains(rawURL, "?") {
+			separator = "&"
+		}
+		rawURL += separator + body
+		data = nil
+	case len(data) > 0 && len(form) > 0:
+		return nil, warnings, fmt.Errorf("cannot combine -d and -F in one request")
+	}
+
+	request.Method = method
+	if request.Method == "" {
+		switch {
+		case head:
+			request.Method = http.MethodHead
+		case len(data) > 0 || len(form) > 0:
+			request.Method = http.MethodPost
+		default:
+			request.Method = http.MethodGet
+		}
+	}
+	request.URL = rawURL
+
+	if len(form) > 0 {
+		request.Form = form
+		// The multipart boundary is generated when sending
+		if name, ok := lookupHeader(headers, "Content-Type"); ok {
+			delete(headers, name)
+		}
+	} else if len(data) > 0 {
+		contentTypeName, hasContentType := lookupHeader(headers, "Content-Type")
+		contentType := strings.ToLower(headers[contentTypeName])
+
+		parsed, isJSON := parseCurlJSON(body)
+		switch {
+		case isJSON && (jsonBody || strings.Contains(contentType, "json") || !hasContentType):
+			request.Body = parsed
+			// postless sends JSON bodies as application/json already
+			if hasContentType && contentType == "application/json" {
+				delete(headers, contentTypeName)
+			}
+		case jsonBody:
+			return nil, warnings, fmt.Errorf("--json data is not valid JSON")
+		default:
+			request.RawBody = body
+			if !hasContentType {
+				setHeader("Content-Type", "application/x-www-form-urlencoded") // cURL's default for -d
+			}
+		}
+	}
+
+	if len(headers) > 0 {
+		request.Headers = headers
+	}
+	return request, warnings, nil
+}
+
+// parseCurlJSON parses JSON data keeping numbers as written, so 20-digit ids are not rounded
+// to float64 in the saved request file
+func parseCurlJSON(data string) (interface{}, bool) {
+	decoder := json.NewDecoder(strings.NewReader(data))
+	decoder.UseNumber()
+	var parsed interface{}
+	if decoder.Decode(&parsed) != nil {
+		return nil, false
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, false // Anything after the value
+	}
+	return parsed, true
+}
+
+// lookupHeader finds a header name case-insensitively
+func lookupHeader(headers map[string]string, name string) (string, bool) {
+	for existing := range headers {
+		if strings.EqualFold(existing, name) {
+			return existing, true
+		}
+	}
+	return "", false
+}
+
+// ImportCurl parses a cURL command and saves it into a collection, templating the base URL.
+// It returns the saved request, its file path and parser warnings.
+func (cl *ConfigLoader) ImportCurl(config *ConfigJSON, collection, name, command string) (*RequestJSON, string, []string, error) {
+	request, warnings, err := ParseCurlCommand(command)
+	if err != nil {
+		return nil, "", warnings, err
+	}
+
+	request.URL = templateBaseURL(request.URL, config)
+	request.Name = name
+	if request.Name == "" {
+		request.Name = defaultRequestName(request.Method, request.URL)
+	}
+
+	filePath, err := cl.SaveImportedRequest(collection, request)
+	if err != nil {
+		return nil, "", warnings, err
+	}
+	return request, filePath, warnings, nil
+}
+
+// splitShellWords splits a command line like a POSIX shell: single and double quotes, $'...'
+// strings and backslash escapes. A backslash before whitespace continues the line.
+func splitShellWords(command string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+
+	flush := func() {
+		if inWord {
+			words = append(words, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			flush()
+
+		case c == '\\':
+			if i+1 >= len(command) {
+				break
+			}
+			i++
+			next := command[i]
+			if next == ' ' || next == '\t' || next == '\n' || next == '\r' {
+				flush() // Line continuation, also when a terminal turned the newline into a space
+				continue
+			}
+			word.WriteByte(next)
+			inWord = true
+
+		case c == '\'':
+			end := strings.IndexByte(command[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated ' in cURL command")
+			}
+			word.WriteString(command[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+
+		case c == '$' && i+1 < len(command) && command[i+1] == '\'':
+			i += 2
+			for ; i < len(command) && command[i] != '\''; i++ {
+				if command[i] == '\\' && i+1 < len(command) {
+					i++
+					switch command[i] {
+					case 'n':
+						word.WriteByte('\n')
+					case 't':
+						word.WriteByte('\t')
+					case 'r':
+						word.WriteByte('\r')
+					default:
+						word.WriteByte(command[i])
+					}
+					continue
+				}
+				word.WriteByte(command[i])
+			}
+			if i >= len(command) {
+				return nil, fmt.Errorf("unterminated $' in cURL command")
+			}
+			inWord = true
+
+		case c == '"':
+			i++
+			for ; i < len(command) && command[i] != '"'; i++ {
+				if command[i] == '\\' && i+1 < len(command) && strings.IndexByte("$`\"\\\n", command[i+1]) >= 0 {
+					i++
+					if command[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(command[i])
+			}
+			if i >= len(command) {
+				return nil, fmt.Errorf("unterminated \" in cURL command")
+			}
+			inWord = true
+
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	flush()
+	return words, nil
+}
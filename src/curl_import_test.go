@@ -0,0 +1,155 @@
+package src
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestSplitShellWords(t *testing.T) {
+	tests := []struct {
+		name    string
+		command string
+		want    []string
+		wantErr bool
+	}{
+		{name: "plain words", command: "curl  -X\tPOST  url", want: []string{"curl", "-X", "POST", "url"}},
+		{name: "single quotes keep everything", command: `curl -d '{"a": "$HOME \n"}'`, want: []string{"curl", "-d", `{"a": "$HOME \n"}`}},
+		{name: "double quotes unescape", command: `curl -H "X-Msg: say \"hi\" \$5 \\ \n"`, want: []string{"curl", "-H", `X-Msg: say "hi" $5 \ \n`}},
+		{name: "ANSI-C quoting", command: `curl -d $'a\nb\t\'c\''`, want: []string{"curl", "-d", "a\nb\t'c'"}},
+		{name: "quotes join a word", command: `-H 'A: '"b"c`, want: []string{"-H", "A: bc"}},
+		{name: "empty quotes are a word", command: `curl -d ''`, want: []string{"curl", "-d", ""}},
+		{name: "backslash escapes a quote", command: `curl -d a\'b`, want: []string{"curl", "-d", "a'b"}},
+		{name: "line continuation", command: "curl \\\n  -X POST \\\r\n  url", want: []string{"curl", "-X", "POST", "url"}},
+		{name: "continuation pasted as a space", command: `curl \ -k`, want: []string{"curl", "-k"}},
+		{name: "unterminated single quote", command: `curl -d 'abc`, wantErr: true},
+		{name: "unterminated double quote", command: `curl -d "abc`, wantErr: true},
+		{name: "unterminated ANSI-C quote", command: `curl -d $'abc`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitShellWords(tt.command)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCurlCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		want     string // The request as JSON
+		warnings []string
+		wantErr  string
+	}{
+		{
+			name:    "GET by default, scheme added",
+			command: "curl example.com/users",
+			want:    `{"name": "", "method": "GET", "url": "http://example.com/users", "skipAuth": false}`,
+		},
+		{
+			name:    "JSON data becomes the body",
+			command: `curl -X PUT https://api.test/u -H 'Content-Type: application/json' -d '{"name":"Ada"}'`,
+			want:    `{"name": "", "method": "PUT", "url": "https://api.test/u", "skipAuth": false, "body": {"name": "Ada"}}`,
+		},
+		{
+			name:    "20-digit integers stay exact",
+			command: `curl https://api.test/u --json '{"id": 12345678901234567890}'`,
+			want:    `{"name": "", "method": "POST", "url": "https://api.test/u", "skipAuth": false, "headers": {"Accept": "application/json"}, "body": {"id": 12345678901234567890}}`,
+		},
+		{
+			name:    "form data is sent raw",
+			command: `curl https://api.test/login -d user=ada -d 'pass=a b'`,
+			want:    `{"name": "", "method": "POST", "url": "https://api.test/login", "skipAuth": false, "headers": {"Content-Type": "application/x-www-form-urlencoded"}, "rawBody": "user=ada&pass=a b"}`,
+		},
+		{
+			name:    "-G moves data to the query",
+			command: `curl -G 'https://api.test/s?x=1' --data-urlencode 'q=a b'`,
+			want:    `{"name": "", "method": "GET", "url": "https://api.test/s?x=1&q=a+b", "skipAuth": false}`,
+		},
+		{
+			name:    "combined short options and attached values",
+			command: `curl -sSLk -XDELETE https://api.test/u/1`,
+			want:    `{"name": "", "method": "DELETE", "url": "https://api.test/u/1", "skipAuth": false, "insecure": true}`,
+		},
+		{
+			name:    "repeated headers keep the last spelling and value",
+			command: `curl https://api.test -H 'x-id: 1' -H 'X-Id: 2' -H 'X-Empty;' -H 'X-Removed:'`,
+			want:    `{"name": "", "method": "GET", "url": "https://api.test", "skipAuth": false, "headers": {"X-Empty": "", "X-Id": "2"}}`,
+		},
+		{
+			name:    "-u becomes a Basic header",
+			command: `curl -u ada:s3cret https://api.test`,
+			want:    `{"name": "", "method": "GET", "url": "https://api.test", "skipAuth": false, "headers": {"Authorization": "Basic YWRhOnMzY3JldA=="}}`,
+		},
+		{
+			name:     "-u without a password",
+			command:  `curl -u ada https://api.test`,
+			want:     `{"name": "", "method": "GET", "url": "https://api.test", "skipAuth": false, "headers": {"Authorization": "Basic YWRhOg=="}}`,
+			warnings: []string{"-u without a password, an empty password is used"},
+		},
+		{
+			name:    "--oauth2-bearer becomes a Bearer header",
+			command: `curl --oauth2-bearer tok https://api.test`,
+			want:    `{"name": "", "method": "GET", "url": "https://api.test", "skipAuth": false, "headers": {"Authorization": "Bearer tok"}}`,
+		},
+		{
+			name:    "multipart form",
+			command: `curl https://api.test/up -F 'file=@photo.png' -F name=x -H 'Content-Type: multipart/form-data'`,
+			want:    `{"name": "", "method": "POST", "url": "https://api.test/up", "skipAuth": false, "form": {"file": "@photo.png", "name": "x"}}`,
+		},
+		{
+			name:     "unknown options are reported",
+			command:  `curl --frobnicate -Z https://api.test extra`,
+			want:     `{"name": "", "method": "GET", "url": "https://api.test", "skipAuth": false}`,
+			warnings: []string{"ignored unsupported option --frobnicate", "ignored unsupported option -Z", `ignored extra argument "extra"`},
+		},
+		{name: "no URL", command: "curl -X POST", wantErr: "no URL found in the cURL command"},
+		{name: "missing value", command: "curl https://api.test -H", wantErr: "-H requires a value"},
+		{name: "invalid --json data", command: `curl https://api.test --json '{'`, wantErr: "--json data is not valid JSON"},
+		{name: "data and form together", command: `curl https://api.test -d a=1 -F b=2`, wantErr: "cannot combine -d and -F in one request"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, warnings, err := ParseCurlCommand(tt.command)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got, err := ToCompactJSON(request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want bytes.Buffer
+			if err := json.Compact(&want, []byte(tt.want)); err != nil {
+				t.Fatalf("invalid expected JSON: %v", err)
+			}
+			if got != want.String() {
+				t.Errorf("got  %s\nwant %s", got, want.String())
+			}
+			if !reflect.DeepEqual(warnings, tt.warnings) {
+				t.Errorf("got warnings %q, want %q", warnings, tt.warnings)
+			}
+		})
+	}
+}
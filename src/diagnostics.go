@@ -0,0 +1,220 @@
+package src
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Diagnostic severities
+const (
+	DiagnosticError   = "error"   // The file could not be loaded, or the request cannot be sent as written
+	DiagnosticWarning = "warning" // The request loads, but probably not the way it was meant to
+)
+
+// Diagnostic is a problem found in a request file while loading collections
+type Diagnostic struct {
+	Collection string
+	FilePath   string
+	Line       int // 1-based, 0 when the problem is not at one place in the file
+	Column     int
+	Severity   string
+	Message    string
+}
+
+// Location writes path:line:column the way compilers do, so editors can jump to it
+func (d Diagnostic) Location(path string) string {
+	switch {
+	case d.Line > 0 && d.Column > 0:
+		return fmt.Sprintf("%s:%d:%d", path, d.Line, d.Column)
+	case d.Line > 0:
+		return fmt.Sprintf("%s:%d", path, d.Line)
+	}
+	return path
+}
+
+// requestFields lists the keys request files may use, to report misspelled ones
+var requestFields = jsonFieldNames(reflect.TypeOf(RequestJSON{}))
+
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// documentDiagnostic reports a request file that could not be read or parsed
+func documentDiagnostic(collection, filePath string, err error) Diagnostic {
+	diagnostic := Diagnostic{Collection: collection, FilePath: filePath, Severity: DiagnosticError, Message: err.Error()}
+	var documentErr *DocumentError
+	if errors.As(err, &documentErr) {
+		diagnostic.Line, diagnostic.Column = documentErr.Line, documentErr.Column
+		diagnostic.Message = documentErr.Message
+	}
+	return diagnostic
+}
+
+// requestDiagnostics reports the required fields a request leaves empty. line is where the
+// request starts in the file, 0 for request files holding a single request.
+func requestDiagnostics(collection, filePath string, line int, request *RequestJSON) []Diagnostic {
+	var diagnostics []Diagnostic
+	for _, field := range []struct{ name, value string }{
+		{"name", request.Name}, {"method", request.Method}, {"url", request.URL},
+	} {
+		if strings.TrimSpace(field.value) == "" {
+			diagnostics = append(diagnostics, Diagnostic{
+				Collection: collection, FilePath: filePath, Line: line, Severity: DiagnosticError,
+				Message: fmt.Sprintf("missing required field %q", field.name),
+			})
+		}
+	}
+	return diagnostics
+}
+
+// unknownFieldDiagnostics reports top-level keys of a request file that no request field uses,
+// which are otherwise dropped without a word. Keys starting with $ ("$schema") are left alone.
+func unknownFieldDiagnostics(collection, filePath, content string, isYAML bool) []Diagnostic {
+	type key struct {
+		name         string
+		line, column int
+	}
+	var keys []key
+	if isYAML {
+		var node yaml.Node
+		if yaml.Unmarshal([]byte(content), &node) != nil || len(node.Content) == 0 || node.Content[0].Kind != yaml.MappingNode {
+			return nil
+		}
+		mapping := node.Content[0]
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			keys = append(keys, key{mapping.Content[i].Value, mapping.Content[i].Line, mapping.Content[i].Column})
+		}
+	} else {
+		document, err := ParseJSONDocument(content)
+		if err != nil || document.root.kind != '{' {
+			return nil
+		}
+		for _, member := range document.root.members {
+			line, column := textPosition(content, member.keyStart)
+			keys = append(keys, key{member.key, line, column})
+		}
+	}
+
+	var diagnostics []Diagnostic
+	for _, k := range keys {
+		if strings.HasPrefix(k.name, "$") || containsFold(requestFields, k.name) {
+			continue
+		}
+		message := fmt.Sprintf("unknown field %q is ignored", k.name)
+		if suggestion := closestName(k.name, requestFields); suggestion != "" {
+			message += fmt.Sprintf(", did you mean %q?", suggestion)
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Collection: collection, FilePath: filePath, Line: k.line, Column: k.column,
+			Severity: DiagnosticWarning, Message: message,
+		})
+	}
+	return diagnostics
+}
+
+// duplicateNameDiagnostics reports requests of a folder sharing a name (ignoring case), since
+// looking them up by name is ambiguous. lines holds where .http requests start, by RequestPath.
+func duplicateNameDiagnostics(collection Collection, lines map[string]int) []Diagnostic {
+	var diagnostics []Diagnostic
+	first := make(map[string]*RequestItem) // Keyed by folder and name, names only clash inside a folder
+	for i := range collection.Requests {
+		item := &collection.Requests[i]
+		name := strings.ToLower(strings.TrimSpace(item.Name))
+		if name == "" {
+			continue
+		}
+		key := item.Folder + "/" + name
+		original, exists := first[key]
+		if !exists {
+			first[key] = item
+			continue
+		}
+		other := original.FileName
+		if original.Section != "" {
+			other += "#" + original.Section
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Collection: collection.Name, FilePath: item.FilePath, Line: lines[item.RequestPath()], Severity: DiagnosticWarning,
+			Message: fmt.Sprintf("request name %q is already used by %s in %s", item.Name, other, collection.Name),
+		})
+	}
+	return diagnostics
+}
+
+// SortDiagnostics orders diagnostics by file, then position
+func SortDiagnostics(diagnostics []Diagnostic) {
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		a, b := diagnostics[i], diagnostics[j]
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
+
+// CountDiagnostics counts errors and warnings
+func CountDiagnostics(diagnostics []Diagnostic) (int, int) {
+	errorCount := 0
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity == DiagnosticError {
+			errorCount++
+		}
+	}
+	return errorCount, len(diagnostics) - errorCount
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// closestName finds the name a misspelling most likely meant: at most two edits away
+func closestName(value string, names []string) string {
+	best, bestDistance := "", 3
+	for _, name := range names {
+		if distance := editDistance(strings.ToLower(value), strings.ToLower(name)); distance < bestDistance {
+			best, bestDistance = name, distance
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,132 @@
+package src
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// showDiagnosticsMsg opens the list of problems found while loading request files
+type showDiagnosticsMsg struct{}
+
+// reloadCollectionsMsg asks for the request files to be read again, after fixing them in an editor
+type reloadCollectionsMsg struct{}
+
+// DiagnosticsViewModel lists the request files that failed to load or have problems,
+// with the line and column to look at
+type DiagnosticsViewModel struct {
+	configLoader *ConfigLoader
+	diagnostics  []Diagnostic
+	lines        []responseLine
+	offset       int
+	width        int
+	height       int
+	styles       *Styles
+}
+
+func NewDiagnosticsViewModel(configLoader *ConfigLoader) DiagnosticsViewModel {
+	m := DiagnosticsViewModel{
+		configLoader: configLoader,
+		diagnostics:  configLoader.Diagnostics(),
+		width:        80,
+		height:       24,
+		styles:       DefaultStyles(),
+	}
+	m.refresh()
+	return m
+}
+
+func (m DiagnosticsViewModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m DiagnosticsViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.refresh()
+		return m, nil
+
+	case collectionsReloadedMsg:
+		m.diagnostics = m.configLoader.Diagnostics()
+		m.refresh()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc":
+			return m, emit(navigateBackMsg{})
+		case "r":
+			return m, emit(reloadCollectionsMsg{})
+		case "up", "k":
+			m.scrollTo(m.offset - 1)
+		case "down", "j":
+			m.scrollTo(m.offset + 1)
+		case "pgup", "b":
+			m.scrollTo(m.offset - m.pageHeight())
+		case "pgdown", " ":
+			m.scrollTo(m.offset + m.pageHeight())
+		case "g", "home":
+			m.scrollTo(0)
+		case "G", "end":
+			m.scrollTo(len(m.lines))
+		}
+	}
+
+	return m, nil
+}
+
+func (m DiagnosticsViewModel) View() string {
+	var b strings.Builder
+
+	b.WriteString("\n")
+	errorCount, warningCount := CountDiagnostics(m.diagnostics)
+	title := fmt.Sprintf("  ⚠️  Request file problems: %s, %s", pluralize(errorCount, "error"), pluralize(warningCount, "warning"))
+	b.WriteString(m.styles.Text(title, m.styles.SelectedTitleColor))
+	b.WriteString("\n\n")
+
+	end := min(m.offset+m.pageHeight(), len(m.lines))
+	for i := m.offset; i < end; i++ {
+		b.WriteString(m.styles.Text("  "+m.lines[i].text, m.lines[i].color))
+		b.WriteString("\n")
+	}
+	for i := end - m.offset; i < m.pageHeight(); i++ {
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.styles.FooterStyle.Render("  r reload files • ↑↓/jk/pgup/pgdn scroll • q/esc back"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// pageHeight is the number of lines that fit between header and footer
+func (m DiagnosticsViewModel) pageHeight() int {
+	return max(m.height-6, 3)
+}
+
+func (m *DiagnosticsViewModel) scrollTo(offset int) {
+	m.offset = max(0, min(offset, len(m.lines)-m.pageHeight()))
+}
+
+func (m *DiagnosticsViewModel) refresh() {
+	var lines []responseLine
+	if len(m.diagnostics) == 0 {
+		lines = append(lines, responseLine{text: "✓ All request files loaded without problems", color: m.styles.AquamarineColor})
+	}
+	for _, diagnostic := range m.diagnostics {
+		icon, color := "⚠", m.styles.PeachColor
+		if diagnostic.Severity == DiagnosticError {
+			icon, color = "✗", m.styles.ErrorColor
+		}
+		location := diagnostic.Location(m.configLoader.RequestKey(diagnostic.FilePath))
+		lines = append(lines,
+			responseLine{text: fmt.Sprintf("%s %s  %s", icon, location, diagnostic.Severity), color: color},
+			responseLine{text: "    " + diagnostic.Message, color: m.styles.FooterColor},
+		)
+	}
+	m.lines = wrapResponseLines(lines, max(m.width-4, 20))
+	m.scrollTo(m.offset)
+}
@@ -0,0 +1,110 @@
+package src
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// diffCommand implements "postless diff <request>".
+// It exits with ExitCodeFailure when the responses differ or the request fails.
+func (r *Runner) diffCommand(args []string) int {
+	fs := newCommandFlagSet("diff", "diff <collection>/<request>|<file> [flags]")
+	noSend := fs.Bool("no-send", false, "compare the two latest recorded runs instead of sending the request")
+	environment := fs.String("env", "", "environment to use instead of the active one")
+	ignoreHeaders := fs.String("ignore-headers", strings.Join(DefaultIgnoredDiffHeaders, ","), "comma separated headers left out of the comparison")
+	filter := fs.String("filter", "", "JSONPath or jq-like filter applied to both bodies before comparing")
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return ExitCodeError
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return ExitCodeError
+	}
+
+	collections, err := r.loadProjectForCommand(*environment)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+
+	item, err := r.findRequest(collections, positional[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+
+	history, err := r.configLoader.LoadHistory(r.config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+
+	requestFile := r.configLoader.RequestKey(item.RequestPath())
+	var runs []HistoryEntryJSON
+	for _, entry := range history {
+		if entry.RequestFile == requestFile {
+			runs = append(runs, entry)
+		}
+	}
+
+	var before, after HistoryEntryJSON
+	if *noSend {
+		if len(runs) < 2 {
+			fmt.Fprintf(os.Stderr, "Need two recorded runs of %s to compare, found %d\n", item.Name, len(runs))
+			return ExitCodeError
+		}
+		before, after = runs[len(runs)-2], runs[len(runs)-1]
+	} else {
+		if len(runs) == 0 {
+			fmt.Fprintf(os.Stderr, "No recorded run of %s to compare with, run it once first\n", item.Name)
+			return ExitCodeError
+		}
+		before = runs[len(runs)-1]
+
+		httpClient := NewHTTPClient(r.config, r.secret, r.configLoader)
+		response, _ := httpClient.ExecuteRequest(item.Request, item.Variables)
+		after, err = r.configLoader.RecordHistory(r.config, item, response)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to record history:", err)
+		}
+		if response.Error != nil {
+			// Nothing to compare, and two failed runs must not pass as "no changes"
+			fmt.Fprintf(os.Stderr, "Request failed: %s\n", formatError(response.Error))
+			return ExitCodeFailure
+		}
+	}
+
+	beforeResponse, afterResponse := before.Response(), after.Response()
+	if *filter != "" {
+		for _, response := range []**HTTPResponse{&beforeResponse, &afterResponse} {
+			if *response, err = filterResponse(*response, *filter); err != nil {
+				fmt.Fprintf(os.Stderr, "Filter failed: %v\n", err)
+				return ExitCodeError
+			}
+		}
+	}
+
+	diff := DiffResponses(beforeResponse, afterResponse, strings.Split(*ignoreHeaders, ","))
+	r.printDiff(diff, item.Name, before, after)
+
+	if !diff.Empty() {
+		return ExitCodeFailure
+	}
+	return ExitCodeOK
+}
+
+func (r *Runner) printDiff(diff ResponseDiff, requestName string, before, after HistoryEntryJSON) {
+	styles := DefaultStyles()
+
+	fmt.Println()
+	fmt.Println(styles.Text("  ⇄ Diff: "+requestName, styles.SelectedTitleColor))
+	fmt.Println(styles.Text(fmt.Sprintf("  - %s   + %s", historyEntryLabel(before), historyEntryLabel(after)), styles.MutedTitleColor))
+	fmt.Println()
+	for _, line := range renderDiffLines(diff, styles) {
+		fmt.Println(styles.Text("  "+line.text, line.color))
+	}
+	fmt.Println()
+}
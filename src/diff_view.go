@@ -0,0 +1,137 @@
+package src
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// diffResponsesMsg opens a diff between two recorded executions of a request
+type diffResponsesMsg struct {
+	before HistoryEntryJSON
+	after  HistoryEntryJSON
+}
+
+// diffPreviousMsg diffs a response against the execution of the same request recorded before it
+type diffPreviousMsg struct {
+	item     *RequestItem
+	response *HTTPResponse
+}
+
+// DiffViewModel shows what changed between two responses of the same request
+type DiffViewModel struct {
+	title       string
+	beforeLabel string
+	afterLabel  string
+	diff        ResponseDiff
+	lines       []responseLine
+	offset      int
+	width       int
+	height      int
+	styles      *Styles
+}
+
+func NewDiffViewModel(title, beforeLabel, afterLabel string, diff ResponseDiff) DiffViewModel {
+	m := DiffViewModel{
+		title:       title,
+		beforeLabel: beforeLabel,
+		afterLabel:  afterLabel,
+		diff:        diff,
+		width:       80,
+		height:      24,
+		styles:      DefaultStyles(),
+	}
+	m.refresh()
+	return m
+}
+
+func (m DiffViewModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m DiffViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.refresh()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc":
+			return m, emit(navigateBackMsg{})
+		case "up", "k":
+			m.scrollTo(m.offset - 1)
+		case "down", "j":
+			m.scrollTo(m.offset + 1)
+		case "pgup", "b":
+			m.scrollTo(m.offset - m.pageHeight())
+		case "pgdown", " ":
+			m.scrollTo(m.offset + m.pageHeight())
+		case "g", "home":
+			m.scrollTo(0)
+		case "G", "end":
+			m.scrollTo(len(m.lines))
+		}
+	}
+
+	return m, nil
+}
+
+func (m DiffViewModel) View() string {
+	var b strings.Builder
+
+	b.WriteString("\n")
+	b.WriteString(m.styles.Text("  ⇄ Diff: "+m.title, m.styles.SelectedTitleColor))
+	b.WriteString("\n")
+	labels := fmt.Sprintf("  - %s   + %s", m.beforeLabel, m.afterLabel)
+	b.WriteString(m.styles.Text(lipgloss.NewStyle().MaxWidth(max(m.width-2, 20)).Render(labels), m.styles.MutedTitleColor))
+	b.WriteString("\n\n")
+
+	end := min(m.offset+m.pageHeight(), len(m.lines))
+	for i := m.offset; i < end; i++ {
+		b.WriteString(m.styles.Text("  "+m.lines[i].text, m.lines[i].color))
+		b.WriteString("\n")
+	}
+	for i := end - m.offset; i < m.pageHeight(); i++ {
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	summary := "no differences"
+	if !m.diff.Empty() {
+		summary = pluralize(len(m.diff.Headers)+len(m.diff.Body), "difference")
+		if m.diff.StatusChanged() {
+			summary += " • status changed"
+		}
+	}
+	b.WriteString(m.styles.FooterStyle.Render("  " + summary + " • ↑↓/jk/pgup/pgdn scroll • q/esc back"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// pageHeight is the number of diff lines that fit between header and footer
+func (m DiffViewModel) pageHeight() int {
+	return max(m.height-7, 3)
+}
+
+func (m *DiffViewModel) scrollTo(offset int) {
+	m.offset = max(0, min(offset, len(m.lines)-m.pageHeight()))
+}
+
+func (m *DiffViewModel) refresh() {
+	m.lines = wrapResponseLines(renderDiffLines(m.diff, m.styles), max(m.width-4, 20))
+	m.scrollTo(m.offset)
+}
+
+// historyEntryLabel describes an execution for the diff header
+func historyEntryLabel(entry HistoryEntryJSON) string {
+	label := entry.Timestamp.Local().Format("Jan 02 15:04:05")
+	if entry.Environment != "" {
+		label += " (" + entry.Environment + ")"
+	}
+	return label
+}
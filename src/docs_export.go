@@ -0,0 +1,417 @@
+package src
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// docsSampleMaxLines limits sample responses so large payloads do not drown the documentation
+const docsSampleMaxLines = 60
+
+// APIDocs is the documentation model shared by the Markdown, HTML and OpenAPI exporters
+type APIDocs struct {
+	Title       string
+	BaseURL     string
+	Collections []DocsCollection
+}
+
+type DocsCollection struct {
+	Name     string
+	Anchor   string
+	Requests []DocsRequest
+}
+
+// DocsRequest describes a request as written in its file, with configured variables filled in
+type DocsRequest struct {
+	Collection  string
+	Name        string
+	FileName    string
+	Anchor      string
+	Method      string
+	URL         string
+	Auth        bool        // Sent with the JWT as a bearer token
+	Headers     [][2]string // Global, environment and request headers
+	ContentType string
+	Body        interface{}
+	RawBody     string
+	Form        map[string]string
+	Sample      *HistoryEntryJSON // Latest successful execution, nil without history
+}
+
+// BuildAPIDocs collects what the exporters need from loaded collections. history may be nil;
+// otherwise the latest execution of each request that got a response becomes its sample.
+func (cl *ConfigLoader) BuildAPIDocs(title string, config *ConfigJSON, collections []Collection, history []HistoryEntryJSON) APIDocs {
+	samples := make(map[string]*HistoryEntryJSON)
+	for i := range history {
+		if history[i].Error == "" && history[i].StatusCode > 0 {
+			samples[history[i].RequestFile] = &history[i]
+		}
+	}
+
+	docs := APIDocs{Title: title, BaseURL: cl.GetBaseURL(config)}
+	anchors := make(map[string]int)
+	for _, collection := range collections {
+		docsCollection := DocsCollection{Name: collection.Name, Anchor: uniqueAnchor(anchors, collection.Name)}
+		for _, item := range collection.Requests {
+			request := cl.buildDocsRequest(config, collection.Name, item)
+			request.Anchor = uniqueAnchor(anchors, request.Name)
+			request.Sample = samples[cl.RequestKey(item.RequestPath())]
+			docsCollection.Requests = append(docsCollection.Requests, request)
+		}
+		docs.Collections = append(docs.Collections, docsCollection)
+	}
+	return docs
+}
+
+func (cl *ConfigLoader) buildDocsRequest(config *ConfigJSON, collection string, item RequestItem) DocsRequest {
+	req := item.Request
+	// Captured values (tokens, ids) stay as {{name}}: they document what callers must provide
+	resolver := cl.NewStaticResolver(config, item.Variables)
+	request := DocsRequest{
+		Collection: collection,
+		Name:       item.Name,
+		FileName:   item.FileName,
+		Method:     req.Method,
+		URL:        resolver.ResolveLenient(req.URL),
+		Auth:       !req.SkipAuth,
+		Body:       req.Body,
+		RawBody:    req.RawBody,
+		Form:       req.Form,
+	}
+	if item.Section != "" {
+		request.FileName = item.Section // Requests of .http files share their file
+	}
+
+	headers := make(map[string]string)
+	for name, value := range config.GlobalHeaders {
+		headers[name] = value
+	}
+	if env := config.GetActiveEnvironment(); env != nil {
+		for name, value := range env.Headers {
+			headers[name] = value
+		}
+	}
+	for name, value := range req.Headers {
+		if existing, ok := lookupHeader(headers, name); ok {
+			delete(headers, existing)
+		}
+		headers[name] = value
+	}
+
+	contentTypeName, hasContentType := lookupHeader(headers, "Content-Type")
+	switch {
+	case len(req.Form) > 0:
+		request.ContentType = "multipart/form-data"
+	case hasContentType:
+		request.ContentType = headers[contentTypeName]
+	case req.Body != nil:
+		request.ContentType = "application/json"
+	case req.RawBody != "":
+		request.ContentType = "text/plain"
+	}
+	if hasContentType && req.Body == nil && req.RawBody == "" {
+		delete(headers, contentTypeName) // Global default that does not apply without a body
+	}
+
+	for _, name := range sortedStringKeys(headers) {
+		request.Headers = append(request.Headers, [2]string{name, resolver.ResolveLenient(headers[name])})
+	}
+	return request
+}
+
+// HasBody reports whether the request sends a body
+func (r DocsRequest) HasBody() bool {
+	return r.Body != nil || r.RawBody != "" || len(r.Form) > 0
+}
+
+// BodyText returns the request body for display: indented JSON, raw text or form fields
+func (r DocsRequest) BodyText() string {
+	switch {
+	case len(r.Form) > 0:
+		var lines []string
+		for _, name := range sortedStringKeys(r.Form) {
+			lines = append(lines, name+"="+r.Form[name])
+		}
+		return strings.Join(lines, "\n")
+	case r.RawBody != "":
+		return r.RawBody
+	case r.Body != nil:
+		body, err := ToJSON(r.Body)
+		if err != nil {
+			return fmt.Sprint(r.Body)
+		}
+		return body
+	}
+	return ""
+}
+
+// BodyLanguage is the fenced code block language of the request body
+func (r DocsRequest) BodyLanguage() string {
+	if r.Body != nil || strings.Contains(r.ContentType, "json") {
+		return "json"
+	}
+	return "text"
+}
+
+// SampleContentType returns the content type of the sample response, without parameters
+func (r DocsRequest) SampleContentType() string {
+	if r.Sample == nil {
+		return ""
+	}
+	for name, values := range r.Sample.Headers {
+		if strings.EqualFold(name, "Content-Type") && len(values) > 0 {
+			contentType, _, _ := strings.Cut(values[0], ";")
+			return strings.TrimSpace(contentType)
+		}
+	}
+	return ""
+}
+
+// SampleJSON returns the decoded sample body when it is JSON
+func (r DocsRequest) SampleJSON() (interface{}, bool) {
+	if r.Sample == nil || r.Sample.BodyEncoding != "" {
+		return nil, false
+	}
+	var data interface{}
+	if err := json.Unmarshal([]byte(r.Sample.Body), &data); err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// SampleText returns the sample response body indented when it is JSON, cut after
+// docsSampleMaxLines lines
+func (r DocsRequest) SampleText() string {
+	if r.Sample == nil {
+		return ""
+	}
+	if r.Sample.BodyEncoding != "" {
+		return fmt.Sprintf("(%s of binary data)", formatBytes(int64(len(r.Sample.Response().Body))))
+	}
+
+	text := r.Sample.Body
+	if data, ok := r.SampleJSON(); ok {
+		if indented, err := ToJSON(data); err == nil {
+			text = indented
+		}
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) > docsSampleMaxLines {
+		lines = append(lines[:docsSampleMaxLines], fmt.Sprintf("… %d more lines", len(lines)-docsSampleMaxLines))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SampleLanguage is the fenced code block language of the sample response
+func (r DocsRequest) SampleLanguage() string {
+	if _, ok := r.SampleJSON(); ok {
+		return "json"
+	}
+	return "text"
+}
+
+// SampleStatus returns "200 OK" for the sample response
+func (r DocsRequest) SampleStatus() string {
+	if r.Sample == nil {
+		return ""
+	}
+	if r.Sample.Status != "" {
+		return r.Sample.Status
+	}
+	return fmt.Sprintf("%d %s", r.Sample.StatusCode, http.StatusText(r.Sample.StatusCode))
+}
+
+// SampleRecorded describes when and where the sample was recorded
+func (r DocsRequest) SampleRecorded() string {
+	if r.Sample == nil {
+		return ""
+	}
+	recorded := r.Sample.Timestamp.Local().Format("2006-01-02 15:04")
+	if r.Sample.Environment != "" {
+		recorded += " (" + r.Sample.Environment + ")"
+	}
+	return recorded
+}
+
+// RequestCount returns the number of documented requests
+func (d APIDocs) RequestCount() int {
+	count := 0
+	for _, collection := range d.Collections {
+		count += len(collection.Requests)
+	}
+	return count
+}
+
+// ExportMarkdown renders the documentation as a Markdown document
+func ExportMarkdown(docs APIDocs) string {
+	var blocks []string
+	add := func(format string, args ...interface{}) {
+		blocks = append(blocks, fmt.Sprintf(format, args...))
+	}
+
+	add("# %s", docs.Title)
+	if docs.BaseURL != "" {
+		add("Base URL: `%s`", docs.BaseURL)
+	}
+	add("%s in %s.", pluralize(docs.RequestCount(), "request"), pluralize(len(docs.Collections), "collection"))
+
+	var contents []string
+	for _, collection := range docs.Collections {
+		contents = append(contents, fmt.Sprintf("- [%s](#%s)", markdownEscape(collection.Name), collection.Anchor))
+		for _, request := range collection.Requests {
+			contents = append(contents, fmt.Sprintf("  - [%s](#%s) `%s`", markdownEscape(request.Name), request.Anchor, request.Method))
+		}
+	}
+	add("## Contents")
+	add("%s", strings.Join(contents, "\n"))
+
+	for _, collection := range docs.Collections {
+		add("## %s", markdownEscape(collection.Name))
+
+		for _, request := range collection.Requests {
+			add("### %s", markdownEscape(request.Name))
+			add("```http\n%s %s\n```", request.Method, request.URL)
+			if request.Auth {
+				add("Requires a bearer token (`Authorization: Bearer <JWT>`).")
+			}
+
+			if len(request.Headers) > 0 {
+				table := []string{"| Header | Value |", "|--------|-------|"}
+				for _, header := range request.Headers {
+					table = append(table, fmt.Sprintf("| `%s` | `%s` |", markdownTableEscape(header[0]), markdownTableEscape(header[1])))
+				}
+				add("%s", strings.Join(table, "\n"))
+			}
+
+			if request.HasBody() {
+				add("**Request body** (`%s`)", request.ContentType)
+				add("```%s\n%s\n```", request.BodyLanguage(), request.BodyText())
+			}
+
+			if request.Sample != nil {
+				add("**Sample response** `%s`, recorded %s", request.SampleStatus(), request.SampleRecorded())
+				add("```%s\n%s\n```", request.SampleLanguage(), request.SampleText())
+			}
+		}
+	}
+
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+var docsHTMLTemplate = template.Must(template.New("docs").Funcs(template.FuncMap{
+	"methodClass": func(method string) string { return strings.ToLower(method) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+  body { margin: 0; font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; color: #24292f; display: flex; }
+  nav { width: 280px; height: 100vh; position: sticky; top: 0; overflow-y: auto; background: #f6f8fa; border-right: 1px solid #d0d7de; padding: 16px; box-sizing: border-box; font-size: 14px; }
+  nav ul { list-style: none; padding-left: 12px; margin: 4px 0 12px; }
+  nav a { color: #24292f; text-decoration: none; }
+  nav a:hover { text-decoration: underline; }
+  main { flex: 1; max-width: 960px; padding: 24px 40px; }
+  h2 { border-bottom: 1px solid #d0d7de; padding-bottom: 6px; margin-top: 48px; }
+  section.request { margin: 28px 0; }
+  .endpoint { font-family: ui-monospace, SFMono-Regular, Menlo, monospace; background: #f6f8fa; padding: 8px 12px; border-radius: 6px; word-break: break-all; }
+  .method { display: inline-block; min-width: 56px; font-weight: 600; }
+  .get { color: #1a7f37; } .post { color: #bc4c00; } .put { color: #8250df; } .delete { color: #cf222e; } .patch { color: #0969da; }
+  table { border-collapse: collapse; margin: 12px 0; font-size: 14px; }
+  th, td { border: 1px solid #d0d7de; padding: 4px 10px; text-align: left; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; }
+  th { background: #f6f8fa; font-family: inherit; }
+  pre { background: #f6f8fa; padding: 12px; border-radius: 6px; overflow-x: auto; font-size: 13px; }
+  .note { color: #57606a; font-size: 14px; }
+</style>
+</head>
+<body>
+<nav>
+  <strong>{{.Title}}</strong>
+  {{- range .Collections}}
+  <p><a href="#{{.Anchor}}">{{.Name}}</a></p>
+  <ul>
+    {{- range .Requests}}
+    <li><a href="#{{.Anchor}}"><span class="method {{methodClass .Method}}">{{.Method}}</span> {{.Name}}</a></li>
+    {{- end}}
+  </ul>
+  {{- end}}
+</nav>
+<main>
+  <h1>{{.Title}}</h1>
+  {{- if .BaseURL}}
+  <p class="note">Base URL: <code>{{.BaseURL}}</code></p>
+  {{- end}}
+  {{- range .Collections}}
+  <h2 id="{{.Anchor}}">{{.Name}}</h2>
+  {{- range .Requests}}
+  <section class="request" id="{{.Anchor}}">
+    <h3>{{.Name}}</h3>
+    <div class="endpoint"><span class="method {{methodClass .Method}}">{{.Method}}</span> {{.URL}}</div>
+    {{- if .Auth}}
+    <p class="note">Requires a bearer token (<code>Authorization: Bearer &lt;JWT&gt;</code>).</p>
+    {{- end}}
+    {{- if .Headers}}
+    <table>
+      <tr><th>Header</th><th>Value</th></tr>
+      {{- range .Headers}}
+      <tr><td>{{index . 0}}</td><td>{{index . 1}}</td></tr>
+      {{- end}}
+    </table>
+    {{- end}}
+    {{- if .HasBody}}
+    <h4>Request body <span class="note">{{.ContentType}}</span></h4>
+    <pre>{{.BodyText}}</pre>
+    {{- end}}
+    {{- if .Sample}}
+    <h4>Sample response <span class="note">{{.SampleStatus}}, recorded {{.SampleRecorded}}</span></h4>
+    <pre>{{.SampleText}}</pre>
+    {{- end}}
+  </section>
+  {{- end}}
+  {{- end}}
+</main>
+</body>
+</html>
+`))
+
+// ExportHTML renders the documentation as a standalone HTML page
+func ExportHTML(docs APIDocs) (string, error) {
+	var b bytes.Buffer
+	if err := docsHTMLTemplate.Execute(&b, docs); err != nil {
+		return "", fmt.Errorf("ExportHTML -> %v", err)
+	}
+	return b.String(), nil
+}
+
+// uniqueAnchor returns a GitHub-style heading anchor, suffixed with -1, -2... when taken
+func uniqueAnchor(used map[string]int, heading string) string {
+	var b strings.Builder
+	for _, c := range strings.ToLower(heading) {
+		switch {
+		case c == ' ' || c == '-':
+			b.WriteRune('-')
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c > 127:
+			b.WriteRune(c)
+		}
+	}
+	anchor := b.String()
+	count := used[anchor]
+	used[anchor] = count + 1
+	if count > 0 {
+		return fmt.Sprintf("%s-%d", anchor, count)
+	}
+	return anchor
+}
+
+func markdownEscape(text string) string {
+	return strings.NewReplacer("*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "`", "\\`", "<", "&lt;").Replace(text)
+}
+
+func markdownTableEscape(text string) string {
+	return strings.NewReplacer("|", `\|`, "`", "'", "\n", " ").Replace(text)
+}
@@ -0,0 +1,286 @@
+package src
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+var exportUsage = `Usage:
+  postless export <format> <collection>/<request>|<file> [--env <name>]
+  postless export <openapi|markdown|html> [collection...] [--env <name>] [--output <file>] [--no-samples]
+  postless export har [collection|collection/request...] [--last <n>] [--output <file>] [--include-secrets]
+  postless export http <collection> [--output <file>]
+
+Request formats: ` + strings.Join(codeGeneratorNames(), ", ") + `
+The request is resolved exactly as it would be sent: variables, merged headers and body.
+
+Documentation formats describe whole collections: method, URL, headers, example body and
+the latest recorded response from history. OpenAPI is written as JSON, or YAML when the
+output file ends with .yaml or .yml.
+
+HAR exports recorded executions from history (TUI sends, run, diff and test) with their
+requests exactly as sent. Authorization, Cookie and Set-Cookie values are redacted unless
+--include-secrets is given.
+
+HTTP exports write a collection as a VS Code REST Client file (.http), with the collection
+variables declared at the top. Variables from config.json are left as {{name}} references.
+`
+
+// exportCommand implements "postless export <format> <request>"
+func (r *Runner) exportCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, exportUsage)
+		return ExitCodeError
+	}
+
+	switch args[0] {
+	case "help", "-h", "--help":
+		fmt.Print(exportUsage)
+		return ExitCodeOK
+	case "openapi", "markdown", "md", "html":
+		return r.exportDocsCommand(args[0], args[1:])
+	case "har":
+		return r.exportHARCommand(args[1:])
+	case "http", "rest":
+		return r.exportHTTPFileCommand(args[1:])
+	}
+
+	generator, ok := findCodeGenerator(args[0])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown export format %q\n\n%s", args[0], exportUsage)
+		return ExitCodeError
+	}
+	return r.exportRequestCommand(generator.Name, args[1:], generator.Generate)
+}
+
+// exportRequestCommand resolves one request and prints it in an export format
+func (r *Runner) exportRequestCommand(format string, args []string, export func(*ResolvedRequest) (string, error)) int {
+	fs := newCommandFlagSet("export "+format, "export "+format+" <collection>/<request>|<file> [flags]")
+	environment := fs.String("env", "", "environment to use instead of the active one")
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return ExitCodeError
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return ExitCodeError
+	}
+
+	collections, err := r.loadProjectForCommand(*environment)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+
+	item, err := r.findRequest(collections, positional[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+
+	resolved, err := NewHTTPClient(r.config, r.secret, r.configLoader).ResolveRequest(item.Request, item.Variables)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to resolve variables: %v\n", err)
+		return ExitCodeError
+	}
+
+	output, err := export(resolved)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		return ExitCodeError
+	}
+	fmt.Println(output)
+	return ExitCodeOK
+}
+
+// exportDocsCommand documents collections as an OpenAPI skeleton, Markdown or HTML
+func (r *Runner) exportDocsCommand(format string, args []string) int {
+	fs := newCommandFlagSet("export "+format, "export "+format+" [collection...] [flags]")
+	environment := fs.String("env", "", "environment whose base URL and headers are documented")
+	output := fs.String("output", "", "file to write instead of stdout")
+	noSamples := fs.Bool("no-samples", false, "leave recorded responses out of the documentation")
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return ExitCodeError
+	}
+
+	collections, err := r.loadProjectForCommand(*environment)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+	selected, err := selectCollections(collections, positional)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+
+	var history []HistoryEntryJSON
+	if !*noSamples {
+		if history, err = r.configLoader.LoadHistory(r.config); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to load history:", err)
+		}
+	}
+
+	title, err := r.fileManager.GetCurrentDirectoryName()
+	if err != nil {
+		title = "API"
+	}
+	docs := r.configLoader.BuildAPIDocs(title, r.config, selected, history)
+
+	var content string
+	var warnings []string
+	switch format {
+	case "openapi":
+		asYAML := strings.HasSuffix(*output, ".yaml") || strings.HasSuffix(*output, ".yml")
+		content, warnings, err = ExportOpenAPI(docs, asYAML)
+	case "html":
+		content, err = ExportHTML(docs)
+	default:
+		content = ExportMarkdown(docs)
+	}
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, "Warning:", warning)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		return ExitCodeError
+	}
+
+	if *output == "" {
+		fmt.Print(content)
+		return ExitCodeOK
+	}
+	if err := os.WriteFile(*output, []byte(content), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		return ExitCodeError
+	}
+	styles := DefaultStyles()
+	fmt.Println(styles.Text(fmt.Sprintf("✓ Documented %s in %s", pluralize(docs.RequestCount(), "request"), *output), styles.AquamarineColor))
+	return ExitCodeOK
+}
+
+// exportHARCommand writes recorded executions of requests as a HAR archive
+func (r *Runner) exportHARCommand(args []string) int {
+	fs := newCommandFlagSet("export har", "export har [collection|collection/request...] [flags]")
+	last := fs.Int("last", 0, "only export the n most recent executions")
+	output := fs.String("output", "", "file to write instead of stdout")
+	includeSecrets := fs.Bool("include-secrets", false, "keep Authorization, Cookie and Set-Cookie values")
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return ExitCodeError
+	}
+
+	collections, err := r.loadProjectForCommand("")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+
+	// Request keys of the selected requests, every recorded execution when empty
+	keys := make(map[string]bool)
+	for _, ref := range positional {
+		if strings.Contains(ref, "/") {
+			item, err := r.findRequest(collections, ref)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return ExitCodeError
+			}
+			keys[r.configLoader.RequestKey(item.RequestPath())] = true
+			continue
+		}
+		selected, err := selectCollections(collections, []string{ref})
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return ExitCodeError
+		}
+		for _, item := range selected[0].Requests {
+			keys[r.configLoader.RequestKey(item.RequestPath())] = true
+		}
+	}
+
+	history, err := r.configLoader.LoadHistory(r.config)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load history:", err)
+		return ExitCodeError
+	}
+	var entries []HistoryEntryJSON
+	for _, entry := range history {
+		// Entries without a request failed to resolve variables, nothing was sent
+		if entry.Request != nil && (len(keys) == 0 || keys[entry.RequestFile]) {
+			entries = append(entries, entry)
+		}
+	}
+	if *last > 0 && len(entries) > *last {
+		entries = entries[len(entries)-*last:]
+	}
+
+	content, err := ExportHAR(entries, *includeSecrets)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		return ExitCodeError
+	}
+	if *output == "" {
+		fmt.Print(content)
+		return ExitCodeOK
+	}
+	if err := os.WriteFile(*output, []byte(content), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		return ExitCodeError
+	}
+	styles := DefaultStyles()
+	fmt.Println(styles.Text(fmt.Sprintf("✓ Exported %s to %s", pluralize(len(entries), "execution"), *output), styles.AquamarineColor))
+	return ExitCodeOK
+}
+
+// exportHTTPFileCommand writes a collection as an .http file
+func (r *Runner) exportHTTPFileCommand(args []string) int {
+	fs := newCommandFlagSet("export http", "export http <collection> [flags]")
+	output := fs.String("output", "", "file to write instead of stdout")
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return ExitCodeError
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return ExitCodeError
+	}
+
+	collections, err := r.loadProjectForCommand("")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+	selected, err := selectCollections(collections, positional)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+	collection := selected[0]
+
+	content, warnings, err := FormatHTTPFile(collection.Variables, collection.Requests)
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, "Warning:", warning)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		return ExitCodeError
+	}
+
+	if *output == "" {
+		fmt.Print(content)
+		return ExitCodeOK
+	}
+	if err := os.WriteFile(*output, []byte(content), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		return ExitCodeError
+	}
+	styles := DefaultStyles()
+	fmt.Println(styles.Text(fmt.Sprintf("✓ Exported %s to %s", pluralize(len(collection.Requests), "request"), *output), styles.AquamarineColor))
+	return ExitCodeOK
+}
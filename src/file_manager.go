@@ -4,22 +4,30 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 type FileManagerInterface interface {
 	CheckIfPathExists(path string) (bool, error)
 	ReadFileContent(filePath string) (string, error)
 	WriteFileContent(filePath, content string) error
+	GetConfigPath() string
 	GetConfigContent() (string, error)
 	WriteConfigContent(content string) error
 	GetSecretContent() (string, error)
 	WriteSecretContent(content string) error
+	GetStateContent() (string, error)
+	WriteStateContent(content string) error
+	GetHistoryContent() (string, error)
+	AppendHistoryContent(line string) error
+	WriteHistoryContent(content string) error
 	CheckPostlessDir() (bool, error)
 	CheckConfigYML() (bool, error)
 	CheckSecretJSON() (bool, error)
 	CheckRequestsDir() (bool, error)
 	GetCollections() ([]string, error)
 	GetRequestFiles(collectionName string) ([]string, error)
+	CreateCollectionDir(collectionName string) (string, error)
 	GetCurrentDirectoryName() (string, error)
 	SaveRequestJSON(filePath string, request *RequestJSON) error
 }
@@ -29,6 +37,8 @@ type FileManager struct {
 	PostlessDir       string
 	ConfigPath        string
 	SecretPath        string
+	StatePath         string
+	HistoryPath       string
 	RequestsDir       string
 	PostlessDirExists bool
 }
@@ -42,6 +52,8 @@ func NewFileManager() (*FileManager, error) {
 	postlessDir := filepath.Join(currentDir, PostlessDirName)
 	configPath := filepath.Join(postlessDir, ConfigFileName)
 	secretPath := filepath.Join(postlessDir, SecretFileName)
+	statePath := filepath.Join(postlessDir, StateFileName)
+	historyPath := filepath.Join(postlessDir, HistoryFileName)
 	requestsDir := filepath.Join(postlessDir, RequestsDirName)
 
 	return &FileManager{
@@ -49,6 +61,8 @@ func NewFileManager() (*FileManager, error) {
 		PostlessDir:       postlessDir,
 		ConfigPath:        configPath,
 		SecretPath:        secretPath,
+		StatePath:         statePath,
+		HistoryPath:       historyPath,
 		RequestsDir:       requestsDir,
 		PostlessDirExists: false,
 	}, nil
@@ -74,12 +88,20 @@ func (m *FileManager) CheckPostlessDir() (bool, error) {
 	return exists, nil
 }
 
+// CheckConfigYML looks for config.json, then config.yaml and config.yml, and uses the first one found
 func (m *FileManager) CheckConfigYML() (bool, error) {
-	exists, err := m.CheckIfPathExists(m.ConfigPath)
-	if err != nil {
-		return false, fmt.Errorf("CheckConfigYML -> %v", err)
+	for _, name := range []string{ConfigFileName, ConfigYAMLName, ConfigYMLName} {
+		configPath := filepath.Join(m.PostlessDir, name)
+		exists, err := m.CheckIfPathExists(configPath)
+		if err != nil {
+			return false, fmt.Errorf("CheckConfigYML -> %v", err)
+		}
+		if exists {
+			m.ConfigPath = configPath
+			return true, nil
+		}
 	}
-	return exists, nil
+	return false, nil
 }
 
 func (m *FileManager) CheckSecretJSON() (bool, error) {
@@ -125,23 +147,55 @@ func (m *FileManager) GetCollections() ([]string, error) {
 	return collections, nil
 }
 
+// GetRequestFiles lists the request files of a collection and of its folders, as slash-separated
+// paths relative to the collection in name order. Hidden folders are skipped.
 func (m *FileManager) GetRequestFiles(collectionName string) ([]string, error) {
 	collectionPath := filepath.Join(m.RequestsDir, collectionName)
-	entries, err := os.ReadDir(collectionPath)
-	if err != nil {
-		return nil, fmt.Errorf("GetRequestFiles -> failed to read directory: %v", err)
-	}
-
 	var files []string
-	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".json" {
-			files = append(files, entry.Name())
+	err := filepath.WalkDir(collectionPath, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != collectionPath && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if IsCollectionVariablesFile(entry.Name()) || strings.HasSuffix(entry.Name(), SnapshotFileSuffix) {
+			return nil
 		}
+		if filepath.Ext(entry.Name()) == ".json" || IsYAMLFile(entry.Name()) || IsHTTPFile(entry.Name()) {
+			relative, err := filepath.Rel(collectionPath, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(relative))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetRequestFiles -> failed to read directory: %v", err)
 	}
 
 	return files, nil
 }
 
+// IsCollectionVariablesFile reports whether a file of a collection holds its variables
+func IsCollectionVariablesFile(name string) bool {
+	base := strings.TrimSuffix(CollectionVariablesFileName, filepath.Ext(CollectionVariablesFileName))
+	return name == CollectionVariablesFileName || name == base+".yaml" || name == base+".yml"
+}
+
+// CreateCollectionDir makes sure a collection directory exists and returns its path
+func (m *FileManager) CreateCollectionDir(collectionName string) (string, error) {
+	collectionPath := filepath.Join(m.RequestsDir, collectionName)
+	if err := os.MkdirAll(collectionPath, 0755); err != nil {
+		return "", fmt.Errorf("CreateCollectionDir -> %v", err)
+	}
+	return collectionPath, nil
+}
+
 func (m *FileManager) CheckIfPathExists(path string) (bool, error) {
 	_, err := os.Stat(path)
 	if err == nil {
@@ -156,7 +210,7 @@ func (m *FileManager) CheckIfPathExists(path string) (bool, error) {
 func (m *FileManager) ReadFileContent(filePath string) (string, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
-		return "", fmt.Errorf("ReadFileContent -> %s %v", filePath, err)
+		return "", fmt.Errorf("ReadFileContent -> %s %w", filePath, err)
 	}
 	return string(data), nil
 }
@@ -169,6 +223,11 @@ func (m *FileManager) WriteFileContent(filePath, content string) error {
 	return nil
 }
 
+// GetConfigPath returns the path of the config file, config.json unless CheckConfigYML found a YAML one
+func (m *FileManager) GetConfigPath() string {
+	return m.ConfigPath
+}
+
 func (m *FileManager) GetConfigContent() (string, error) {
 	str, err := m.ReadFileContent(m.ConfigPath)
 	if err != nil {
@@ -201,6 +260,52 @@ func (m *FileManager) WriteSecretContent(content string) error {
 	return nil
 }
 
+func (m *FileManager) GetStateContent() (string, error) {
+	str, err := m.ReadFileContent(m.StatePath)
+	if err != nil {
+		return "", fmt.Errorf("GetStateContent -> %s %v", m.StatePath, err)
+	}
+	return str, nil
+}
+
+func (m *FileManager) WriteStateContent(content string) error {
+	err := m.WriteFileContent(m.StatePath, content)
+	if err != nil {
+		return fmt.Errorf("WriteStateContent -> %s: %v", m.StatePath, err)
+	}
+	return nil
+}
+
+func (m *FileManager) GetHistoryContent() (string, error) {
+	str, err := m.ReadFileContent(m.HistoryPath)
+	if err != nil {
+		return "", fmt.Errorf("GetHistoryContent -> %w", err)
+	}
+	return str, nil
+}
+
+// AppendHistoryContent adds one line to the history file, creating it when needed
+func (m *FileManager) AppendHistoryContent(line string) error {
+	file, err := os.OpenFile(m.HistoryPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("AppendHistoryContent -> %s: %v", m.HistoryPath, err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(line + "\n"); err != nil {
+		return fmt.Errorf("AppendHistoryContent -> %s: %v", m.HistoryPath, err)
+	}
+	return nil
+}
+
+func (m *FileManager) WriteHistoryContent(content string) error {
+	err := m.WriteFileContent(m.HistoryPath, content)
+	if err != nil {
+		return fmt.Errorf("WriteHistoryContent -> %s: %v", m.HistoryPath, err)
+	}
+	return nil
+}
+
 func (m *FileManager) GetCurrentDirectoryName() (string, error) {
 	dir, err := os.Getwd()
 	if err != nil {
@@ -210,12 +315,28 @@ func (m *FileManager) GetCurrentDirectoryName() (string, error) {
 	return filepath.Base(dir), nil
 }
 
+// SaveRequestJSON writes a request file, as YAML when its extension is .yaml or .yml.
+// Existing files are updated in place: only the values that changed are rewritten, so YAML
+// comments and the layout of both formats are kept.
 func (m *FileManager) SaveRequestJSON(filePath string, request *RequestJSON) error {
-	content, err := ToJSON(request)
+	isYAML := IsYAMLFile(filePath)
+	content, err := ToDocument(request, isYAML)
 	if err != nil {
 		return fmt.Errorf("SaveRequestJSON -> failed to marshal: %v", err)
 	}
 
+	if existing, err := os.ReadFile(filePath); err == nil {
+		updated, err := UpdateDocumentContent(string(existing), request, isYAML)
+		switch {
+		case err == nil:
+			content = updated
+		case isYAML:
+			// Rewriting would drop the comments of the file
+			return fmt.Errorf("SaveRequestJSON -> %s cannot be updated in place: %v", filepath.Base(filePath), err)
+		}
+		// JSON files that cannot be updated in place (invalid JSON) are rewritten entirely
+	}
+
 	err = m.WriteFileContent(filePath, content)
 	if err != nil {
 		return fmt.Errorf("SaveRequestJSON -> %v", err)
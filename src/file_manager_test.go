@@ -0,0 +1,71 @@
+package src
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGetRequestFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string // Created in the collection directory
+		want  []string
+	}{
+		{
+			name:  "requests of every format",
+			files: []string{"b.yaml", "a.json", "c.yml", "d.http", "notes.md"},
+			want:  []string{"a.json", "b.yaml", "c.yml", "d.http"},
+		},
+		{
+			name:  "folders at any depth",
+			files: []string{"list.json", "admin/users.json", "admin/roles/get.yaml", "admin/roles/all.http"},
+			want:  []string{"admin/roles/all.http", "admin/roles/get.yaml", "admin/users.json", "list.json"},
+		},
+		{
+			name:  "variables and snapshots are not requests",
+			files: []string{"_variables.json", "get.json", "get.snap.json", "admin/_variables.yml", "admin/list.json", "admin/list.snap.json"},
+			want:  []string{"admin/list.json", "get.json"},
+		},
+		{
+			name:  "hidden folders are skipped",
+			files: []string{".git/config.json", "admin/.drafts/new.json", "admin/get.json"},
+			want:  []string{"admin/get.json"},
+		},
+		{
+			name:  "empty folders",
+			files: []string{"empty/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, fm := newTestConfigLoader(t)
+			collectionPath, err := fm.CreateCollectionDir("users")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, file := range tt.files {
+				path := filepath.Join(collectionPath, filepath.FromSlash(file))
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if file[len(file)-1] == '/' {
+					continue
+				}
+				if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := fm.GetRequestFiles("users")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
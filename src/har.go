@@ -0,0 +1,468 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HAR 1.2 archive (http://www.softwareishard.com/blog/har-12-spec/), the fields postless reads and writes
+type harFileJSON struct {
+	Log harLogJSON `json:"log"`
+}
+
+type harLogJSON struct {
+	Version string         `json:"version"`
+	Creator harCreatorJSON `json:"creator"`
+	Entries []harEntryJSON `json:"entries"`
+}
+
+type harCreatorJSON struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntryJSON struct {
+	StartedDateTime string          `json:"startedDateTime"`
+	Time            float64         `json:"time"`
+	Request         harRequestJSON  `json:"request"`
+	Response        harResponseJSON `json:"response"`
+	Cache           struct{}        `json:"cache"`
+	Timings         harTimingsJSON  `json:"timings"`
+	ResourceType    string          `json:"_resourceType,omitempty"` // Set by Chromium devtools: xhr, fetch, script...
+	Error           string          `json:"_error,omitempty"`        // Transport error of a recorded execution
+	Comment         string          `json:"comment,omitempty"`
+}
+
+type harRequestJSON struct {
+	Method      string             `json:"method"`
+	URL         string             `json:"url"`
+	HTTPVersion string             `json:"httpVersion"`
+	Cookies     []harNameValueJSON `json:"cookies"`
+	Headers     []harNameValueJSON `json:"headers"`
+	QueryString []harNameValueJSON `json:"queryString"`
+	PostData    *harPostDataJSON   `json:"postData,omitempty"`
+	HeadersSize int                `json:"headersSize"`
+	BodySize    int                `json:"bodySize"`
+}
+
+type harResponseJSON struct {
+	Status      int                `json:"status"`
+	StatusText  string             `json:"statusText"`
+	HTTPVersion string             `json:"httpVersion"`
+	Cookies     []harNameValueJSON `json:"cookies"`
+	Headers     []harNameValueJSON `json:"headers"`
+	Content     harContentJSON     `json:"content"`
+	RedirectURL string             `json:"redirectURL"`
+	HeadersSize int                `json:"headersSize"`
+	BodySize    int                `json:"bodySize"`
+}
+
+type harNameValueJSON struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	FileName string `json:"fileName,omitempty"` // postData params only
+}
+
+type harPostDataJSON struct {
+	MimeType string             `json:"mimeType"`
+	Text     string             `json:"text,omitempty"`
+	Params   []harNameValueJSON `json:"params,omitempty"`
+}
+
+type harContentJSON struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimingsJSON struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// harIgnoredHeaders are set by browsers or the transport, not by the API caller
+var harIgnoredHeaders = map[string]bool{
+	"host": true, "content-length": true, "connection": true, "accept-encoding": true,
+	"accept-language": true, "user-agent": true, "referer": true, "origin": true,
+	"priority": true, "pragma": true, "cache-control": true, "dnt": true,
+	"upgrade-insecure-requests": true, "te": true,
+}
+
+// harStaticExtensions identify asset requests when the HAR has no resource type
+var harStaticExtensions = map[string]bool{
+	".js": true, ".mjs": true, ".css": true, ".map": true, ".png": true, ".jpg": true, ".jpeg": true,
+	".gif": true, ".svg": true, ".ico": true, ".webp": true, ".avif": true, ".woff": true, ".woff2": true,
+	".ttf": true, ".otf": true, ".mp4": true, ".webm": true, ".mp3": true, ".html": true,
+}
+
+// HAREntry summarizes an entry of an archive for listing and selection
+type HAREntry struct {
+	Index    int // 1-based, as shown by --list
+	Method   string
+	URL      string
+	Status   int
+	Static   bool // Page asset (script, stylesheet, image...) rather than an API call
+	harEntry harEntryJSON
+}
+
+// HARSelection chooses the entries of an archive to import
+type HARSelection struct {
+	Indexes       []int          // 1-based entry numbers, all entries when empty
+	Pattern       *regexp.Regexp // Only URLs matching it, when set
+	Methods       []string       // Only these methods, when set
+	IncludeStatic bool           // Also import page assets
+}
+
+// ParseHAR reads a HAR 1.2 archive and lists its entries
+func ParseHAR(content string) ([]HAREntry, error) {
+	var har harFileJSON
+	if err := json.Unmarshal([]byte(content), &har); err != nil {
+		return nil, fmt.Errorf("ParseHAR -> invalid HAR: %v", err)
+	}
+	if har.Log.Entries == nil {
+		return nil, fmt.Errorf("ParseHAR -> no log.entries, not a HAR file")
+	}
+
+	entries := make([]HAREntry, len(har.Log.Entries))
+	for i, entry := range har.Log.Entries {
+		entries[i] = HAREntry{
+			Index:    i + 1,
+			Method:   strings.ToUpper(entry.Request.Method),
+			URL:      entry.Request.URL,
+			Status:   entry.Response.Status,
+			Static:   harStaticEntry(entry),
+			harEntry: entry,
+		}
+	}
+	return entries, nil
+}
+
+func harStaticEntry(entry harEntryJSON) bool {
+	switch entry.ResourceType {
+	case "xhr", "fetch", "websocket", "eventsource":
+		return false
+	case "":
+		parsed, err := url.Parse(entry.Request.URL)
+		return err == nil && harStaticExtensions[strings.ToLower(path.Ext(parsed.Path))]
+	}
+	return true // document, script, stylesheet, image, font, media...
+}
+
+// SelectHAREntries applies a selection to the entries of an archive
+func SelectHAREntries(entries []HAREntry, selection HARSelection) ([]HAREntry, error) {
+	var selected []HAREntry
+	for _, index := range selection.Indexes {
+		if index < 1 || index > len(entries) {
+			return nil, fmt.Errorf("entry %d does not exist, the archive has %d entries", index, len(entries))
+		}
+	}
+
+	for _, entry := range entries {
+		if len(selection.Indexes) > 0 {
+			found := false
+			for _, index := range selection.Indexes {
+				found = found || index == entry.Index
+			}
+			if !found {
+				continue
+			}
+		} else if entry.Static && !selection.IncludeStatic {
+			continue // Explicitly numbered entries are imported even when they are assets
+		}
+		if selection.Pattern != nil && !selection.Pattern.MatchString(entry.URL) {
+			continue
+		}
+		if len(selection.Methods) > 0 {
+			found := false
+			for _, method := range selection.Methods {
+				found = found || strings.EqualFold(method, entry.Method)
+			}
+			if !found {
+				continue
+			}
+		}
+		selected = append(selected, entry)
+	}
+	return selected, nil
+}
+
+// harCredentialWarning explains a recorded credential header left out of a request file
+func harCredentialWarning(name, value string) string {
+	if strings.EqualFold(name, "Authorization") && strings.HasPrefix(value, "Bearer ") {
+		return "recorded bearer token left out, the postless JWT is sent instead"
+	}
+	if scheme, _, ok := strings.Cut(value, " "); ok && strings.HasSuffix(strings.ToLower(name), "authorization") {
+		return fmt.Sprintf("recorded %s %s credentials left out, add them with a {{variable}} if the API needs them", scheme, canonicalHARHeader(name))
+	}
+	return fmt.Sprintf("recorded %s header left out, add it with a {{variable}} if the API needs it", canonicalHARHeader(name))
+}
+
+// ParseEntryIndexes parses a list of entry numbers and ranges: "1,3,5-8"
+func ParseEntryIndexes(list string) ([]int, error) {
+	var indexes []int
+	for _, part := range strings.Split(list, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		from, to, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid entry number %q", part)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(to); err != nil || end < start {
+				return nil, fmt.Errorf("invalid entry range %q", part)
+			}
+		}
+		for index := start; index <= end; index++ {
+			indexes = append(indexes, index)
+		}
+	}
+	return indexes, nil
+}
+
+// RequestFromHAR converts a recorded browser request into a request file
+func RequestFromHAR(entry HAREntry) (*RequestJSON, []string) {
+	var warnings []string
+	source := entry.harEntry.Request
+	request := &RequestJSON{Method: entry.Method, URL: entry.URL}
+
+	headers := make(map[string]string)
+	for _, header := range source.Headers {
+		name := header.Name
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(name, ":") || harIgnoredHeaders[lower] || strings.HasPrefix(lower, "sec-") {
+			continue // HTTP/2 pseudo headers, browser and transport headers
+		}
+		if isCredentialHeader(name) {
+			// Captures hold live sessions, request files are usually committed
+			warnings = append(warnings, harCredentialWarning(name, header.Value))
+			continue
+		}
+		if _, ok := lookupHeader(headers, name); ok {
+			warnings = append(warnings, fmt.Sprintf("duplicate header %s, only the last value is kept", name))
+		}
+		headers[canonicalHARHeader(name)] = header.Value
+	}
+	if postData := source.PostData; postData != nil {
+		contentTypeName, hasContentType := lookupHeader(headers, "Content-Type")
+		mimeType := postData.MimeType
+		if mimeType == "" {
+			mimeType = headers[contentTypeName]
+		}
+		baseType, _, _ := strings.Cut(strings.ToLower(mimeType), ";")
+
+		var parsed interface{}
+		switch {
+		case strings.HasPrefix(baseType, "multipart/form-data"):
+			request.Form = make(map[string]string)
+			for _, param := range postData.Params {
+				if param.FileName != "" {
+					request.Form[param.Name] = "@" + param.FileName
+					warnings = append(warnings, fmt.Sprintf("set the path of the file uploaded as form field %s", param.Name))
+					continue
+				}
+				request.Form[param.Name] = param.Value
+			}
+			if len(postData.Params) == 0 && postData.Text != "" {
+				warnings = append(warnings, "multipart body has no params, set the form fields by hand")
+			}
+			if hasContentType {
+				delete(headers, contentTypeName) // The multipart boundary is generated when sending
+			}
+		case strings.Contains(baseType, "json") && json.Unmarshal([]byte(postData.Text), &parsed) == nil:
+			request.Body = parsed
+			// postless sends JSON bodies as application/json already
+			if hasContentType && strings.TrimSpace(baseType) == "application/json" {
+				delete(headers, contentTypeName)
+			}
+		case postData.Text != "":
+			request.RawBody = postData.Text
+			if !hasContentType && mimeType != "" {
+				headers["Content-Type"] = mimeType
+			}
+		case len(postData.Params) > 0:
+			values := url.Values{}
+			for _, param := range postData.Params {
+				values.Add(param.Name, param.Value)
+			}
+			request.RawBody = values.Encode()
+			if !hasContentType {
+				headers["Content-Type"] = "application/x-www-form-urlencoded"
+			}
+		}
+	}
+
+	if len(headers) > 0 {
+		request.Headers = headers
+	}
+	return request, warnings
+}
+
+// canonicalHARHeader restores the usual casing of headers recorded in lower case over HTTP/2
+func canonicalHARHeader(name string) string {
+	if name == strings.ToLower(name) {
+		return http.CanonicalHeaderKey(name)
+	}
+	return name
+}
+
+// ImportHAR saves the selected entries of an archive as requests of a collection
+func (cl *ConfigLoader) ImportHAR(config *ConfigJSON, collection string, entries []HAREntry) (*ImportResult, error) {
+	result := &ImportResult{}
+	for _, entry := range entries {
+		request, warnings := RequestFromHAR(entry)
+		request.URL = templateBaseURL(request.URL, config)
+		request.Name = defaultRequestName(request.Method, request.URL)
+
+		label := fmt.Sprintf("#%d %s", entry.Index, request.Name)
+		for _, warning := range warnings {
+			result.Warnings = append(result.Warnings, label+": "+warning)
+		}
+
+		filePath, err := cl.SaveImportedRequest(collection, request)
+		if err != nil {
+			return result, fmt.Errorf("ImportHAR -> %v", err)
+		}
+		result.Requests = append(result.Requests, ImportedRequest{Name: request.Name, Collection: collection, FilePath: filePath})
+	}
+	return result, nil
+}
+
+// ExportHAR writes recorded executions as a HAR 1.2 archive. Authorization, Cookie and
+// Set-Cookie values are redacted unless includeSecrets is set.
+func ExportHAR(entries []HistoryEntryJSON, includeSecrets bool) (string, error) {
+	version := "devel"
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		version = info.Main.Version
+	}
+
+	har := harFileJSON{Log: harLogJSON{
+		Version: "1.2",
+		Creator: harCreatorJSON{Name: "postless", Version: version},
+		Entries: make([]harEntryJSON, 0, len(entries)),
+	}}
+
+	for _, entry := range entries {
+		if entry.Request == nil {
+			continue // Variables could not be resolved, nothing was sent
+		}
+		harEntry, err := historyHAREntry(entry, includeSecrets)
+		if err != nil {
+			return "", fmt.Errorf("ExportHAR -> %v", err)
+		}
+		har.Log.Entries = append(har.Log.Entries, harEntry)
+	}
+
+	output, err := ToJSON(har)
+	if err != nil {
+		return "", fmt.Errorf("ExportHAR -> %v", err)
+	}
+	return output + "\n", nil
+}
+
+func historyHAREntry(entry HistoryEntryJSON, includeSecrets bool) (harEntryJSON, error) {
+	resolved := entry.Request
+	responseHeaders := http.Header(entry.Headers)
+	if !includeSecrets {
+		resolved = resolved.Redacted()
+		responseHeaders = redactHeaders(responseHeaders, []string{"Set-Cookie"})
+	}
+	harEntry := harEntryJSON{
+		StartedDateTime: entry.Timestamp.Format(time.RFC3339Nano),
+		Time:            entry.DurationMs,
+		Timings:         harTimingsJSON{Wait: entry.DurationMs},
+		Error:           entry.Error,
+		Comment:         entry.RequestFile,
+		Request: harRequestJSON{
+			Method:      resolved.Method,
+			URL:         resolved.URL,
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []harNameValueJSON{},
+			Headers:     harHeaders(resolved.Headers),
+			QueryString: []harNameValueJSON{},
+			HeadersSize: -1,
+		},
+	}
+
+	if parsed, err := url.Parse(resolved.URL); err == nil {
+		for _, name := range sortedQueryKeys(parsed.Query()) {
+			for _, value := range parsed.Query()[name] {
+				harEntry.Request.QueryString = append(harEntry.Request.QueryString, harNameValueJSON{Name: name, Value: value})
+			}
+		}
+	}
+
+	switch {
+	case len(resolved.Form) > 0:
+		postData := &harPostDataJSON{MimeType: "multipart/form-data"}
+		for _, name := range sortedStringKeys(resolved.Form) {
+			value := resolved.Form[name]
+			if filePath, ok := strings.CutPrefix(value, "@"); ok {
+				postData.Params = append(postData.Params, harNameValueJSON{Name: name, FileName: filePath})
+				continue
+			}
+			postData.Params = append(postData.Params, harNameValueJSON{Name: name, Value: value})
+		}
+		harEntry.Request.PostData = postData
+		harEntry.Request.BodySize = -1
+	default:
+		body, err := requestBodyText(resolved)
+		if err != nil {
+			return harEntry, err
+		}
+		if body != "" {
+			mimeType := resolved.Headers.Get("Content-Type")
+			if mimeType == "" {
+				mimeType = "application/json"
+			}
+			harEntry.Request.PostData = &harPostDataJSON{MimeType: mimeType, Text: body}
+		}
+		harEntry.Request.BodySize = len(body)
+	}
+
+	response := entry.Response()
+	mimeType := http.Header(entry.Headers).Get("Content-Type")
+	harEntry.Response = harResponseJSON{
+		Status:      entry.StatusCode,
+		StatusText:  strings.TrimSpace(strings.TrimPrefix(entry.Status, strconv.Itoa(entry.StatusCode))),
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []harNameValueJSON{},
+		Headers:     harHeaders(responseHeaders),
+		Content: harContentJSON{
+			Size:     len(response.Body),
+			MimeType: mimeType,
+			Text:     entry.Body,
+			Encoding: entry.BodyEncoding,
+		},
+		RedirectURL: http.Header(entry.Headers).Get("Location"),
+		HeadersSize: -1,
+		BodySize:    len(response.Body),
+	}
+	if entry.Error != "" {
+		harEntry.Response.BodySize = -1
+	}
+	return harEntry, nil
+}
+
+func harHeaders(headers map[string][]string) []harNameValueJSON {
+	list := []harNameValueJSON{}
+	for _, name := range sortedHeaderNames(headers) {
+		for _, value := range headers[name] {
+			list = append(list, harNameValueJSON{Name: name, Value: value})
+		}
+	}
+	return list
+}
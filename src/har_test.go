@@ -0,0 +1,312 @@
+package src
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// harTestArchive wraps entries (JSON objects) into a HAR archive
+func harTestArchive(entries ...string) string {
+	return `{"log": {"version": "1.2", "entries": [` + strings.Join(entries, ",") + `]}}`
+}
+
+func TestParseHAR(t *testing.T) {
+	content := harTestArchive(
+		`{"request": {"method": "get", "url": "https://app.test/api/users"}, "response": {"status": 200}, "_resourceType": "fetch"}`,
+		`{"request": {"method": "GET", "url": "https://app.test/app.js?v=2"}, "response": {"status": 200}}`,
+		`{"request": {"method": "GET", "url": "https://app.test/"}, "response": {"status": 200}, "_resourceType": "document"}`,
+		`{"request": {"method": "POST", "url": "https://app.test/api/login"}, "response": {"status": 0}}`,
+	)
+
+	entries, err := ParseHAR(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	type summary struct {
+		Index  int
+		Method string
+		Status int
+		Static bool
+	}
+	var got []summary
+	for _, entry := range entries {
+		got = append(got, summary{entry.Index, entry.Method, entry.Status, entry.Static})
+	}
+	want := []summary{{1, "GET", 200, false}, {2, "GET", 200, true}, {3, "GET", 200, true}, {4, "POST", 0, false}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	for _, invalid := range []string{`{`, `{"log": {}}`} {
+		if _, err := ParseHAR(invalid); err == nil {
+			t.Errorf("expected an error for %s", invalid)
+		}
+	}
+}
+
+func TestSelectHAREntries(t *testing.T) {
+	entries := []HAREntry{
+		{Index: 1, Method: "GET", URL: "https://app.test/api/users"},
+		{Index: 2, Method: "GET", URL: "https://app.test/app.js", Static: true},
+		{Index: 3, Method: "POST", URL: "https://app.test/api/users"},
+		{Index: 4, Method: "DELETE", URL: "https://app.test/other"},
+	}
+
+	tests := []struct {
+		name      string
+		selection HARSelection
+		want      []int
+		wantErr   string
+	}{
+		{name: "API calls by default", want: []int{1, 3, 4}},
+		{name: "page assets on request", selection: HARSelection{IncludeStatic: true}, want: []int{1, 2, 3, 4}},
+		{name: "numbered entries even when assets", selection: HARSelection{Indexes: []int{2, 3}}, want: []int{2, 3}},
+		{name: "URL pattern", selection: HARSelection{Pattern: regexp.MustCompile(`/api/`)}, want: []int{1, 3}},
+		{name: "methods ignore case", selection: HARSelection{Methods: []string{"post", "delete"}}, want: []int{3, 4}},
+		{name: "every filter applies", selection: HARSelection{Indexes: []int{1, 3, 4}, Pattern: regexp.MustCompile(`/api/`), Methods: []string{"POST"}}, want: []int{3}},
+		{name: "missing entry", selection: HARSelection{Indexes: []int{5}}, wantErr: "entry 5 does not exist, the archive has 4 entries"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := SelectHAREntries(entries, tt.selection)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []int
+			for _, entry := range selected {
+				got = append(got, entry.Index)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got entries %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEntryIndexes(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    []int
+		wantErr string
+	}{
+		{list: "3", want: []int{3}},
+		{list: "1, 3,5-7,", want: []int{1, 3, 5, 6, 7}},
+		{list: "2-2", want: []int{2}},
+		{list: "x", wantErr: `invalid entry number "x"`},
+		{list: "4-2", wantErr: `invalid entry range "4-2"`},
+		{list: "1-y", wantErr: `invalid entry range "1-y"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.list, func(t *testing.T) {
+			got, err := ParseEntryIndexes(tt.list)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestFromHAR(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  string // HAR request object
+		want     string // The request as JSON
+		warnings []string
+	}{
+		{
+			name: "browser headers are left out",
+			request: `{"method": "GET", "url": "https://app.test/api/users", "headers": [
+				{"name": ":authority", "value": "app.test"}, {"name": "user-agent", "value": "Firefox"},
+				{"name": "sec-fetch-mode", "value": "cors"}, {"name": "accept", "value": "application/json"},
+				{"name": "X-Trace", "value": "1"}, {"name": "x-trace", "value": "2"}]}`,
+			want:     `{"name": "", "method": "GET", "url": "https://app.test/api/users", "skipAuth": false, "headers": {"Accept": "application/json", "X-Trace": "2"}}`,
+			warnings: []string{"duplicate header x-trace, only the last value is kept"},
+		},
+		{
+			name: "every credential is dropped",
+			request: `{"method": "GET", "url": "https://app.test/api/me", "headers": [
+				{"name": "authorization", "value": "Bearer eyJhbGc"}, {"name": "Cookie", "value": "session=abc"},
+				{"name": "Proxy-Authorization", "value": "Basic cHJveHk6cHc="}]}`,
+			want: `{"name": "", "method": "GET", "url": "https://app.test/api/me", "skipAuth": false}`,
+			warnings: []string{
+				"recorded bearer token left out, the postless JWT is sent instead",
+				"recorded Cookie header left out, add it with a {{variable}} if the API needs it",
+				"recorded Basic Proxy-Authorization credentials left out, add them with a {{variable}} if the API needs them",
+			},
+		},
+		{
+			name: "basic credentials are dropped too",
+			request: `{"method": "GET", "url": "https://app.test/api/me", "headers": [
+				{"name": "Authorization", "value": "Basic YWRhOnB3"}]}`,
+			want:     `{"name": "", "method": "GET", "url": "https://app.test/api/me", "skipAuth": false}`,
+			warnings: []string{"recorded Basic Authorization credentials left out, add them with a {{variable}} if the API needs them"},
+		},
+		{
+			name: "JSON body",
+			request: `{"method": "POST", "url": "https://app.test/api/users", "headers": [{"name": "content-type", "value": "application/json"}],
+				"postData": {"mimeType": "application/json", "text": "{\"name\": \"Ada\"}"}}`,
+			want: `{"name": "", "method": "POST", "url": "https://app.test/api/users", "skipAuth": false, "body": {"name": "Ada"}}`,
+		},
+		{
+			name: "vendor JSON keeps its content type",
+			request: `{"method": "POST", "url": "https://app.test/api/users", "headers": [{"name": "Content-Type", "value": "application/vnd.api+json"}],
+				"postData": {"mimeType": "application/vnd.api+json", "text": "[1]"}}`,
+			want: `{"name": "", "method": "POST", "url": "https://app.test/api/users", "skipAuth": false, "headers": {"Content-Type": "application/vnd.api+json"}, "body": [1]}`,
+		},
+		{
+			name: "multipart params",
+			request: `{"method": "POST", "url": "https://app.test/api/upload", "headers": [{"name": "Content-Type", "value": "multipart/form-data; boundary=x"}],
+				"postData": {"mimeType": "multipart/form-data; boundary=x", "params": [{"name": "title", "value": "A"}, {"name": "file", "fileName": "a.png"}]}}`,
+			want:     `{"name": "", "method": "POST", "url": "https://app.test/api/upload", "skipAuth": false, "form": {"file": "@a.png", "title": "A"}}`,
+			warnings: []string{"set the path of the file uploaded as form field file"},
+		},
+		{
+			name: "form params without text",
+			request: `{"method": "POST", "url": "https://app.test/api/login",
+				"postData": {"mimeType": "application/x-www-form-urlencoded", "params": [{"name": "user", "value": "ada"}, {"name": "q", "value": "a b"}]}}`,
+			want: `{"name": "", "method": "POST", "url": "https://app.test/api/login", "skipAuth": false, "headers": {"Content-Type": "application/x-www-form-urlencoded"}, "rawBody": "q=a+b&user=ada"}`,
+		},
+		{
+			name: "other bodies are raw",
+			request: `{"method": "PUT", "url": "https://app.test/api/notes/1",
+				"postData": {"mimeType": "text/plain", "text": "hello"}}`,
+			want: `{"name": "", "method": "PUT", "url": "https://app.test/api/notes/1", "skipAuth": false, "headers": {"Content-Type": "text/plain"}, "rawBody": "hello"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := ParseHAR(harTestArchive(`{"request": ` + tt.request + `, "response": {"status": 200}}`))
+			if err != nil {
+				t.Fatalf("ParseHAR: %v", err)
+			}
+			request, warnings := RequestFromHAR(entries[0])
+			got, err := ToCompactJSON(request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := compactTestJSON(t, tt.want); got != want {
+				t.Errorf("got  %s\nwant %s", got, want)
+			}
+			if !reflect.DeepEqual(warnings, tt.warnings) {
+				t.Errorf("got warnings %q, want %q", warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestExportHAR(t *testing.T) {
+	entries := []HistoryEntryJSON{
+		{
+			Timestamp:   time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+			RequestFile: "auth/login.json",
+			Request: &ResolvedRequest{
+				Method: "POST",
+				URL:    "https://api.test/login?next=%2Fhome",
+				Headers: http.Header{
+					"Authorization": {"Bearer tok"},
+					"Cookie":        {"session=abc"},
+					"Content-Type":  {"application/json"},
+				},
+				Body: map[string]interface{}{"user": "ada"},
+			},
+			StatusCode: 200,
+			Status:     "200 OK",
+			Headers:    map[string][]string{"Set-Cookie": {"session=def"}, "Content-Type": {"application/json"}},
+			Body:       `{"ok":true}`,
+			DurationMs: 12.5,
+		},
+		{RequestFile: "users/broken.json", Error: "undefined variable {{id}}"}, // Never sent
+	}
+
+	tests := []struct {
+		name           string
+		includeSecrets bool
+		requestHeaders []harNameValueJSON
+		setCookie      string
+	}{
+		{
+			name: "credentials are redacted",
+			requestHeaders: []harNameValueJSON{
+				{Name: "Authorization", Value: "Bearer " + RedactedValue},
+				{Name: "Content-Type", Value: "application/json"},
+				{Name: "Cookie", Value: RedactedValue},
+			},
+			setCookie: RedactedValue,
+		},
+		{
+			name:           "credentials are kept on request",
+			includeSecrets: true,
+			requestHeaders: []harNameValueJSON{
+				{Name: "Authorization", Value: "Bearer tok"},
+				{Name: "Content-Type", Value: "application/json"},
+				{Name: "Cookie", Value: "session=abc"},
+			},
+			setCookie: "session=def",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := ExportHAR(entries, tt.includeSecrets)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var har harFileJSON
+			if err := json.Unmarshal([]byte(output), &har); err != nil {
+				t.Fatalf("invalid HAR: %v", err)
+			}
+			if har.Log.Version != "1.2" || len(har.Log.Entries) != 1 {
+				t.Fatalf("got version %q with %d entries", har.Log.Version, len(har.Log.Entries))
+			}
+
+			entry := har.Log.Entries[0]
+			if entry.StartedDateTime != "2026-03-01T12:00:00Z" || entry.Time != 12.5 || entry.Comment != "auth/login.json" {
+				t.Errorf("got entry %s %v %q", entry.StartedDateTime, entry.Time, entry.Comment)
+			}
+			if !reflect.DeepEqual(entry.Request.Headers, tt.requestHeaders) {
+				t.Errorf("got request headers %+v, want %+v", entry.Request.Headers, tt.requestHeaders)
+			}
+			if want := []harNameValueJSON{{Name: "next", Value: "/home"}}; !reflect.DeepEqual(entry.Request.QueryString, want) {
+				t.Errorf("got query string %+v", entry.Request.QueryString)
+			}
+			if entry.Request.PostData == nil || entry.Request.PostData.Text != `{"user":"ada"}` {
+				t.Errorf("got post data %+v", entry.Request.PostData)
+			}
+			if entry.Response.Status != 200 || entry.Response.StatusText != "OK" || entry.Response.Content.Text != `{"ok":true}` {
+				t.Errorf("got response %d %q %q", entry.Response.Status, entry.Response.StatusText, entry.Response.Content.Text)
+			}
+			var setCookie string
+			for _, header := range entry.Response.Headers {
+				if header.Name == "Set-Cookie" {
+					setCookie = header.Value
+				}
+			}
+			if setCookie != tt.setCookie {
+				t.Errorf("got Set-Cookie %q, want %q", setCookie, tt.setCookie)
+			}
+		})
+	}
+}
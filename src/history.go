@@ -0,0 +1,179 @@
+package src
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// NewHistoryEntry captures an execution: the request exactly as sent and the full response
+func NewHistoryEntry(requestFile, name, environment string, response *HTTPResponse) HistoryEntryJSON {
+	entry := HistoryEntryJSON{
+		Timestamp:   response.StartedAt,
+		Environment: environment,
+		RequestFile: requestFile,
+		Name:        name,
+		Request:     response.Request,
+		StatusCode:  response.StatusCode,
+		Status:      response.Status,
+		Headers:     response.Headers,
+		DurationMs:  float64(response.Duration.Microseconds()) / 1000,
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	if utf8.Valid(response.Body) {
+		entry.Body = string(response.Body)
+	} else {
+		entry.Body = base64.StdEncoding.EncodeToString(response.Body)
+		entry.BodyEncoding = "base64"
+	}
+
+	if response.Error != nil {
+		entry.Error = response.Error.Error()
+	}
+	return entry
+}
+
+// Response rebuilds the recorded response so it can be displayed again
+func (e HistoryEntryJSON) Response() *HTTPResponse {
+	body := []byte(e.Body)
+	if e.BodyEncoding == "base64" {
+		if decoded, err := base64.StdEncoding.DecodeString(e.Body); err == nil {
+			body = decoded
+		}
+	}
+
+	response := &HTTPResponse{
+		StatusCode: e.StatusCode,
+		Status:     e.Status,
+		Headers:    e.Headers,
+		Body:       body,
+		BodyString: string(body),
+		Size:       int64(len(body)),
+		Duration:   time.Duration(e.DurationMs * float64(time.Millisecond)),
+		Request:    e.Request,
+		StartedAt:  e.Timestamp,
+	}
+	if e.Error != "" {
+		response.Error = errors.New(e.Error)
+	}
+
+	var jsonData interface{}
+	if len(body) > 0 && json.Unmarshal(body, &jsonData) == nil {
+		response.IsJSON = true
+		response.BodyJSON = jsonData
+	}
+	return response
+}
+
+// RecordHistory appends an execution to history.jsonl unless history is disabled. The oldest
+// entries beyond the configured limit are dropped, so repeated headless runs do not grow the file.
+// Credentials are redacted unless historyIncludeSecrets is set.
+func (cl *ConfigLoader) RecordHistory(config *ConfigJSON, item *RequestItem, response *HTTPResponse) (HistoryEntryJSON, error) {
+	entry := NewHistoryEntry(cl.RequestKey(item.RequestPath()), item.Name, cl.GetEnvironmentLabel(config), response)
+	if !config.HistoryIncludeSecrets {
+		entry.Request = entry.Request.Redacted()
+	}
+	limit := config.GetHistoryLimit()
+	if limit == 0 {
+		return entry, nil
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return entry, fmt.Errorf("RecordHistory -> %v", err)
+	}
+
+	content, err := cl.readHistory()
+	if err != nil {
+		return entry, fmt.Errorf("RecordHistory -> %v", err)
+	}
+	var lines []string
+	for _, existing := range strings.Split(content, "\n") {
+		if strings.TrimSpace(existing) != "" {
+			lines = append(lines, existing)
+		}
+	}
+	if len(lines) < limit {
+		if err := cl.fileManager.AppendHistoryContent(string(line)); err != nil {
+			return entry, fmt.Errorf("RecordHistory -> %v", err)
+		}
+		return entry, nil
+	}
+
+	lines = append(lines[len(lines)-(limit-1):], string(line))
+	if err := cl.fileManager.WriteHistoryContent(strings.Join(lines, "\n") + "\n"); err != nil {
+		return entry, fmt.Errorf("RecordHistory -> %v", err)
+	}
+	return entry, nil
+}
+
+// LoadHistory returns recorded executions, oldest first, at most the configured limit.
+// Unreadable lines are skipped; the file itself is only changed by RecordHistory.
+func (cl *ConfigLoader) LoadHistory(config *ConfigJSON) ([]HistoryEntryJSON, error) {
+	content, err := cl.readHistory()
+	if err != nil {
+		return nil, fmt.Errorf("LoadHistory -> %v", err)
+	}
+
+	var entries []HistoryEntryJSON
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var entry HistoryEntryJSON
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if limit := config.GetHistoryLimit(); limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	return entries, nil
+}
+
+// readHistory reads history.jsonl, which is empty until the first execution is recorded
+func (cl *ConfigLoader) readHistory() (string, error) {
+	content, err := cl.fileManager.GetHistoryContent()
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	return content, err
+}
+
+// RequestFilePath turns a request key back into a path inside the requests directory
+func (cl *ConfigLoader) RequestFilePath(requestKey string) string {
+	if fm, ok := cl.fileManager.(*FileManager); ok {
+		return filepath.Join(fm.RequestsDir, filepath.FromSlash(requestKey))
+	}
+	return filepath.FromSlash(requestKey)
+}
+
+// recordHistory records a headless execution, warning on stderr when it cannot be saved
+func (r *Runner) recordHistory(item *RequestItem, response *HTTPResponse) {
+	if _, err := r.configLoader.RecordHistory(r.config, item, response); err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to record history:", err)
+	}
+}
+
+// PreviousHistoryEntry finds the latest execution of a request recorded strictly before a time.
+// Entries are expected oldest first, as returned by LoadHistory.
+func PreviousHistoryEntry(entries []HistoryEntryJSON, requestFile string, before time.Time) (HistoryEntryJSON, bool) {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].RequestFile == requestFile && entries[i].Timestamp.Before(before) {
+			return entries[i], true
+		}
+	}
+	return HistoryEntryJSON{}, false
+}
@@ -0,0 +1,262 @@
+package src
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// historyTestLines writes n history entries named entry-1 to entry-n
+func historyTestLines(t *testing.T, fm *FileManager, n int) {
+	t.Helper()
+	var lines []string
+	for i := 1; i <= n; i++ {
+		lines = append(lines, fmt.Sprintf(`{"timestamp": "2026-01-01T00:00:00Z", "requestFile": "a.json", "name": "entry-%d", "durationMs": 1}`, i))
+	}
+	if err := fm.WriteHistoryContent(strings.Join(lines, "\n") + "\n"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecordHistory(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		existing int      // Entries already recorded
+		want     []string // Names of the entries in the file afterwards
+		noFile   bool
+	}{
+		{name: "first entry", limit: 3, want: []string{"new"}},
+		{name: "below the limit", limit: 3, existing: 1, want: []string{"entry-1", "new"}},
+		{name: "at the limit the oldest is dropped", limit: 3, existing: 3, want: []string{"entry-2", "entry-3", "new"}},
+		{name: "a lowered limit trims the file", limit: 2, existing: 4, want: []string{"entry-4", "new"}},
+		{name: "disabled history writes nothing", limit: -1, noFile: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl, fm := newTestConfigLoader(t)
+			if tt.existing > 0 {
+				historyTestLines(t, fm, tt.existing)
+			}
+			config := &ConfigJSON{HistoryLimit: tt.limit}
+			item := &RequestItem{Name: "new", FilePath: filepath.Join(fm.RequestsDir, "users", "get.json"), Request: &RequestJSON{}}
+			response := &HTTPResponse{StatusCode: 200, Status: "200 OK", Body: []byte("ok"), Request: &ResolvedRequest{Method: "GET", URL: "/u"}}
+
+			entry, err := cl.RecordHistory(config, item, response)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if entry.RequestFile != "users/get.json" || entry.Environment != DefaultEnvironment || entry.Body != "ok" {
+				t.Errorf("got entry %q %q %q", entry.RequestFile, entry.Environment, entry.Body)
+			}
+
+			if _, err := os.Stat(fm.HistoryPath); tt.noFile {
+				if !os.IsNotExist(err) {
+					t.Errorf("history file written while disabled: %v", err)
+				}
+				return
+			}
+			entries, err := cl.LoadHistory(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, entry := range entries {
+				names = append(names, entry.Name)
+			}
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("got entries %q, want %q", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordHistoryRedaction(t *testing.T) {
+	tests := []struct {
+		name           string
+		includeSecrets bool
+		want           http.Header
+	}{
+		{
+			name: "credentials are redacted",
+			want: http.Header{
+				"Authorization":       {"Bearer " + RedactedValue},
+				"Proxy-Authorization": {RedactedValue},
+				"Cookie":              {RedactedValue, RedactedValue},
+				"X-Trace":             {"1"},
+			},
+		},
+		{
+			name:           "historyIncludeSecrets keeps them",
+			includeSecrets: true,
+			want: http.Header{
+				"Authorization":       {"Bearer tok"},
+				"Proxy-Authorization": {"secret"},
+				"Cookie":              {"a=1", "b=2"},
+				"X-Trace":             {"1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl, fm := newTestConfigLoader(t)
+			sent := &ResolvedRequest{Method: "GET", URL: "/u", Headers: http.Header{
+				"Authorization":       {"Bearer tok"},
+				"Proxy-Authorization": {"secret"},
+				"Cookie":              {"a=1", "b=2"},
+				"X-Trace":             {"1"},
+			}}
+			item := &RequestItem{Name: "get", FilePath: filepath.Join(fm.RequestsDir, "get.json"), Request: &RequestJSON{}}
+			config := &ConfigJSON{HistoryIncludeSecrets: tt.includeSecrets}
+
+			if _, err := cl.RecordHistory(config, item, &HTTPResponse{Request: sent}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := sent.Headers.Get("Authorization"); got != "Bearer tok" {
+				t.Errorf("the sent request was modified: %q", got)
+			}
+
+			entries, err := cl.LoadHistory(config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 {
+				t.Fatalf("got %d entries", len(entries))
+			}
+			if got := entries[0].Request.Headers; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got headers %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadHistory(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{name: "unreadable lines are skipped", limit: 10, want: 5},
+		{name: "the newest entries within the limit", limit: 2, want: 2},
+		{name: "disabled history still reads the file", limit: -1, want: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl, fm := newTestConfigLoader(t)
+			historyTestLines(t, fm, 4)
+			if err := fm.AppendHistoryContent("{not json"); err != nil {
+				t.Fatal(err)
+			}
+			if err := fm.AppendHistoryContent(`{"name": "entry-5"}`); err != nil {
+				t.Fatal(err)
+			}
+			entries, err := cl.LoadHistory(&ConfigJSON{HistoryLimit: tt.limit})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(entries) != tt.want {
+				t.Fatalf("got %d entries, want %d", len(entries), tt.want)
+			}
+			if last := entries[len(entries)-1].Name; last != "entry-5" {
+				t.Errorf("got %q last, want entry-5", last)
+			}
+		})
+	}
+
+	cl, _ := newTestConfigLoader(t)
+	if entries, err := cl.LoadHistory(&ConfigJSON{}); err != nil || len(entries) != 0 {
+		t.Errorf("got %v, %v without a history file", entries, err)
+	}
+}
+
+func TestPreviousHistoryEntry(t *testing.T) {
+	at := func(second int) time.Time { return time.Date(2026, 1, 1, 0, 0, second, 0, time.UTC) }
+	entries := []HistoryEntryJSON{
+		{Name: "a1", RequestFile: "a.json", Timestamp: at(1)},
+		{Name: "b1", RequestFile: "b.json", Timestamp: at(2)},
+		{Name: "a2", RequestFile: "a.json", Timestamp: at(3)},
+	}
+
+	tests := []struct {
+		name        string
+		requestFile string
+		before      time.Time
+		want        string // "" when none is found
+	}{
+		{name: "latest of the request", requestFile: "a.json", before: at(9), want: "a2"},
+		{name: "strictly before", requestFile: "a.json", before: at(3), want: "a1"},
+		{name: "nothing earlier", requestFile: "a.json", before: at(1)},
+		{name: "other request", requestFile: "c.json", before: at(9)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, found := PreviousHistoryEntry(entries, tt.requestFile, tt.before)
+			if found != (tt.want != "") || entry.Name != tt.want {
+				t.Errorf("got %q (found %v), want %q", entry.Name, found, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestoreCredentials(t *testing.T) {
+	recorded := &ResolvedRequest{Method: "GET", URL: "/u", Headers: http.Header{
+		"Authorization": {"Bearer " + RedactedValue},
+		"Cookie":        {RedactedValue},
+		"X-Trace":       {"1"},
+	}}
+
+	tests := []struct {
+		name    string
+		jwt     string
+		request *RequestJSON
+		want    http.Header
+	}{
+		{
+			name:    "current JWT and request file headers",
+			jwt:     "new-token",
+			request: &RequestJSON{Headers: map[string]string{"Cookie": "session={{session}}"}},
+			want:    http.Header{"Authorization": {"Bearer new-token"}, "Cookie": {"session=abc"}, "X-Trace": {"1"}},
+		},
+		{
+			name:    "credentials without a current value are left out",
+			request: &RequestJSON{},
+			want:    http.Header{"X-Trace": {"1"}},
+		},
+		{
+			name:    "unresolvable requests lose their credentials",
+			jwt:     "new-token",
+			request: &RequestJSON{URL: "{{missing}}"},
+			want:    http.Header{"X-Trace": {"1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl, _ := newTestConfigLoader(t)
+			client := NewHTTPClient(&ConfigJSON{BaseUrl: "http://localhost"}, &SecretJSON{JWT: tt.jwt}, cl)
+			item := &RequestItem{Request: tt.request, Variables: map[string]string{"session": "abc"}}
+
+			restored := client.RestoreCredentials(recorded, item)
+			if !reflect.DeepEqual(restored.Headers, tt.want) {
+				t.Errorf("got headers %v, want %v", restored.Headers, tt.want)
+			}
+			if recorded.Headers.Get("Authorization") != "Bearer "+RedactedValue {
+				t.Error("the recorded request was modified")
+			}
+		})
+	}
+
+	plain := &ResolvedRequest{Headers: http.Header{"X-Trace": {"1"}}}
+	if got := NewHTTPClient(&ConfigJSON{}, &SecretJSON{}, NewConfigLoader(nil)).RestoreCredentials(plain, nil); got != plain {
+		t.Error("a request without redacted headers should be returned as is")
+	}
+}
@@ -0,0 +1,349 @@
+package src
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type historySelectedMsg struct {
+	entry HistoryEntryJSON
+}
+
+type historyResendMsg struct {
+	entry HistoryEntryJSON
+}
+
+// historyRecordedMsg is broadcast to every screen when an execution is added to history
+type historyRecordedMsg struct {
+	entry HistoryEntryJSON
+}
+
+// Status filters cycled with "s" in the history list
+var historyStatusFilters = []string{"all", "2xx", "3xx", "4xx", "5xx", "errors"}
+
+// HistoryViewModel lists recorded executions, newest first, with request and status filters.
+// It is shown as the history tab of the collections view.
+type HistoryViewModel struct {
+	entries      []HistoryEntryJSON // Newest first
+	filtered     []HistoryEntryJSON
+	loadErr      error
+	searchMode   bool
+	searchQuery  string
+	statusFilter int
+	marked       *HistoryEntryJSON // Compared with the selected entry by "d"
+	cursor       int
+	offset       int
+	width        int
+	height       int
+	styles       *Styles
+}
+
+// NewHistoryViewModel takes entries oldest first, as stored in history.jsonl
+func NewHistoryViewModel(entries []HistoryEntryJSON, loadErr error) HistoryViewModel {
+	m := HistoryViewModel{
+		loadErr: loadErr,
+		width:   80,
+		height:  24,
+		styles:  DefaultStyles(),
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		m.entries = append(m.entries, entries[i])
+	}
+	m.applyFilters()
+	return m
+}
+
+func (m HistoryViewModel) Init() tea.Cmd {
+	return nil
+}
+
+// capturingInput reports whether keys are being typed into the search box
+func (m HistoryViewModel) capturingInput() bool {
+	return m.searchMode
+}
+
+func (m HistoryViewModel) Update(msg tea.Msg) (HistoryViewModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.moveTo(m.cursor)
+		return m, nil
+
+	case historyRecordedMsg:
+		count := len(m.filtered)
+		m.entries = append([]HistoryEntryJSON{msg.entry}, m.entries...)
+		m.applyFilters()
+		// Keep the cursor on the same entry when the new one is listed above it
+		if count > 0 && len(m.filtered) > count {
+			m.moveTo(m.cursor + 1)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.searchMode {
+			switch msg.String() {
+			case "esc":
+				m.searchMode = false
+				m.searchQuery = ""
+				m.applyFilters()
+			case "enter":
+				m.searchMode = false
+			case "backspace":
+				if len(m.searchQuery) > 0 {
+					runes := []rune(m.searchQuery)
+					m.searchQuery = string(runes[:len(runes)-1])
+					m.applyFilters()
+				}
+			case "up", "down":
+				m.searchMode = false
+				return m.Update(msg)
+			default:
+				if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+					m.searchQuery += string(msg.Runes)
+					m.applyFilters()
+				}
+			}
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "/":
+			m.searchMode = true
+		case "s":
+			m.statusFilter = (m.statusFilter + 1) % len(historyStatusFilters)
+			m.applyFilters()
+		case "up", "k":
+			m.moveTo(m.cursor - 1)
+		case "down", "j":
+			m.moveTo(m.cursor + 1)
+		case "pgup":
+			m.moveTo(m.cursor - m.pageHeight())
+		case "pgdown":
+			m.moveTo(m.cursor + m.pageHeight())
+		case "g", "home":
+			m.moveTo(0)
+		case "G", "end":
+			m.moveTo(len(m.filtered) - 1)
+		case "enter":
+			if entry, ok := m.selected(); ok {
+				return m, emit(historySelectedMsg{entry: entry})
+			}
+		case "r":
+			if entry, ok := m.selected(); ok {
+				return m, emit(historyResendMsg{entry: entry})
+			}
+		case "m":
+			if entry, ok := m.selected(); ok {
+				if m.marked != nil && sameHistoryEntry(*m.marked, entry) {
+					m.marked = nil
+				} else {
+					m.marked = &entry
+				}
+			}
+		case "d":
+			if entry, ok := m.selected(); ok {
+				return m, m.diff(entry)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func (m HistoryViewModel) View() string {
+	var b strings.Builder
+
+	// Filters
+	var filters []string
+	for i, name := range historyStatusFilters {
+		if i == m.statusFilter {
+			filters = append(filters, m.styles.Text("["+name+"]", m.styles.SelectedTitleColor))
+		} else {
+			filters = append(filters, m.styles.Text(" "+name+" ", m.styles.MutedTitleColor))
+		}
+	}
+	b.WriteString("  " + strings.Join(filters, " "))
+
+	if m.searchMode || m.searchQuery != "" {
+		search := "🔍 " + m.searchQuery
+		if m.searchMode {
+			search += "█"
+		}
+		b.WriteString(m.styles.Text("   "+search, m.styles.SearchTextColor))
+	}
+	b.WriteString(m.styles.Text(fmt.Sprintf("   %d of %d", len(m.filtered), len(m.entries)), m.styles.MutedTitleColor))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.loadErr != nil:
+		b.WriteString(m.styles.Text("  ⚠️  Failed to load history: "+m.loadErr.Error(), m.styles.ErrorColor) + "\n")
+	case len(m.entries) == 0:
+		b.WriteString(m.styles.FooterStyle.Render("  No requests executed yet") + "\n")
+	case len(m.filtered) == 0:
+		b.WriteString(m.styles.FooterStyle.Render("  No matches found") + "\n")
+	}
+
+	end := min(m.offset+m.pageHeight(), len(m.filtered))
+	for i := m.offset; i < end; i++ {
+		b.WriteString(m.renderEntry(m.filtered[i], i == m.cursor))
+		b.WriteString("\n")
+	}
+
+	// Details of the selected execution
+	if entry, ok := m.selected(); ok {
+		b.WriteString("\n")
+		detail := entry.Error
+		if entry.Request != nil {
+			detail = entry.Request.Method + " " + entry.Request.URL
+		}
+		detail = lipgloss.NewStyle().MaxWidth(max(m.width-4, 20)).Render(detail)
+		b.WriteString(m.styles.Text("  "+detail, m.styles.FooterColor))
+		b.WriteString("\n")
+		b.WriteString(m.styles.Text(fmt.Sprintf("  %s • env: %s", entry.RequestFile, entry.Environment), m.styles.MutedTitleColor))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	var helpText string
+	if m.searchMode {
+		helpText = "  type to filter by request • enter done • esc clear"
+	} else if m.marked != nil {
+		helpText = "  ↑↓/jk navigate • d diff with marked • m unmark • enter open • r re-send • s status • / search • q quit"
+	} else {
+		helpText = "  ↑↓/jk navigate • enter open • r re-send • d diff with previous • m mark • s status • / search • q quit"
+	}
+	b.WriteString(m.styles.FooterStyle.Render(helpText + "\n"))
+
+	return b.String()
+}
+
+func (m HistoryViewModel) renderEntry(entry HistoryEntryJSON, selected bool) string {
+	var status string
+	var statusColor lipgloss.Color
+	if entry.Error != "" {
+		status, statusColor = "✗ ERR", m.styles.ErrorColor
+	} else {
+		status = fmt.Sprintf("%s %d", getStatusIcon(entry.StatusCode), entry.StatusCode)
+		statusColor = getStatusColor(entry.StatusCode, m.styles)
+	}
+
+	method := ""
+	if entry.Request != nil {
+		method = entry.Request.Method
+	}
+
+	nameColor := m.styles.MutedTitleColor
+	prefix := "  "
+	if selected {
+		nameColor = m.styles.SelectedTitleColor
+		prefix = m.styles.Text("❯ ", m.styles.SelectedTitleColor)
+	}
+	mark := " "
+	if m.marked != nil && sameHistoryEntry(*m.marked, entry) {
+		mark = m.styles.Text("◆", m.styles.PeachColor)
+	}
+
+	duration := time.Duration(entry.DurationMs * float64(time.Millisecond)).Round(time.Millisecond)
+
+	line := mark + " " + m.styles.Text(fmt.Sprintf("%-6s", status), statusColor) + "  " +
+		m.styles.Text(entry.Timestamp.Local().Format("Jan 02 15:04:05"), m.styles.MutedTitleColor) + "  " +
+		m.styles.Text(fmt.Sprintf("%-6s", method), m.styles.ThistleColor) + " " +
+		m.styles.Text(entry.Name, nameColor) + "  " +
+		m.styles.Text(duration.String(), m.styles.MutedTitleColor)
+
+	return prefix + lipgloss.NewStyle().MaxWidth(max(m.width-4, 20)).Render(line)
+}
+
+// diff compares the selected entry with the marked one, or with the previous run of the same request
+func (m HistoryViewModel) diff(entry HistoryEntryJSON) tea.Cmd {
+	if m.marked != nil && !sameHistoryEntry(*m.marked, entry) {
+		before, after := *m.marked, entry
+		if after.Timestamp.Before(before.Timestamp) {
+			before, after = after, before
+		}
+		return emit(diffResponsesMsg{before: before, after: after})
+	}
+
+	// Entries are newest first, so the previous run is listed after the selected one
+	for i, candidate := range m.entries {
+		if !sameHistoryEntry(candidate, entry) {
+			continue
+		}
+		for _, previous := range m.entries[i+1:] {
+			if previous.RequestFile == entry.RequestFile {
+				return emit(diffResponsesMsg{before: previous, after: entry})
+			}
+		}
+		break
+	}
+	return emit(statusMsg{text: "⚠️  No previous run of this request to compare with, mark another entry with m", isError: true})
+}
+
+// pageHeight is the number of entries shown at once
+func (m HistoryViewModel) pageHeight() int {
+	return max(m.height-12, 3)
+}
+
+func (m HistoryViewModel) selected() (HistoryEntryJSON, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.filtered) {
+		return HistoryEntryJSON{}, false
+	}
+	return m.filtered[m.cursor], true
+}
+
+func (m *HistoryViewModel) moveTo(index int) {
+	m.cursor = max(0, min(index, len(m.filtered)-1))
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+m.pageHeight() {
+		m.offset = m.cursor - m.pageHeight() + 1
+	}
+	m.offset = max(0, min(m.offset, len(m.filtered)-m.pageHeight()))
+}
+
+func (m *HistoryViewModel) applyFilters() {
+	m.filtered = nil
+	query := strings.ToLower(m.searchQuery)
+	status := historyStatusFilters[m.statusFilter]
+
+	for _, entry := range m.entries {
+		if !historyStatusMatches(entry, status) {
+			continue
+		}
+		if query != "" {
+			url := ""
+			if entry.Request != nil {
+				url = entry.Request.URL
+			}
+			if !fuzzyMatch(strings.ToLower(entry.Name), query) &&
+				!fuzzyMatch(strings.ToLower(entry.RequestFile), query) &&
+				!strings.Contains(strings.ToLower(url), query) {
+				continue
+			}
+		}
+		m.filtered = append(m.filtered, entry)
+	}
+	m.moveTo(m.cursor)
+}
+
+func historyStatusMatches(entry HistoryEntryJSON, filter string) bool {
+	switch filter {
+	case "all":
+		return true
+	case "errors":
+		return entry.Error != ""
+	}
+	if entry.Error != "" {
+		return false
+	}
+	return fmt.Sprintf("%dxx", entry.StatusCode/100) == filter
+}
+
+func sameHistoryEntry(a, b HistoryEntryJSON) bool {
+	return a.RequestFile == b.RequestFile && a.Timestamp.Equal(b.Timestamp)
+}
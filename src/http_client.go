@@ -2,10 +2,16 @@ package src
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
+	"net/http/httptrace"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -26,6 +32,20 @@ type HTTPResponse struct {
 	Duration   time.Duration
 	Size       int64
 	Error      error
+	Request    *ResolvedRequest // The request exactly as sent
+	StartedAt  time.Time
+	Timing     ResponseTiming
+}
+
+// ResponseTiming breaks a request down into its network phases.
+// Phases that did not happen (no TLS, reused DNS result...) stay at zero.
+type ResponseTiming struct {
+	DNS          time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	FirstByte    time.Duration // From the request being written to the first response byte
+	Download     time.Duration // Reading the response body
+	Total        time.Duration // Whole exchange, including the body download
 }
 
 func NewHTTPClient(config *ConfigJSON, secret *SecretJSON, configLoader *ConfigLoader) *HTTPClient {
@@ -36,20 +56,135 @@ func NewHTTPClient(config *ConfigJSON, secret *SecretJSON, configLoader *ConfigL
 	}
 }
 
-func (c *HTTPClient) ExecuteRequest(request *RequestJSON) (*HTTPResponse, error) {
-	response := &HTTPResponse{}
+// ResolvedRequest is a request with every variable resolved and headers merged, exactly as sent
+type ResolvedRequest struct {
+	Method   string            `json:"method"`
+	URL      string            `json:"url"`
+	Headers  http.Header       `json:"headers,omitempty"`
+	Body     interface{}       `json:"body,omitempty"`
+	RawBody  string            `json:"rawBody,omitempty"` // Sent instead of Body
+	Form     map[string]string `json:"form,omitempty"`    // multipart/form-data fields, encoded when sending
+	Insecure bool              `json:"insecure,omitempty"`
+}
+
+// ResolveRequest interpolates variables in the URL, header values and body string leaves
+func (c *HTTPClient) ResolveRequest(request *RequestJSON, scope map[string]string) (*ResolvedRequest, error) {
+	resolver := c.configLoader.NewResolver(c.config, scope)
+
+	url, err := resolver.Resolve(request.URL)
+	if err != nil {
+		return nil, fmt.Errorf("ResolveRequest -> url: %v", err)
+	}
+
+	var body interface{}
+	if request.Body != nil {
+		body, err = resolver.ResolveBody(request.Body)
+		if err != nil {
+			return nil, fmt.Errorf("ResolveRequest -> body: %v", err)
+		}
+	}
+
+	headers, err := c.mergeHeaders(request, resolver)
+	if err != nil {
+		return nil, fmt.Errorf("ResolveRequest -> %v", err)
+	}
+
+	rawBody, err := resolver.Resolve(request.RawBody)
+	if err != nil {
+		return nil, fmt.Errorf("ResolveRequest -> rawBody: %v", err)
+	}
+
+	var form map[string]string
+	if len(request.Form) > 0 {
+		form = map[string]string{}
+		for name, value := range request.Form {
+			if form[name], err = resolver.Resolve(value); err != nil {
+				return nil, fmt.Errorf("ResolveRequest -> form field %s: %v", name, err)
+			}
+		}
+		// The multipart Content-Type carries a boundary and is set when sending
+		headers.Del("Content-Type")
+	}
+
+	return &ResolvedRequest{
+		Method:   request.Method,
+		URL:      url,
+		Headers:  headers,
+		Body:     body,
+		RawBody:  rawBody,
+		Form:     form,
+		Insecure: request.Insecure,
+	}, nil
+}
+
+// buildMultipartBody encodes form fields in key order; "@path" values upload the file at path
+func buildMultipartBody(form map[string]string) (string, []byte, error) {
+	var buffer bytes.Buffer
+	writer := multipart.NewWriter(&buffer)
+
+	for _, name := range sortedStringKeys(form) {
+		value := form[name]
+		path, isFile := strings.CutPrefix(value, "@")
+		if !isFile {
+			if err := writer.WriteField(name, value); err != nil {
+				return "", nil, err
+			}
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", nil, fmt.Errorf("field %s: %v", name, err)
+		}
+		part, err := writer.CreateFormFile(name, filepath.Base(path))
+		if err != nil {
+			return "", nil, err
+		}
+		if _, err := part.Write(content); err != nil {
+			return "", nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", nil, err
+	}
+	return writer.FormDataContentType(), buffer.Bytes(), nil
+}
+
+func (c *HTTPClient) ExecuteRequest(request *RequestJSON, scope map[string]string) (*HTTPResponse, error) {
+	resolved, err := c.ResolveRequest(request, scope)
+	if err != nil {
+		response := &HTTPResponse{Error: fmt.Errorf("failed to resolve variables: %v", err)}
+		return response, response.Error
+	}
+
+	return c.ExecuteResolved(resolved)
+}
+
+// ExecuteResolved sends an already resolved request as-is
+func (c *HTTPClient) ExecuteResolved(resolved *ResolvedRequest) (*HTTPResponse, error) {
+	response := &HTTPResponse{Request: resolved}
 
 	// Start timing
 	startTime := time.Now()
-
-	// Interpolate URL
-	url := c.configLoader.ReplaceVariables(request.URL, c.config)
+	response.StartedAt = startTime
 
 	// Prepare body - ALWAYS create fresh buffer, never reuse
 	var bodyReader io.Reader
-	if request.Body != nil {
+	var multipartType string
+	if len(resolved.Form) > 0 {
+		contentType, form, err := buildMultipartBody(resolved.Form)
+		if err != nil {
+			response.Error = fmt.Errorf("failed to build form: %v", err)
+			return response, response.Error
+		}
+		multipartType = contentType
+		bodyReader = bytes.NewReader(form)
+	} else if resolved.RawBody != "" {
+		bodyReader = strings.NewReader(resolved.RawBody)
+	} else if resolved.Body != nil {
 		// Marshal to JSON each time (no caching)
-		bodyJSON, err := json.Marshal(request.Body)
+		bodyJSON, err := json.Marshal(resolved.Body)
 		if err != nil {
 			response.Error = fmt.Errorf("failed to marshal body: %v", err)
 			return response, response.Error
@@ -60,19 +195,36 @@ func (c *HTTPClient) ExecuteRequest(request *RequestJSON) (*HTTPResponse, error)
 	}
 
 	// Create NEW request each time (no reuse)
-	req, err := http.NewRequest(request.Method, url, bodyReader)
+	req, err := http.NewRequest(resolved.Method, resolved.URL, bodyReader)
 	if err != nil {
 		response.Error = fmt.Errorf("failed to create request: %v", err)
 		return response, response.Error
 	}
 
-	// ALWAYS set Content-Type for requests with body
-	if request.Body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	var dnsStart, connectStart, tlsStart, wroteRequest time.Time
+	trace := &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone:           func(httptrace.DNSDoneInfo) { response.Timing.DNS = time.Since(dnsStart) },
+		ConnectStart:      func(string, string) { connectStart = time.Now() },
+		ConnectDone:       func(string, string, error) { response.Timing.Connect = time.Since(connectStart) },
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			response.Timing.TLSHandshake = time.Since(tlsStart)
+		},
+		WroteRequest:         func(httptrace.WroteRequestInfo) { wroteRequest = time.Now() },
+		GotFirstResponseByte: func() { response.Timing.FirstByte = time.Since(wroteRequest) },
 	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
 
-	// Add headers
-	c.addHeaders(req, request)
+	// Add merged headers
+	for key, values := range resolved.Headers {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	if multipartType != "" {
+		req.Header.Set("Content-Type", multipartType)
+	}
 
 	// Get timeout from config (default: 30 seconds)
 	timeout := time.Duration(c.config.GetTimeout()) * time.Second
@@ -81,6 +233,12 @@ func (c *HTTPClient) ExecuteRequest(request *RequestJSON) (*HTTPResponse, error)
 	client := &http.Client{
 		Timeout: timeout,
 	}
+	if resolved.Insecure {
+		client.Transport = &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}
+	}
 
 	resp, err := client.Do(req)
 
@@ -101,7 +259,10 @@ func (c *HTTPClient) ExecuteRequest(request *RequestJSON) (*HTTPResponse, error)
 	response.Headers = resp.Header
 
 	// Read body
+	downloadStart := time.Now()
 	body, err := io.ReadAll(resp.Body)
+	response.Timing.Download = time.Since(downloadStart)
+	response.Timing.Total = time.Since(startTime)
 	if err != nil {
 		response.Error = fmt.Errorf("failed to read body: %v", err)
 		return response, response.Error
@@ -121,24 +282,49 @@ func (c *HTTPClient) ExecuteRequest(request *RequestJSON) (*HTTPResponse, error)
 	return response, nil
 }
 
-func (c *HTTPClient) addHeaders(req *http.Request, request *RequestJSON) {
+// mergeHeaders applies, in order: Content-Type for bodies, global, environment, JWT and request headers.
+// Later sources override earlier ones.
+func (c *HTTPClient) mergeHeaders(request *RequestJSON, resolver *VariableResolver) (http.Header, error) {
+	headers := http.Header{}
+
+	// ALWAYS set Content-Type for requests with body
+	if request.Body != nil {
+		headers.Set("Content-Type", "application/json")
+	}
+
+	set := func(source map[string]string, label string) error {
+		for key, value := range source {
+			resolved, err := resolver.Resolve(value)
+			if err != nil {
+				return fmt.Errorf("%s header %s: %v", label, key, err)
+			}
+			headers.Set(key, resolved)
+		}
+		return nil
+	}
+
 	// Global headers
-	if c.config.GlobalHeaders != nil {
-		for key, value := range c.config.GlobalHeaders {
-			req.Header.Set(key, value)
+	if err := set(c.config.GlobalHeaders, "global"); err != nil {
+		return nil, err
+	}
+
+	// Active environment headers (override global)
+	if env := c.config.GetActiveEnvironment(); env != nil {
+		if err := set(env.Headers, "environment"); err != nil {
+			return nil, err
 		}
 	}
 
 	// JWT (if not skipAuth)
 	jwt := c.configLoader.GetJWT(c.secret)
 	if !request.SkipAuth && jwt != "" {
-		req.Header.Set("Authorization", "Bearer "+jwt)
+		headers.Set("Authorization", "Bearer "+jwt)
 	}
 
 	// Request-specific headers (override previous)
-	if request.Headers != nil {
-		for key, value := range request.Headers {
-			req.Header.Set(key, value)
-		}
+	if err := set(request.Headers, "request"); err != nil {
+		return nil, err
 	}
+
+	return headers, nil
 }
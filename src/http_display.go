@@ -24,7 +24,8 @@ func (r *Runner) printRequestDetails(selectedRequest *RequestItem) {
 	methodColor := r.getMethodColor(req.Method, styles)
 	fmt.Println(styles.Text(fmt.Sprintf("  Method:   %s", req.Method), methodColor))
 
-	url := r.configLoader.ReplaceVariables(req.URL, r.config)
+	resolver := r.configLoader.NewResolver(r.config, selectedRequest.Variables)
+	url := resolver.ResolveLenient(req.URL)
 	fmt.Println(styles.Text(fmt.Sprintf("  URL:      %s", url), styles.FooterColor))
 	fmt.Println()
 
@@ -42,14 +43,21 @@ func (r *Runner) printRequestDetails(selectedRequest *RequestItem) {
 	// Global headers
 	if r.config.GlobalHeaders != nil {
 		for key, value := range r.config.GlobalHeaders {
-			fmt.Println(styles.Text(fmt.Sprintf("    %s: %s", key, value), styles.MutedTitleColor))
+			fmt.Println(styles.Text(fmt.Sprintf("    %s: %s", key, resolver.ResolveLenient(value)), styles.MutedTitleColor))
+		}
+	}
+
+	// Environment headers
+	if env := r.config.GetActiveEnvironment(); env != nil {
+		for key, value := range env.Headers {
+			fmt.Println(styles.Text(fmt.Sprintf("    %s: %s", key, resolver.ResolveLenient(value)), styles.ThistleColor))
 		}
 	}
 
 	// Request-specific headers
 	if req.Headers != nil {
 		for key, value := range req.Headers {
-			fmt.Println(styles.Text(fmt.Sprintf("    %s: %s", key, value), styles.FooterColor))
+			fmt.Println(styles.Text(fmt.Sprintf("    %s: %s", key, resolver.ResolveLenient(value)), styles.FooterColor))
 		}
 	}
 	fmt.Println()
@@ -60,6 +68,16 @@ func (r *Runner) printRequestDetails(selectedRequest *RequestItem) {
 		bodyJSON, _ := json.MarshalIndent(req.Body, "    ", "  ")
 		fmt.Println(styles.Text(string(bodyJSON), styles.FooterColor))
 	}
+	if req.RawBody != "" {
+		fmt.Println(styles.Text("  Body:", styles.TitleColor))
+		fmt.Println(styles.Text("    "+resolver.ResolveLenient(req.RawBody), styles.FooterColor))
+	}
+	if len(req.Form) > 0 {
+		fmt.Println(styles.Text("  Form:", styles.TitleColor))
+		for _, name := range sortedStringKeys(req.Form) {
+			fmt.Println(styles.Text(fmt.Sprintf("    %s=%s", name, resolver.ResolveLenient(req.Form[name])), styles.FooterColor))
+		}
+	}
 
 	fmt.Println()
 	fmt.Println(styles.Text("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━", styles.TitleColor))
@@ -67,30 +85,35 @@ func (r *Runner) printRequestDetails(selectedRequest *RequestItem) {
 }
 
 func (r *Runner) printResponse(response *HTTPResponse, requestName string) {
-	styles := DefaultStyles()
+	fmt.Print(renderResponse(response, requestName, DefaultStyles()))
+}
 
-	fmt.Println()
-	fmt.Println(styles.Text("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━", styles.TitleColor))
-	fmt.Println(styles.Text(fmt.Sprintf("  Response: %s", requestName), styles.SelectedTitleColor))
-	fmt.Println(styles.Text("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━", styles.TitleColor))
-	fmt.Println()
+// renderResponse formats a response the same way for the terminal and the TUI
+func renderResponse(response *HTTPResponse, requestName string, styles *Styles) string {
+	var b strings.Builder
+
+	b.WriteString("\n")
+	b.WriteString(styles.Text("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━", styles.TitleColor) + "\n")
+	b.WriteString(styles.Text(fmt.Sprintf("  Response: %s", requestName), styles.SelectedTitleColor) + "\n")
+	b.WriteString(styles.Text("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━", styles.TitleColor) + "\n")
+	b.WriteString("\n")
 
 	// Connection error
 	if response.Error != nil {
-		fmt.Println(styles.Text("  ❌ Error:", styles.ErrorColor))
-		errorMsg := r.formatError(response.Error)
-		fmt.Println(styles.Text("    "+errorMsg, styles.CoralColor))
-		fmt.Println()
-		fmt.Println(styles.Text("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━", styles.TitleColor))
-		fmt.Println()
-		return
+		b.WriteString(styles.Text("  ❌ Error:", styles.ErrorColor) + "\n")
+		errorMsg := formatError(response.Error)
+		b.WriteString(styles.Text("    "+errorMsg, styles.CoralColor) + "\n")
+		b.WriteString("\n")
+		b.WriteString(styles.Text("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━", styles.TitleColor) + "\n")
+		b.WriteString("\n")
+		return b.String()
 	}
 
 	// Status Code
-	statusColor := r.getStatusColor(response.StatusCode, styles)
-	statusIcon := r.getStatusIcon(response.StatusCode)
-	fmt.Println(styles.Text(fmt.Sprintf("  %s Status:   %d %s",
-		statusIcon, response.StatusCode, response.Status), statusColor))
+	statusColor := getStatusColor(response.StatusCode, styles)
+	statusIcon := getStatusIcon(response.StatusCode)
+	b.WriteString(styles.Text(fmt.Sprintf("  %s Status:   %d %s",
+		statusIcon, response.StatusCode, response.Status), statusColor) + "\n")
 
 	// Duration
 	durationColor := styles.FooterColor
@@ -101,45 +124,77 @@ func (r *Runner) printResponse(response *HTTPResponse, requestName string) {
 	} else {
 		durationColor = styles.AquamarineColor
 	}
-	fmt.Println(styles.Text(fmt.Sprintf("  ⏱️  Duration: %s", response.Duration), durationColor))
+	b.WriteString(styles.Text(fmt.Sprintf("  ⏱️  Duration: %s", response.Duration), durationColor) + "\n")
 
 	// Size
-	fmt.Println(styles.Text(fmt.Sprintf("  📦 Size:     %s", formatBytes(response.Size)), styles.MutedTitleColor))
-	fmt.Println()
+	b.WriteString(styles.Text(fmt.Sprintf("  📦 Size:     %s", formatBytes(response.Size)), styles.MutedTitleColor) + "\n")
+	b.WriteString("\n")
 
 	// Response headers
-	fmt.Println(styles.Text("  📋 Response Headers:", styles.TitleColor))
+	b.WriteString(styles.Text("  📋 Response Headers:", styles.TitleColor) + "\n")
 	for key, values := range response.Headers {
 		for _, value := range values {
-			fmt.Println(styles.Text(fmt.Sprintf("    %s: %s", key, value), styles.FooterColor))
+			b.WriteString(styles.Text(fmt.Sprintf("    %s: %s", key, value), styles.FooterColor) + "\n")
 		}
 	}
-	fmt.Println()
+	b.WriteString("\n")
 
 	// Body
 	if len(response.Body) == 0 {
-		fmt.Println(styles.Text("  📄 Body: (empty)", styles.MutedTitleColor))
+		b.WriteString(styles.Text("  📄 Body: (empty)", styles.MutedTitleColor) + "\n")
 	} else {
-		fmt.Println(styles.Text("  📄 Body:", styles.TitleColor))
+		b.WriteString(styles.Text("  📄 Body:", styles.TitleColor) + "\n")
 
 		if response.IsJSON {
 			prettyJSON, _ := json.MarshalIndent(response.BodyJSON, "    ", "  ")
-			fmt.Println(styles.Text(string(prettyJSON), styles.FooterColor))
+			b.WriteString(styles.Text(string(prettyJSON), styles.FooterColor) + "\n")
 		} else {
 			displayBody := response.BodyString
 			if len(displayBody) > 1000 {
 				displayBody = displayBody[:1000] + "... (truncated)"
 			}
-			fmt.Println(styles.Text("    "+displayBody, styles.FooterColor))
+			b.WriteString(styles.Text("    "+displayBody, styles.FooterColor) + "\n")
 		}
 	}
 
-	fmt.Println()
-	fmt.Println(styles.Text("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━", styles.TitleColor))
-	fmt.Println()
+	b.WriteString("\n")
+	b.WriteString(styles.Text("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━", styles.TitleColor) + "\n")
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (r *Runner) printCaptures(captures []CaptureResult) {
+	fmt.Print(renderCaptures(captures, DefaultStyles()))
+}
+
+func renderCaptures(captures []CaptureResult, styles *Styles) string {
+	if len(captures) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+
+	b.WriteString(styles.Text("  🔗 Captures:", styles.TitleColor) + "\n")
+	for _, capture := range captures {
+		if capture.Error != nil {
+			b.WriteString(styles.Text(fmt.Sprintf("    ✗ %s ← %s: %v", capture.Name, capture.From, capture.Error), styles.CoralColor) + "\n")
+			continue
+		}
+
+		target := "session"
+		if capture.Name == CaptureJWTTarget {
+			target = "secret.json"
+		} else if capture.Persist {
+			target = "state.json"
+		}
+		b.WriteString(styles.Text(fmt.Sprintf("    ✓ %s ← %s (%s)", capture.Name, capture.From, target), styles.AquamarineColor) + "\n")
+	}
+	b.WriteString("\n")
+
+	return b.String()
 }
 
-func (r *Runner) getStatusColor(statusCode int, styles *Styles) lipgloss.Color {
+func getStatusColor(statusCode int, styles *Styles) lipgloss.Color {
 	switch {
 	case statusCode >= 200 && statusCode < 300:
 		return styles.AquamarineColor
@@ -154,7 +209,7 @@ func (r *Runner) getStatusColor(statusCode int, styles *Styles) lipgloss.Color {
 	}
 }
 
-func (r *Runner) getStatusIcon(statusCode int) string {
+func getStatusIcon(statusCode int) string {
 	switch {
 	case statusCode >= 200 && statusCode < 300:
 		return "✓"
@@ -169,7 +224,7 @@ func (r *Runner) getStatusIcon(statusCode int) string {
 	}
 }
 
-func (r *Runner) formatError(err error) string {
+func formatError(err error) string {
 	errStr := err.Error()
 
 	if strings.Contains(errStr, "timeout") {
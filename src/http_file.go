@@ -0,0 +1,330 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HTTP files (.http/.rest) as written for the VS Code REST Client: requests separated by ###,
+// "@name = value" file variables and "# @name login" request metadata.
+//
+//	@host = https://api.example.com
+//
+//	### Log in
+//	# @name login
+//	POST {{host}}/login
+//	Content-Type: application/json
+//
+//	{"email": "user@example.com"}
+
+const httpFileBoundary = "PostlessBoundary"
+
+var (
+	httpFileVariablePattern = regexp.MustCompile(`^@([A-Za-z0-9_.\-]+)\s*=\s*(.*)$`)
+	httpFileMetaPattern     = regexp.MustCompile(`^(?:#|//)\s*@([A-Za-z0-9_\-]+)\s*(.*)$`)
+	httpFileRequestPattern  = regexp.MustCompile(`^(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS|TRACE|CONNECT)\s+(.+?)(?:\s+HTTP/[0-9.]+)?$`)
+	httpFileHeaderPattern   = regexp.MustCompile(`^([^\s:]+)\s*:\s*(.*)$`)
+)
+
+// HTTPFile is a parsed .http file
+type HTTPFile struct {
+	Variables map[string]string // File variables, visible to every request of the file
+	Requests  []HTTPFileRequest
+	Problems  []DocumentError // Lines that were read differently than they look, such as malformed headers
+}
+
+// HTTPFileRequest is one request of an .http file and where it sits in the file
+type HTTPFileRequest struct {
+	Section    string // The "# @name" of the request, or its 1-based position in the file
+	Request    *RequestJSON
+	Line       int // 1-based line of the request line
+	headersEnd int // 0-based line after the headers
+	bodyStart  int // 0-based first body line, -1 without a body
+	bodyEnd    int // 0-based line after the body
+}
+
+// IsHTTPFile reports whether a request file uses the .http format
+func IsHTTPFile(filePath string) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	return ext == HTTPFileExtension || ext == RestFileExtension
+}
+
+// ParseHTTPFile reads the requests and file variables of an .http file. Blocks without a
+// request line (only comments or variables) are not requests and are skipped.
+func ParseHTTPFile(content string) *HTTPFile {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	file := &HTTPFile{Variables: make(map[string]string)}
+
+	start := 0
+	for i := 0; i <= len(lines); i++ {
+		if i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "###") {
+			continue
+		}
+		if request, ok := parseHTTPFileBlock(lines, start, i, file); ok {
+			if request.Section == "" {
+				request.Section = strconv.Itoa(len(file.Requests) + 1)
+			}
+			file.Requests = append(file.Requests, request)
+		}
+		start = i + 1
+	}
+	return file
+}
+
+// parseHTTPFileBlock reads the request between lines start and end (exclusive), adding
+// variables declared before the request line and problems to the file
+func parseHTTPFileBlock(lines []string, start, end int, file *HTTPFile) (HTTPFileRequest, bool) {
+	parsed := HTTPFileRequest{bodyStart: -1}
+	request := &RequestJSON{}
+	title := ""
+	if start > 0 {
+		title = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(lines[start-1]), "#"))
+	}
+
+	i := start
+	for ; i < end; i++ {
+		line := strings.TrimSpace(lines[i])
+		switch {
+		case line == "":
+			continue
+		case httpFileVariablePattern.MatchString(line):
+			match := httpFileVariablePattern.FindStringSubmatch(line)
+			file.Variables[match[1]] = strings.TrimSpace(match[2])
+			continue
+		case httpFileMetaPattern.MatchString(line):
+			match := httpFileMetaPattern.FindStringSubmatch(line)
+			switch match[1] {
+			case "name":
+				parsed.Section = strings.TrimSpace(match[2])
+			case "no-auth":
+				request.SkipAuth = true
+			case "insecure":
+				request.Insecure = true
+			}
+			continue
+		case strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//"):
+			if title == "" {
+				title = strings.TrimSpace(strings.TrimLeft(line, "#/"))
+			}
+			continue
+		}
+		break
+	}
+	if i == end {
+		return parsed, false
+	}
+
+	// Request line: "METHOD URL [HTTP/1.1]", or only the URL for a GET
+	parsed.Line = i + 1
+	line := strings.TrimSpace(lines[i])
+	if match := httpFileRequestPattern.FindStringSubmatch(line); match != nil {
+		request.Method, request.URL = match[1], strings.TrimSpace(match[2])
+	} else {
+		request.Method, request.URL = "GET", strings.TrimSuffix(strings.TrimSuffix(line, " HTTP/1.1"), " HTTP/2")
+		if fields := strings.Fields(request.URL); len(fields) > 1 {
+			file.Problems = append(file.Problems, DocumentError{Line: i + 1, Column: 1, Message: fmt.Sprintf("unknown method %q, the line is sent as a GET URL", fields[0])})
+		}
+	}
+
+	// Query parameters continued on the following lines: "?page=1" and "&size=10"
+	for i++; i < end; i++ {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, "?") && !strings.HasPrefix(line, "&") {
+			break
+		}
+		request.URL += line
+	}
+
+	for ; i < end; i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		if match := httpFileHeaderPattern.FindStringSubmatch(line); match != nil {
+			if request.Headers == nil {
+				request.Headers = make(map[string]string)
+			}
+			request.Headers[match[1]] = strings.TrimSpace(match[2])
+			continue
+		}
+		file.Problems = append(file.Problems, DocumentError{Line: i + 1, Column: 1, Message: fmt.Sprintf("%q is not a header (Name: value) and is ignored", line)})
+	}
+	parsed.headersEnd = i
+
+	// The body runs from the blank line after the headers to the next ###, without trailing blank lines
+	bodyEnd := end
+	for bodyEnd > i+1 && strings.TrimSpace(lines[bodyEnd-1]) == "" {
+		bodyEnd--
+	}
+	if i+1 < bodyEnd {
+		parsed.bodyStart, parsed.bodyEnd = i+1, bodyEnd
+		setHTTPFileBody(request, strings.Join(lines[i+1:bodyEnd], "\n"))
+	}
+
+	request.Name = parsed.Section
+	if title != "" {
+		request.Name = title
+	}
+	if request.Name == "" {
+		request.Name = defaultRequestName(request.Method, request.URL)
+	}
+	parsed.Request = request
+	return parsed, true
+}
+
+// setHTTPFileBody keeps JSON bodies as structured bodies, so they can be edited and resolved like
+// request files, and anything else (including JSON with unquoted {{variables}}) as a raw body
+func setHTTPFileBody(request *RequestJSON, body string) {
+	contentType := ""
+	if name, ok := lookupHeader(request.Headers, "Content-Type"); ok {
+		contentType = strings.ToLower(request.Headers[name])
+	}
+
+	var parsed interface{}
+	if (contentType == "" || strings.Contains(contentType, "json")) && json.Unmarshal([]byte(body), &parsed) == nil {
+		if _, isObject := parsed.(map[string]interface{}); isObject || contentType != "" {
+			request.Body = parsed
+			return
+		}
+	}
+	request.RawBody = body
+}
+
+// httpFileBody writes the body of a request the way .http files expect it
+func httpFileBody(request *RequestJSON) (string, error) {
+	switch {
+	case len(request.Form) > 0:
+		var body strings.Builder
+		for _, name := range sortedStringKeys(request.Form) {
+			value := request.Form[name]
+			body.WriteString("--" + httpFileBoundary + "\n")
+			if filePath, isFile := strings.CutPrefix(value, "@"); isFile {
+				fmt.Fprintf(&body, "Content-Disposition: form-data; name=%q; filename=%q\n\n< %s\n", name, filepath.Base(filePath), filePath)
+				continue
+			}
+			fmt.Fprintf(&body, "Content-Disposition: form-data; name=%q\n\n%s\n", name, value)
+		}
+		body.WriteString("--" + httpFileBoundary + "--")
+		return body.String(), nil
+	case request.Body != nil:
+		return ToJSON(request.Body)
+	}
+	return request.RawBody, nil
+}
+
+// FormatHTTPFile writes requests as an .http file, with variables declared at the top.
+// Variables of the requests (.http file variables) are declared as well.
+// Captures, assertions and snapshots have no .http equivalent; warnings list the requests that lose them.
+func FormatHTTPFile(variables map[string]string, items []RequestItem) (string, []string, error) {
+	var blocks []string
+	var warnings []string
+
+	declared := make(map[string]string, len(variables))
+	for name, value := range variables {
+		declared[name] = value
+	}
+	for _, item := range items {
+		for name, value := range item.Variables {
+			declared[name] = value
+		}
+	}
+	if len(declared) > 0 {
+		var declarations []string
+		for _, name := range sortedStringKeys(declared) {
+			declarations = append(declarations, fmt.Sprintf("@%s = %s", name, declared[name]))
+		}
+		blocks = append(blocks, strings.Join(declarations, "\n"))
+	}
+
+	sections := make(map[string]bool)
+	for _, item := range items {
+		request := item.Request
+		if len(request.Captures) > 0 || request.Assertions != nil || request.Snapshot != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: captures, assertions and snapshots are left out", item.Name))
+		}
+
+		// Named requests of .http files keep their name, so references to them still work
+		base := item.Section
+		if _, err := strconv.Atoi(base); base == "" || err == nil {
+			base = requestFileSlug(request.Name)
+		}
+		section := base
+		for i := 2; sections[section]; i++ {
+			section = fmt.Sprintf("%s-%d", base, i)
+		}
+		sections[section] = true
+
+		lines := []string{"### " + request.Name, "# @name " + section}
+		if request.SkipAuth {
+			lines = append(lines, "# @no-auth")
+		}
+		if request.Insecure {
+			lines = append(lines, "# @insecure")
+		}
+		lines = append(lines, request.Method+" "+request.URL)
+
+		headers := make(map[string]string, len(request.Headers)+1)
+		for name, value := range request.Headers {
+			headers[name] = value
+		}
+		contentTypeName, hasContentType := lookupHeader(headers, "Content-Type")
+		switch {
+		case len(request.Form) > 0:
+			if hasContentType {
+				delete(headers, contentTypeName)
+			}
+			headers["Content-Type"] = "multipart/form-data; boundary=" + httpFileBoundary
+		case request.Body != nil && !hasContentType:
+			headers["Content-Type"] = "application/json"
+		}
+		for _, name := range sortedStringKeys(headers) {
+			lines = append(lines, name+": "+headers[name])
+		}
+
+		body, err := httpFileBody(request)
+		if err != nil {
+			return "", warnings, fmt.Errorf("FormatHTTPFile -> %s: %v", item.Name, err)
+		}
+		if body != "" {
+			lines = append(lines, "", body)
+		}
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+
+	return strings.Join(blocks, "\n\n") + "\n", warnings, nil
+}
+
+// ReplaceHTTPFileBody rewrites the body of one request of an .http file, leaving the rest of the file as it is
+func ReplaceHTTPFileBody(content, section string, request *RequestJSON) (string, error) {
+	file := ParseHTTPFile(content)
+	for _, parsed := range file.Requests {
+		if parsed.Section != section {
+			continue
+		}
+
+		body, err := httpFileBody(request)
+		if err != nil {
+			return "", fmt.Errorf("ReplaceHTTPFileBody -> %v", err)
+		}
+		lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+		var updated []string
+		if parsed.bodyStart < 0 {
+			updated = append(updated, lines[:parsed.headersEnd]...)
+			updated = append(updated, "", body)
+			updated = append(updated, lines[parsed.headersEnd:]...)
+		} else {
+			updated = append(updated, lines[:parsed.bodyStart]...)
+			updated = append(updated, body)
+			updated = append(updated, lines[parsed.bodyEnd:]...)
+		}
+		return strings.Join(updated, "\n"), nil
+	}
+	return "", fmt.Errorf("ReplaceHTTPFileBody -> request %q not found", section)
+}
@@ -0,0 +1,222 @@
+package src
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// compactTestJSON normalizes expected JSON for comparisons with ToCompactJSON
+func compactTestJSON(t *testing.T, content string) string {
+	t.Helper()
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, []byte(content)); err != nil {
+		t.Fatalf("invalid test JSON: %v", err)
+	}
+	return compacted.String()
+}
+
+func TestParseHTTPFile(t *testing.T) {
+	type parsedRequest struct {
+		section string
+		line    int
+		request string // JSON of the request
+	}
+
+	tests := []struct {
+		name      string
+		content   string
+		variables map[string]string
+		requests  []parsedRequest
+		problems  []DocumentError
+	}{
+		{
+			name:      "variables, names and JSON bodies",
+			content:   "@host = https://api.test\n\n### Log in\n# @name login\nPOST {{host}}/login HTTP/1.1\nContent-Type: application/json\n\n{\"email\": \"a@b.c\"}\n\n\n###\n\nGET {{host}}/me\n",
+			variables: map[string]string{"host": "https://api.test"},
+			requests: []parsedRequest{
+				{section: "login", line: 5, request: `{"name": "Log in", "method": "POST", "url": "{{host}}/login", "skipAuth": false, "headers": {"Content-Type": "application/json"}, "body": {"email": "a@b.c"}}`},
+				{section: "2", line: 13, request: `{"name": "GET {{host}}/me", "method": "GET", "url": "{{host}}/me", "skipAuth": false}`},
+			},
+		},
+		{
+			name:    "URL alone, query continuations and metadata",
+			content: "// @no-auth\n# @insecure\nhttps://api.test/s\n  ?q=a\n  &page=2\r\nAccept: text/plain\n",
+			requests: []parsedRequest{
+				{section: "1", line: 3, request: `{"name": "GET /s", "method": "GET", "url": "https://api.test/s?q=a&page=2", "skipAuth": true, "headers": {"Accept": "text/plain"}, "insecure": true}`},
+			},
+		},
+		{
+			name:    "other bodies are raw",
+			content: "POST /form\nContent-Type: application/x-www-form-urlencoded\n\na=1&b={{b}}\n\n### JSON with unquoted variables\nPOST /json\n\n{\"id\": {{id}}}\n",
+			requests: []parsedRequest{
+				{section: "1", line: 1, request: `{"name": "POST /form", "method": "POST", "url": "/form", "skipAuth": false, "headers": {"Content-Type": "application/x-www-form-urlencoded"}, "rawBody": "a=1&b={{b}}"}`},
+				{section: "2", line: 7, request: `{"name": "JSON with unquoted variables", "method": "POST", "url": "/json", "skipAuth": false, "rawBody": "{\"id\": {{id}}}"}`},
+			},
+		},
+		{
+			name:      "blocks without a request line are skipped",
+			content:   "### Only a comment\n# nothing here\n\n###\n@a = 1\n",
+			variables: map[string]string{"a": "1"},
+		},
+		{
+			name:    "suspicious lines are reported",
+			content: "FETCH /x\nnot a header\n",
+			requests: []parsedRequest{
+				{section: "1", line: 1, request: `{"name": "GET FETCH /x", "method": "GET", "url": "FETCH /x", "skipAuth": false}`},
+			},
+			problems: []DocumentError{
+				{Line: 1, Column: 1, Message: `unknown method "FETCH", the line is sent as a GET URL`},
+				{Line: 2, Column: 1, Message: `"not a header" is not a header (Name: value) and is ignored`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := ParseHTTPFile(tt.content)
+			if fmt.Sprint(file.Variables) != fmt.Sprint(tt.variables) { // nil and empty maps alike
+				t.Errorf("got variables %v, want %v", file.Variables, tt.variables)
+			}
+			if !reflect.DeepEqual(file.Problems, tt.problems) {
+				t.Errorf("got problems %+v, want %+v", file.Problems, tt.problems)
+			}
+			if len(file.Requests) != len(tt.requests) {
+				t.Fatalf("got %d requests, want %d", len(file.Requests), len(tt.requests))
+			}
+			for i, want := range tt.requests {
+				got := file.Requests[i]
+				if got.Section != want.section || got.Line != want.line {
+					t.Errorf("request %d: got section %q at line %d, want %q at line %d", i, got.Section, got.Line, want.section, want.line)
+				}
+				request, err := ToCompactJSON(got.Request)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if wantRequest := compactTestJSON(t, want.request); request != wantRequest {
+					t.Errorf("request %d:\ngot  %s\nwant %s", i, request, wantRequest)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatHTTPFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		variables map[string]string
+		items     []RequestItem
+		want      string
+		warnings  []string
+	}{
+		{
+			name:      "variables, JSON body and flags",
+			variables: map[string]string{"host": "https://api.test"},
+			items: []RequestItem{{
+				Name:      "Create user",
+				Variables: map[string]string{"tenant": "acme"},
+				Request: &RequestJSON{
+					Name: "Create user", Method: "POST", URL: "{{host}}/users", SkipAuth: true, Insecure: true,
+					Headers: map[string]string{"X-Tenant": "{{tenant}}"},
+					Body:    map[string]interface{}{"name": "Ada"},
+				},
+			}},
+			want: "@host = https://api.test\n@tenant = acme\n\n" +
+				"### Create user\n# @name create-user\n# @no-auth\n# @insecure\nPOST {{host}}/users\nContent-Type: application/json\nX-Tenant: {{tenant}}\n\n{\n  \"name\": \"Ada\"\n}\n",
+		},
+		{
+			name: "named sections are kept, others are unique slugs",
+			items: []RequestItem{
+				{Name: "Login", Section: "login", Request: &RequestJSON{Name: "Login", Method: "POST", URL: "/login", RawBody: "a=1"}},
+				{Name: "List", Section: "2", Request: &RequestJSON{Name: "List", Method: "GET", URL: "/a"}},
+				{Name: "List", Request: &RequestJSON{Name: "List", Method: "GET", URL: "/b"}},
+			},
+			want: "### Login\n# @name login\nPOST /login\n\na=1\n\n" +
+				"### List\n# @name list\nGET /a\n\n" +
+				"### List\n# @name list-2\nGET /b\n",
+		},
+		{
+			name: "multipart forms and lost features",
+			items: []RequestItem{{
+				Name: "Upload",
+				Request: &RequestJSON{
+					Name: "Upload", Method: "POST", URL: "/up",
+					Headers:  map[string]string{"content-type": "multipart/form-data"},
+					Form:     map[string]string{"file": "@./docs/a.png", "title": "A"},
+					Captures: map[string]CaptureJSON{"id": {From: "$.id"}},
+				},
+			}},
+			want: "### Upload\n# @name upload\nPOST /up\nContent-Type: multipart/form-data; boundary=PostlessBoundary\n\n" +
+				"--PostlessBoundary\nContent-Disposition: form-data; name=\"file\"; filename=\"a.png\"\n\n< ./docs/a.png\n" +
+				"--PostlessBoundary\nContent-Disposition: form-data; name=\"title\"\n\nA\n--PostlessBoundary--\n",
+			warnings: []string{"Upload: captures, assertions and snapshots are left out"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warnings, err := FormatHTTPFile(tt.variables, tt.items)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+			if !reflect.DeepEqual(warnings, tt.warnings) {
+				t.Errorf("got warnings %q, want %q", warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestReplaceHTTPFileBody(t *testing.T) {
+	const content = "@host = x\n\n### Create\n# @name create\nPOST /users\nContent-Type: application/json\n\n{\"name\": \"Ada\"}\n\n### Ping\nGET /ping\n\n###\nPOST /raw\n\nold\n"
+
+	tests := []struct {
+		name    string
+		section string
+		request *RequestJSON
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "body between other requests",
+			section: "create",
+			request: &RequestJSON{Body: map[string]interface{}{"name": "Grace"}},
+			want:    "@host = x\n\n### Create\n# @name create\nPOST /users\nContent-Type: application/json\n\n{\n  \"name\": \"Grace\"\n}\n\n### Ping\nGET /ping\n\n###\nPOST /raw\n\nold\n",
+		},
+		{
+			name:    "request without a body",
+			section: "2",
+			request: &RequestJSON{RawBody: "hello"},
+			want:    "@host = x\n\n### Create\n# @name create\nPOST /users\nContent-Type: application/json\n\n{\"name\": \"Ada\"}\n\n### Ping\nGET /ping\n\nhello\n\n###\nPOST /raw\n\nold\n",
+		},
+		{
+			name:    "last request",
+			section: "3",
+			request: &RequestJSON{RawBody: "new"},
+			want:    "@host = x\n\n### Create\n# @name create\nPOST /users\nContent-Type: application/json\n\n{\"name\": \"Ada\"}\n\n### Ping\nGET /ping\n\n###\nPOST /raw\n\nnew\n",
+		},
+		{name: "unknown section", section: "nope", request: &RequestJSON{}, wantErr: `ReplaceHTTPFileBody -> request "nope" not found`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReplaceHTTPFileBody(content, tt.section, tt.request)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package src
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const importUsage = `Usage:
+  postless import curl --collection <name> [--name <request name>] ['curl ...' | -]
+  postless import postman <collection.json|environment.json>...
+  postless import openapi <spec.json|spec.yaml> [--sync]
+  postless import har <capture.har> --collection <name> [--entries 1,3-5] [--filter <regexp>] [--method GET,POST] [--all]
+  postless import har <capture.har> --list
+
+Without a command argument (or with -), the cURL command is read from stdin.
+Postman exports must use the Collection v2.1 format; environment and globals
+exports are added to config.json. OpenAPI 3 operations become one request per
+operation in one collection per tag; --sync adds new operations and leaves
+existing request files untouched.
+HAR captures (browser devtools "Save all as HAR") are listed with --list; the selected
+entries become requests in one collection. Page assets (scripts, stylesheets, images,
+fonts) are skipped unless --all is set or they are selected with --entries.
+`
+
+// importCommand implements "postless import <format>"
+func (r *Runner) importCommand(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, importUsage)
+		return ExitCodeError
+	}
+
+	switch args[0] {
+	case "curl":
+		return r.importCurlCommand(args[1:])
+	case "postman":
+		return r.importPostmanCommand(args[1:])
+	case "openapi":
+		return r.importOpenAPICommand(args[1:])
+	case "har":
+		return r.importHARCommand(args[1:])
+	case "help", "-h", "--help":
+		fmt.Print(importUsage)
+		return ExitCodeOK
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown import format %q\n\n%s", args[0], importUsage)
+		return ExitCodeError
+	}
+}
+
+func (r *Runner) importCurlCommand(args []string) int {
+	fs := newCommandFlagSet("import curl", "import curl --collection <name> [flags] ['curl ...' | -]")
+	collection := fs.String("collection", "", "collection to save the request into, created when missing")
+	name := fs.String("name", "", `request name (default: method and path, e.g. "POST /login")`)
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return ExitCodeError
+	}
+	if *collection == "" || len(positional) > 1 {
+		fs.Usage()
+		return ExitCodeError
+	}
+
+	command := ""
+	if len(positional) == 1 && positional[0] != "-" {
+		command = positional[0]
+	} else {
+		input, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to read stdin:", err)
+			return ExitCodeError
+		}
+		command = string(input)
+	}
+
+	if _, err := r.loadProject(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+
+	request, filePath, warnings, err := r.configLoader.ImportCurl(r.config, *collection, *name, command)
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, "Warning:", warning)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Import failed:", err)
+		return ExitCodeError
+	}
+
+	styles := DefaultStyles()
+	fmt.Println(styles.Text(fmt.Sprintf("✓ Imported %s → %s", request.Name, r.displayPath(filePath)), styles.AquamarineColor))
+	return ExitCodeOK
+}
+
+func (r *Runner) importPostmanCommand(args []string) int {
+	fs := newCommandFlagSet("import postman", "import postman <collection.json|environment.json>...")
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return ExitCodeError
+	}
+	if len(positional) == 0 {
+		fs.Usage()
+		return ExitCodeError
+	}
+
+	if _, err := r.loadProject(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+
+	styles := DefaultStyles()
+	exitCode := ExitCodeOK
+	for _, path := range positional {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Import failed:", err)
+			exitCode = ExitCodeError
+			continue
+		}
+
+		result, err := r.configLoader.ImportPostman(r.config, string(content))
+		if result != nil {
+			for _, request := range result.Requests {
+				fmt.Println(styles.Text(fmt.Sprintf("✓ Imported %s/%s → %s", request.Collection, request.Name, r.displayPath(request.FilePath)), styles.AquamarineColor))
+			}
+			for _, environment := range result.Environments {
+				fmt.Println(styles.Text(fmt.Sprintf("✓ Imported environment %s into config.json", environment), styles.AquamarineColor))
+			}
+			for _, warning := range result.Warnings {
+				fmt.Fprintln(os.Stderr, "Warning:", warning)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Import of %s failed: %v\n", path, err)
+			exitCode = ExitCodeError
+		}
+	}
+	return exitCode
+}
+
+func (r *Runner) importOpenAPICommand(args []string) int {
+	fs := newCommandFlagSet("import openapi", "import openapi <spec.json|spec.yaml> [flags]")
+	sync := fs.Bool("sync", false, "add operations without a request file yet, keep existing files as they are")
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return ExitCodeError
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return ExitCodeError
+	}
+
+	content, err := os.ReadFile(positional[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Import failed:", err)
+		return ExitCodeError
+	}
+	if _, err := r.loadProject(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+
+	result, err := r.configLoader.ImportOpenAPI(r.config, string(content), *sync)
+	styles := DefaultStyles()
+	if result != nil {
+		for _, request := range result.Requests {
+			fmt.Println(styles.Text(fmt.Sprintf("✓ Imported %s/%s → %s", request.Collection, request.Name, r.displayPath(request.FilePath)), styles.AquamarineColor))
+		}
+		if len(result.Kept) > 0 {
+			fmt.Println(styles.Text(fmt.Sprintf("• Kept %s already in the project", pluralize(len(result.Kept), "request file")), styles.MutedTitleColor))
+		}
+		for _, warning := range result.Warnings {
+			fmt.Fprintln(os.Stderr, "Warning:", warning)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Import failed:", err)
+		return ExitCodeError
+	}
+	return ExitCodeOK
+}
+
+func (r *Runner) importHARCommand(args []string) int {
+	fs := newCommandFlagSet("import har", "import har <capture.har> --collection <name> [flags]")
+	collection := fs.String("collection", "", "collection to save the requests into, created when missing")
+	list := fs.Bool("list", false, "print the numbered entries of the archive and exit")
+	entries := fs.String("entries", "", `entry numbers to import, as shown by --list (e.g. "1,3-5")`)
+	filter := fs.String("filter", "", "only import entries whose URL matches this regular expression")
+	methods := fs.String("method", "", `only import these methods (e.g. "POST,PUT")`)
+	all := fs.Bool("all", false, "also import page assets: scripts, stylesheets, images, fonts...")
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return ExitCodeError
+	}
+	if len(positional) != 1 || (*collection == "" && !*list) {
+		fs.Usage()
+		return ExitCodeError
+	}
+
+	content, err := os.ReadFile(positional[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Import failed:", err)
+		return ExitCodeError
+	}
+	archive, err := ParseHAR(string(content))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Import failed:", err)
+		return ExitCodeError
+	}
+
+	selection := HARSelection{IncludeStatic: *all || *list}
+	if selection.Indexes, err = ParseEntryIndexes(*entries); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+	if *filter != "" {
+		if selection.Pattern, err = regexp.Compile(*filter); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid --filter: %v\n", err)
+			return ExitCodeError
+		}
+	}
+	for _, method := range strings.Split(*methods, ",") {
+		if method = strings.TrimSpace(method); method != "" {
+			selection.Methods = append(selection.Methods, method)
+		}
+	}
+
+	selected, err := SelectHAREntries(archive, selection)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+
+	styles := DefaultStyles()
+	if *list {
+		for _, entry := range selected {
+			line := fmt.Sprintf("%4d  %-7s %3d  %s", entry.Index, entry.Method, entry.Status, entry.URL)
+			if entry.Static {
+				line = styles.Text(line, styles.MutedTitleColor)
+			}
+			fmt.Println(line)
+		}
+		return ExitCodeOK
+	}
+	if len(selected) == 0 {
+		fmt.Fprintln(os.Stderr, "No entries selected, use --list to see the entries of the archive")
+		return ExitCodeError
+	}
+
+	if _, err := r.loadProject(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+
+	result, err := r.configLoader.ImportHAR(r.config, *collection, selected)
+	for _, request := range result.Requests {
+		fmt.Println(styles.Text(fmt.Sprintf("✓ Imported %s/%s → %s", request.Collection, request.Name, r.displayPath(request.FilePath)), styles.AquamarineColor))
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintln(os.Stderr, "Warning:", warning)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Import failed:", err)
+		return ExitCodeError
+	}
+	return ExitCodeOK
+}
+
+// displayPath shortens a path inside the current directory for messages
+func (r *Runner) displayPath(path string) string {
+	if fm, ok := r.fileManager.(*FileManager); ok {
+		if relative, found := strings.CutPrefix(path, fm.CurrentDir+string(os.PathSeparator)); found {
+			return relative
+		}
+	}
+	return path
+}
@@ -0,0 +1,180 @@
+package src
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ImportedRequest is a request written (or kept) by an import
+type ImportedRequest struct {
+	Name       string
+	Collection string
+	FilePath   string
+}
+
+// ImportResult reports what an import wrote and what it could not convert
+type ImportResult struct {
+	Requests     []ImportedRequest
+	Kept         []ImportedRequest // Existing request files left untouched by a re-sync
+	Environments []string
+	Warnings     []string
+}
+
+// SaveImportedRequest writes an imported request into a collection, creating the collection
+// when needed. The file name is derived from the request name and never overwrites a file.
+func (cl *ConfigLoader) SaveImportedRequest(collection string, request *RequestJSON) (string, error) {
+	dir, err := cl.importCollectionDir(collection)
+	if err != nil {
+		return "", fmt.Errorf("SaveImportedRequest -> %v", err)
+	}
+
+	base := requestFileSlug(request.Name)
+	filePath := filepath.Join(dir, base+".json")
+	for i := 2; ; i++ {
+		exists, err := cl.fileManager.CheckIfPathExists(filePath)
+		if err != nil {
+			return "", fmt.Errorf("SaveImportedRequest -> %v", err)
+		}
+		if !exists {
+			break
+		}
+		filePath = filepath.Join(dir, fmt.Sprintf("%s-%d.json", base, i))
+	}
+
+	if err := cl.fileManager.SaveRequestJSON(filePath, request); err != nil {
+		return "", fmt.Errorf("SaveImportedRequest -> %v", err)
+	}
+	return filePath, nil
+}
+
+// importCollectionDir validates a collection name and creates its directory when needed
+func (cl *ConfigLoader) importCollectionDir(collection string) (string, error) {
+	if collection == "" || strings.ContainsAny(collection, `/\`) || collection == "." || collection == ".." {
+		return "", fmt.Errorf("invalid collection name %q", collection)
+	}
+	return cl.fileManager.CreateCollectionDir(collection)
+}
+
+// mergeCollectionVariables adds variables to a collection's _variables.json, keeping existing values
+func (cl *ConfigLoader) mergeCollectionVariables(collection string, variables map[string]string) error {
+	if len(variables) == 0 {
+		return nil
+	}
+
+	dir, err := cl.fileManager.CreateCollectionDir(collection)
+	if err != nil {
+		return fmt.Errorf("mergeCollectionVariables -> %v", err)
+	}
+	existing, err := cl.loadCollectionVariables(dir)
+	if err != nil {
+		return fmt.Errorf("mergeCollectionVariables -> %v", err)
+	}
+
+	merged := make(map[string]string, len(existing)+len(variables))
+	for name, value := range variables {
+		merged[name] = value
+	}
+	for name, value := range existing {
+		merged[name] = value
+	}
+
+	variablesPath, _, err := cl.collectionVariablesPath(dir)
+	if err != nil {
+		return fmt.Errorf("mergeCollectionVariables -> %v", err)
+	}
+	content, err := ToDocument(merged, IsYAMLFile(variablesPath))
+	if err != nil {
+		return fmt.Errorf("mergeCollectionVariables -> %v", err)
+	}
+	if current, err := cl.fileManager.ReadFileContent(variablesPath); err == nil {
+		// Keep the comments and order of the existing file
+		if content, err = UpdateDocumentContent(current, &merged, IsYAMLFile(variablesPath)); err != nil {
+			return fmt.Errorf("mergeCollectionVariables -> %v", err)
+		}
+	}
+	if err := cl.fileManager.WriteFileContent(variablesPath, strings.TrimSuffix(content, "\n")+"\n"); err != nil {
+		return fmt.Errorf("mergeCollectionVariables -> %v", err)
+	}
+	return nil
+}
+
+// importCollectionName makes a folder or tag name usable as a collection directory
+func importCollectionName(name, fallback string) string {
+	name = strings.TrimSpace(strings.NewReplacer("/", "-", `\`, "-").Replace(name))
+	if name == "" || name == "." || name == ".." {
+		return fallback
+	}
+	return name
+}
+
+// templateBaseURL replaces a configured base URL (default or any environment) at the start
+// of rawURL with {{baseUrl}}, so imported requests follow the active environment
+func templateBaseURL(rawURL string, config *ConfigJSON) string {
+	bases := []string{config.BaseUrl}
+	for _, env := range config.Environments {
+		bases = append(bases, env.BaseUrl)
+	}
+	// Longest first, so https://api.example.com/v2 wins over https://api.example.com
+	sort.Slice(bases, func(i, j int) bool { return len(bases[i]) > len(bases[j]) })
+
+	for _, base := range bases {
+		base = strings.TrimRight(base, "/")
+		if base == "" || !strings.HasPrefix(rawURL, base) {
+			continue
+		}
+		rest := rawURL[len(base):]
+		if rest == "" || strings.ContainsRune("/?#", rune(rest[0])) {
+			return "{{baseUrl}}" + rest
+		}
+	}
+	return rawURL
+}
+
+// defaultRequestName names an imported request after its method and path: "GET /users/1"
+func defaultRequestName(method, rawURL string) string {
+	path := rawURL
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+		path = parsed.EscapedPath()
+	} else {
+		path = strings.TrimPrefix(path, "{{baseUrl}}")
+		path, _, _ = strings.Cut(path, "?")
+	}
+	if path == "" {
+		path = "/"
+	}
+	return method + " " + path
+}
+
+// requestFileSlug turns a request name into a file name: "GET /users/{{id}}" -> "get-users-id",
+// "listPets" -> "list-pets"
+func requestFileSlug(name string) string {
+	var b strings.Builder
+	dash := false
+	lower := false
+	for _, c := range name {
+		if c >= 'A' && c <= 'Z' {
+			if lower && !dash {
+				b.WriteByte('-') // camelCase boundary
+			}
+			c += 'a' - 'A'
+			lower = false
+		} else {
+			lower = c >= 'a' && c <= 'z'
+		}
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		return "request"
+	}
+	return slug
+}
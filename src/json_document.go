@@ -0,0 +1,501 @@
+package src
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// JSONDocument edits a JSON file in place: values are replaced, added or removed where they are,
+// so key order, whitespace and everything that did not change stay byte-for-byte identical.
+type JSONDocument struct {
+	content string
+	root    *jsonNode
+}
+
+// jsonNode is a value of the document with its position in the content
+type jsonNode struct {
+	kind    byte // '{' object, '[' array, 0 for strings, numbers, booleans and null
+	start   int  // Offset of the first byte of the value
+	end     int  // Offset after the last byte of the value
+	members []jsonMember
+	items   []*jsonNode
+}
+
+type jsonMember struct {
+	key      string
+	keyStart int // Offset of the opening quote of the key
+	value    *jsonNode
+}
+
+// ParseJSONDocument reads a JSON document, keeping the position of every value
+func ParseJSONDocument(content string) (*JSONDocument, error) {
+	parser := &jsonDocumentParser{content: content}
+	root, err := parser.parseDocument()
+	if err != nil {
+		return nil, fmt.Errorf("ParseJSONDocument -> %v", err)
+	}
+	return &JSONDocument{content: content, root: root}, nil
+}
+
+// UpdateJSONContent writes value into a JSON file's content, touching only what differs from the
+// data the content already holds (as decoded into the same type)
+func UpdateJSONContent[T any](content string, value *T) (string, error) {
+	previous, err := ParseJSONContent[T](content)
+	if err != nil {
+		return "", fmt.Errorf("UpdateJSONContent -> %v", err)
+	}
+	before, err := ToJSON(previous)
+	if err != nil {
+		return "", fmt.Errorf("UpdateJSONContent -> %v", err)
+	}
+	after, err := ToJSON(value)
+	if err != nil {
+		return "", fmt.Errorf("UpdateJSONContent -> %v", err)
+	}
+
+	document, err := ParseJSONDocument(content)
+	if err != nil {
+		return "", fmt.Errorf("UpdateJSONContent -> %v", err)
+	}
+	if err := document.Update(before, after); err != nil {
+		return "", fmt.Errorf("UpdateJSONContent -> %v", err)
+	}
+	return document.String(), nil
+}
+
+// String returns the content of the document with its edits
+func (d *JSONDocument) String() string {
+	return d.content
+}
+
+// Update applies the differences between two versions of the document's data, both written by
+// ToJSON: changed values are replaced, new keys appended to their object and missing keys removed.
+// Keys of the document that appear in neither version are left alone.
+func (d *JSONDocument) Update(before, after string) error {
+	beforeDoc, err := ParseJSONDocument(before)
+	if err != nil {
+		return fmt.Errorf("Update -> %v", err)
+	}
+	afterDoc, err := ParseJSONDocument(after)
+	if err != nil {
+		return fmt.Errorf("Update -> %v", err)
+	}
+	if err := d.update(nil, beforeDoc, beforeDoc.root, afterDoc, afterDoc.root); err != nil {
+		return fmt.Errorf("Update -> %v", err)
+	}
+	return nil
+}
+
+func (d *JSONDocument) update(path []string, beforeDoc *JSONDocument, before *jsonNode, afterDoc *JSONDocument, after *jsonNode) error {
+	if before.kind != '{' || after.kind != '{' {
+		return d.Set(path, afterDoc.text(after))
+	}
+
+	for i, member := range after.members {
+		memberPath := append(append([]string{}, path...), member.key)
+		previous := before.member(member.key)
+		// Keys the document does not have yet go after the key they follow in the new version
+		precedingKey := ""
+		if i > 0 {
+			precedingKey = after.members[i-1].key
+		}
+		switch {
+		case previous == nil:
+			if err := d.set(memberPath, afterDoc.text(member.value), precedingKey); err != nil {
+				return err
+			}
+		case jsonTextEqual(beforeDoc.text(previous), afterDoc.text(member.value)):
+			continue
+		default:
+			// Only descend into objects the document has, e.g. not a capture written as a string
+			if current := d.find(memberPath); current != nil && current.kind == '{' {
+				if err := d.update(memberPath, beforeDoc, previous, afterDoc, member.value); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := d.set(memberPath, afterDoc.text(member.value), precedingKey); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, member := range before.members {
+		if after.member(member.key) == nil {
+			if err := d.Delete(append(append([]string{}, path...), member.key)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Set replaces the value at a path of object keys with a JSON value, adding the key (and missing
+// parent objects) when it does not exist. The value is indented like the surrounding content.
+// A key spelled with another case ("URL" for "url") is the same key, as encoding/json reads it.
+func (d *JSONDocument) Set(path []string, value string) error {
+	return d.set(path, value, "")
+}
+
+// set is Set, adding a missing key after precedingKey when its object has it, last otherwise
+func (d *JSONDocument) set(path []string, value, precedingKey string) error {
+	if len(path) == 0 {
+		return d.replace(d.root, value)
+	}
+
+	parent := d.root
+	for i, key := range path {
+		if parent.kind != '{' {
+			return fmt.Errorf("%s is not an object", strings.Join(path[:i], "."))
+		}
+		child := parent.member(key)
+		if child == nil {
+			// Build the missing objects around the value: {"b": {"c": value}}
+			for j := len(path) - 1; j > i; j-- {
+				keyText, err := marshalJSONString(path[j])
+				if err != nil {
+					return err
+				}
+				value = "{" + keyText + ": " + value + "}"
+			}
+			if i < len(path)-1 {
+				precedingKey = ""
+			}
+			return d.insert(parent, key, value, precedingKey)
+		}
+		if i == len(path)-1 {
+			return d.replace(child, value)
+		}
+		parent = child
+	}
+	return nil
+}
+
+// Delete removes the key at a path of object keys, doing nothing when it does not exist.
+// Keys are matched like Set does: exactly, or ignoring case.
+func (d *JSONDocument) Delete(path []string) error {
+	if len(path) == 0 {
+		return fmt.Errorf("cannot delete the document root")
+	}
+	parent := d.find(path[:len(path)-1])
+	if parent == nil || parent.kind != '{' {
+		return nil
+	}
+
+	i := parent.memberIndex(path[len(path)-1])
+	if i < 0 {
+		return nil
+	}
+	member := parent.members[i]
+	switch {
+	case i > 0: // From the end of the previous value: `, "key": value`
+		return d.splice(parent.members[i-1].value.end, member.value.end, "")
+	case len(parent.members) > 1: // Up to the next key: `"key": value, `
+		return d.splice(member.keyStart, parent.members[1].keyStart, "")
+	default: // The only key: {}
+		return d.splice(parent.start+1, parent.end-1, "")
+	}
+}
+
+func (d *JSONDocument) replace(node *jsonNode, value string) error {
+	multiline := strings.Contains(d.content[node.start:node.end], "\n")
+	return d.splice(node.start, node.end, d.format(value, d.lineIndent(node.start), multiline))
+}
+
+// insert adds a key to an object after precedingKey, or last, on its own line unless the object
+// is written on one line
+func (d *JSONDocument) insert(object *jsonNode, key, value, precedingKey string) error {
+	keyText, err := marshalJSONString(key)
+	if err != nil {
+		return err
+	}
+
+	objectText := d.content[object.start:object.end]
+	if len(object.members) == 0 {
+		if !strings.Contains(d.content, "\n") {
+			return d.splice(object.start, object.end, "{"+keyText+": "+d.format(value, "", false)+"}")
+		}
+		indent := d.lineIndent(object.start)
+		memberIndent := indent + d.indentUnit()
+		return d.splice(object.start, object.end, "{\n"+memberIndent+keyText+": "+d.format(value, memberIndent, true)+"\n"+indent+"}")
+	}
+
+	last := object.members[len(object.members)-1]
+	if index := object.memberIndex(precedingKey); precedingKey != "" && index >= 0 {
+		last = object.members[index]
+	}
+	if !strings.Contains(objectText, "\n") {
+		return d.splice(last.value.end, last.value.end, ", "+keyText+": "+d.format(value, "", false))
+	}
+	indent := d.lineIndent(last.keyStart)
+	return d.splice(last.value.end, last.value.end, ",\n"+indent+keyText+": "+d.format(value, indent, true))
+}
+
+// format writes a value indented for a line starting with indent, or on one line
+func (d *JSONDocument) format(value, indent string, multiline bool) string {
+	var buffer bytes.Buffer
+	if multiline {
+		if json.Indent(&buffer, []byte(value), indent, d.indentUnit()) == nil {
+			return buffer.String()
+		}
+		return value
+	}
+	if json.Compact(&buffer, []byte(value)) == nil {
+		return buffer.String()
+	}
+	return value
+}
+
+// splice replaces content[start:end] and parses the document again, so positions stay valid
+func (d *JSONDocument) splice(start, end int, text string) error {
+	content := d.content[:start] + text + d.content[end:]
+	parsed, err := ParseJSONDocument(content)
+	if err != nil {
+		return err
+	}
+	*d = *parsed
+	return nil
+}
+
+// find returns the value at a path of object keys, nil when it does not exist
+func (d *JSONDocument) find(path []string) *jsonNode {
+	node := d.root
+	for _, key := range path {
+		if node.kind != '{' {
+			return nil
+		}
+		if node = node.member(key); node == nil {
+			return nil
+		}
+	}
+	return node
+}
+
+func (d *JSONDocument) text(node *jsonNode) string {
+	return d.content[node.start:node.end]
+}
+
+// lineIndent returns the whitespace at the start of the line holding offset
+func (d *JSONDocument) lineIndent(offset int) string {
+	lineStart := strings.LastIndex(d.content[:offset], "\n") + 1
+	line := d.content[lineStart:offset]
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// indentUnit guesses the indentation of the document from its first indented key
+func (d *JSONDocument) indentUnit() string {
+	if d.root.kind == '{' && len(d.root.members) > 0 {
+		if indent := d.lineIndent(d.root.members[0].keyStart); indent != "" && d.lineIndent(d.root.start) == "" {
+			return indent
+		}
+	}
+	return "  "
+}
+
+func (n *jsonNode) member(key string) *jsonNode {
+	if index := n.memberIndex(key); index >= 0 {
+		return n.members[index].value
+	}
+	return nil
+}
+
+// memberIndex finds the key of an object, exactly or ignoring case like JSON decoding does
+func (n *jsonNode) memberIndex(key string) int {
+	for i, member := range n.members {
+		if member.key == key {
+			return i
+		}
+	}
+	for i, member := range n.members {
+		if strings.EqualFold(member.key, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+func jsonTextEqual(a, b string) bool {
+	var valueA, valueB interface{}
+	if json.Unmarshal([]byte(a), &valueA) != nil || json.Unmarshal([]byte(b), &valueB) != nil {
+		return a == b
+	}
+	return reflect.DeepEqual(valueA, valueB)
+}
+
+func marshalJSONString(value string) (string, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+// jsonDocumentParser is a small recursive descent parser recording where values are.
+// Scalars are validated by encoding/json.
+type jsonDocumentParser struct {
+	content string
+	pos     int
+}
+
+func (p *jsonDocumentParser) parseDocument() (*jsonNode, error) {
+	node, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.content) {
+		return nil, p.errorf("unexpected %q after the document", p.content[p.pos])
+	}
+	return node, nil
+}
+
+func (p *jsonDocumentParser) parseValue() (*jsonNode, error) {
+	p.skipSpace()
+	if p.pos >= len(p.content) {
+		return nil, p.errorf("unexpected end of document")
+	}
+
+	switch p.content[p.pos] {
+	case '{':
+		return p.parseObject()
+	case '[':
+		return p.parseArray()
+	case '"':
+		start := p.pos
+		if err := p.skipString(); err != nil {
+			return nil, err
+		}
+		return &jsonNode{start: start, end: p.pos}, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.content) && !strings.ContainsRune(" \t\r\n,]}", rune(p.content[p.pos])) {
+		p.pos++
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(p.content[start:p.pos]), &value); err != nil {
+		p.pos = start
+		return nil, p.errorf("invalid value %q", p.content[start:min(start+20, len(p.content))])
+	}
+	return &jsonNode{start: start, end: p.pos}, nil
+}
+
+func (p *jsonDocumentParser) parseObject() (*jsonNode, error) {
+	node := &jsonNode{kind: '{', start: p.pos}
+	p.pos++
+	p.skipSpace()
+	if p.peek() == '}' {
+		p.pos++
+		node.end = p.pos
+		return node, nil
+	}
+
+	for {
+		p.skipSpace()
+		if p.peek() != '"' {
+			return nil, p.errorf("expected a key")
+		}
+		keyStart := p.pos
+		if err := p.skipString(); err != nil {
+			return nil, err
+		}
+		var key string
+		if err := json.Unmarshal([]byte(p.content[keyStart:p.pos]), &key); err != nil {
+			return nil, p.errorf("invalid key: %v", err)
+		}
+
+		p.skipSpace()
+		if p.peek() != ':' {
+			return nil, p.errorf("expected ':' after key %q", key)
+		}
+		p.pos++
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		node.members = append(node.members, jsonMember{key: key, keyStart: keyStart, value: value})
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			node.end = p.pos
+			return node, nil
+		default:
+			return nil, p.errorf("expected ',' or '}' in object")
+		}
+	}
+}
+
+func (p *jsonDocumentParser) parseArray() (*jsonNode, error) {
+	node := &jsonNode{kind: '[', start: p.pos}
+	p.pos++
+	p.skipSpace()
+	if p.peek() == ']' {
+		p.pos++
+		node.end = p.pos
+		return node, nil
+	}
+
+	for {
+		item, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		node.items = append(node.items, item)
+
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			node.end = p.pos
+			return node, nil
+		default:
+			return nil, p.errorf("expected ',' or ']' in array")
+		}
+	}
+}
+
+// skipString moves past a string, from its opening quote to its closing quote
+func (p *jsonDocumentParser) skipString() error {
+	start := p.pos
+	for p.pos++; p.pos < len(p.content); p.pos++ {
+		switch p.content[p.pos] {
+		case '\\':
+			p.pos++
+		case '"':
+			p.pos++
+			return nil
+		}
+	}
+	p.pos = start
+	return p.errorf("unterminated string")
+}
+
+func (p *jsonDocumentParser) skipSpace() {
+	for p.pos < len(p.content) && strings.ContainsRune(" \t\r\n", rune(p.content[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *jsonDocumentParser) peek() byte {
+	if p.pos < len(p.content) {
+		return p.content[p.pos]
+	}
+	return 0
+}
+
+// errorf reports an error at the current position, as a line and column
+func (p *jsonDocumentParser) errorf(format string, args ...interface{}) error {
+	line, column := textPosition(p.content, p.pos)
+	return fmt.Errorf("line %d, column %d: %s", line, column, fmt.Sprintf(format, args...))
+}
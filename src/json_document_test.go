@@ -0,0 +1,185 @@
+package src
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUpdateJSONContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		edit    func(*RequestJSON)
+		want    string
+	}{
+		{
+			name:    "unchanged content is kept byte for byte",
+			content: "{\n    \"name\":   \"Get\",\n    \"method\": \"GET\",\n    \"url\": \"/a\"\n}\n",
+			edit:    func(r *RequestJSON) {},
+			want:    "{\n    \"name\":   \"Get\",\n    \"method\": \"GET\",\n    \"url\": \"/a\"\n}\n",
+		},
+		{
+			name:    "changed value is replaced in place",
+			content: "{\n  \"url\": \"/a\",\n  \"name\": \"Get\",\n  \"method\": \"GET\"\n}\n",
+			edit:    func(r *RequestJSON) { r.Name = "Get user" },
+			want:    "{\n  \"url\": \"/a\",\n  \"name\": \"Get user\",\n  \"method\": \"GET\"\n}\n",
+		},
+		{
+			name:    "20-digit integers in untouched values stay exact",
+			content: "{\n  \"name\": \"Get\",\n  \"method\": \"GET\",\n  \"url\": \"/a\",\n  \"body\": {\"id\": 12345678901234567890}\n}\n",
+			edit:    func(r *RequestJSON) { r.URL = "/b" },
+			want:    "{\n  \"name\": \"Get\",\n  \"method\": \"GET\",\n  \"url\": \"/b\",\n  \"body\": {\"id\": 12345678901234567890}\n}\n",
+		},
+		{
+			name:    "new key goes after the key it follows",
+			content: "{\n  \"name\": \"Get\",\n  \"method\": \"GET\",\n  \"url\": \"/a\",\n  \"skipAuth\": false\n}\n",
+			edit:    func(r *RequestJSON) { r.Headers = map[string]string{"X-Id": "1"} },
+			want:    "{\n  \"name\": \"Get\",\n  \"method\": \"GET\",\n  \"url\": \"/a\",\n  \"skipAuth\": false,\n  \"headers\": {\n    \"X-Id\": \"1\"\n  }\n}\n",
+		},
+		{
+			name:    "removed key is deleted with its separator",
+			content: "{\n  \"name\": \"Get\",\n  \"method\": \"GET\",\n  \"url\": \"/a\",\n  \"rawBody\": \"x\"\n}\n",
+			edit:    func(r *RequestJSON) { r.RawBody = "" },
+			want:    "{\n  \"name\": \"Get\",\n  \"method\": \"GET\",\n  \"url\": \"/a\"\n}\n",
+		},
+		{
+			name:    "keys spelled with another case are updated, not duplicated",
+			content: "{\n  \"Name\": \"Get\",\n  \"method\": \"GET\",\n  \"URL\": \"/a\",\n  \"Headers\": {\"A\": \"1\"}\n}\n",
+			edit:    func(r *RequestJSON) { r.URL = "/b"; r.Headers = nil },
+			want:    "{\n  \"Name\": \"Get\",\n  \"method\": \"GET\",\n  \"URL\": \"/b\"\n}\n",
+		},
+		{
+			name:    "one-line objects stay on one line",
+			content: `{"name": "Get", "method": "GET", "url": "/a", "headers": {"A": "1"}}`,
+			edit:    func(r *RequestJSON) { r.Headers["B"] = "2" },
+			want:    `{"name": "Get", "method": "GET", "url": "/a", "headers": {"A": "1", "B": "2"}}`,
+		},
+		{
+			name:    "tab indentation is reused",
+			content: "{\n\t\"name\": \"Get\",\n\t\"method\": \"GET\",\n\t\"url\": \"/a\"\n}",
+			edit:    func(r *RequestJSON) { r.Insecure = true },
+			want:    "{\n\t\"name\": \"Get\",\n\t\"method\": \"GET\",\n\t\"url\": \"/a\",\n\t\"insecure\": true\n}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := ParseJSONContent[RequestJSON](tt.content)
+			if err != nil {
+				t.Fatalf("ParseJSONContent: %v", err)
+			}
+			tt.edit(request)
+			got, err := UpdateJSONContent(tt.content, request)
+			if err != nil {
+				t.Fatalf("UpdateJSONContent: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONDocumentSetAndDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		apply   func(*JSONDocument) error
+		want    string
+	}{
+		{
+			name:    "set adds missing parent objects",
+			content: `{"a": 1}`,
+			apply:   func(d *JSONDocument) error { return d.Set([]string{"b", "c"}, `"x"`) },
+			want:    `{"a": 1, "b": {"c":"x"}}`,
+		},
+		{
+			name:    "set on a scalar parent fails",
+			content: `{"a": 1}`,
+			apply:   func(d *JSONDocument) error { return d.Set([]string{"a", "b"}, `2`) },
+			want:    "",
+		},
+		{
+			name:    "delete the first key",
+			content: `{"a": 1, "b": 2}`,
+			apply:   func(d *JSONDocument) error { return d.Delete([]string{"a"}) },
+			want:    `{"b": 2}`,
+		},
+		{
+			name:    "delete the only key",
+			content: `{"a": 1}`,
+			apply:   func(d *JSONDocument) error { return d.Delete([]string{"a"}) },
+			want:    `{}`,
+		},
+		{
+			name:    "exact keys win over keys spelled with another case",
+			content: `{"Url": 1, "url": 2}`,
+			apply:   func(d *JSONDocument) error { return d.Set([]string{"url"}, `3`) },
+			want:    `{"Url": 1, "url": 3}`,
+		},
+		{
+			name:    "delete a key spelled with another case",
+			content: `{"a": 1, "URL": 2}`,
+			apply:   func(d *JSONDocument) error { return d.Delete([]string{"url"}) },
+			want:    `{"a": 1}`,
+		},
+		{
+			name:    "delete a missing key does nothing",
+			content: `{"a": 1}`,
+			apply:   func(d *JSONDocument) error { return d.Delete([]string{"z", "y"}) },
+			want:    `{"a": 1}`,
+		},
+		{
+			name:    "keys with escapes are written as JSON strings",
+			content: `{}`,
+			apply:   func(d *JSONDocument) error { return d.Set([]string{`a"<b>`}, `1`) },
+			want:    `{"a\"<b>": 1}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := ParseJSONDocument(tt.content)
+			if err != nil {
+				t.Fatalf("ParseJSONDocument: %v", err)
+			}
+			err = tt.apply(document)
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", document.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := document.String(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONDocumentErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"trailing comma", `{"a": 1,}`},
+		{"unterminated string", `{"a": "b}`},
+		{"content after the value", `{"a": 1} x`},
+		{"invalid number", `{"a": 01}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseJSONDocument(tt.content)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if !strings.HasPrefix(err.Error(), "ParseJSONDocument -> ") {
+				t.Errorf("error %q does not name ParseJSONDocument", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+package src
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonPathSegment is one step of a parsed JSONPath expression
+type jsonPathSegment struct {
+	recursive bool   // ".." descendant search
+	wildcard  bool   // "*" or "[*]"
+	key       string // object member name
+	isIndex   bool
+	index     int
+	isSlice   bool
+	sliceFrom *int
+	sliceTo   *int
+}
+
+// EvaluateJSONPath returns every value matched by a JSONPath expression.
+// Supported syntax: $, .name, ['name'], [n], [-n], [*], .*, ..name and [from:to].
+func EvaluateJSONPath(data interface{}, path string) ([]interface{}, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	current := []interface{}{data}
+	for _, segment := range segments {
+		var next []interface{}
+		for _, value := range current {
+			if segment.recursive {
+				for _, descendant := range jsonDescendants(value) {
+					next = append(next, segment.apply(descendant)...)
+				}
+			} else {
+				next = append(next, segment.apply(value)...)
+			}
+		}
+		current = next
+	}
+
+	return current, nil
+}
+
+// QueryJSONPath returns the first value matched by a JSONPath expression
+func QueryJSONPath(data interface{}, path string) (interface{}, bool, error) {
+	values, err := EvaluateJSONPath(data, path)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(values) == 0 {
+		return nil, false, nil
+	}
+	return values[0], true, nil
+}
+
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("invalid JSONPath %q: must start with $", path)
+	}
+
+	var segments []jsonPathSegment
+	i := 1
+	for i < len(path) {
+		recursive := false
+
+		switch path[i] {
+		case '.':
+			i++
+			if i < len(path) && path[i] == '.' {
+				recursive = true
+				i++
+			}
+			if i < len(path) && path[i] == '[' {
+				segment, next, err := parseJSONPathBracket(path, i)
+				if err != nil {
+					return nil, err
+				}
+				segment.recursive = recursive
+				segments = append(segments, segment)
+				i = next
+				continue
+			}
+
+			start := i
+			for i < len(path) && path[i] != '.' && path[i] != '[' {
+				i++
+			}
+			name := path[start:i]
+			if name == "" {
+				return nil, fmt.Errorf("invalid JSONPath %q: empty member name at %d", path, start)
+			}
+			if name == "*" {
+				segments = append(segments, jsonPathSegment{recursive: recursive, wildcard: true})
+			} else {
+				segments = append(segments, jsonPathSegment{recursive: recursive, key: name})
+			}
+
+		case '[':
+			segment, next, err := parseJSONPathBracket(path, i)
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, segment)
+			i = next
+
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q at %d", path, path[i], i)
+		}
+	}
+
+	return segments, nil
+}
+
+// parseJSONPathBracket parses a [...] selector starting at path[start] == '['
+func parseJSONPathBracket(path string, start int) (jsonPathSegment, int, error) {
+	end := start + 1
+	inQuote := byte(0)
+	for end < len(path) {
+		c := path[end]
+		if inQuote != 0 {
+			if c == inQuote {
+				inQuote = 0
+			}
+		} else if c == '\'' || c == '"' {
+			inQuote = c
+		} else if c == ']' {
+			break
+		}
+		end++
+	}
+	if end >= len(path) {
+		return jsonPathSegment{}, 0, fmt.Errorf("invalid JSONPath %q: unclosed [ at %d", path, start)
+	}
+
+	content := strings.TrimSpace(path[start+1 : end])
+	next := end + 1
+
+	switch {
+	case content == "*":
+		return jsonPathSegment{wildcard: true}, next, nil
+
+	case len(content) >= 2 && (content[0] == '\'' || content[0] == '"') && content[len(content)-1] == content[0]:
+		return jsonPathSegment{key: content[1 : len(content)-1]}, next, nil
+
+	case strings.Contains(content, ":"):
+		parts := strings.SplitN(content, ":", 2)
+		segment := jsonPathSegment{isSlice: true}
+		for i, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return jsonPathSegment{}, 0, fmt.Errorf("invalid JSONPath %q: bad slice %q", path, content)
+			}
+			if i == 0 {
+				segment.sliceFrom = &n
+			} else {
+				segment.sliceTo = &n
+			}
+		}
+		return segment, next, nil
+
+	default:
+		n, err := strconv.Atoi(content)
+		if err != nil {
+			return jsonPathSegment{}, 0, fmt.Errorf("invalid JSONPath %q: bad selector [%s]", path, content)
+		}
+		return jsonPathSegment{isIndex: true, index: n}, next, nil
+	}
+}
+
+func (s jsonPathSegment) apply(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if s.wildcard {
+			keys := sortedKeys(v)
+			result := make([]interface{}, 0, len(keys))
+			for _, key := range keys {
+				result = append(result, v[key])
+			}
+			return result
+		}
+		if s.isIndex || s.isSlice {
+			return nil
+		}
+		if child, ok := v[s.key]; ok {
+			return []interface{}{child}
+		}
+		return nil
+
+	case []interface{}:
+		if s.wildcard {
+			return append([]interface{}{}, v...)
+		}
+		if s.isIndex {
+			index := s.index
+			if index < 0 {
+				index += len(v)
+			}
+			if index < 0 || index >= len(v) {
+				return nil
+			}
+			return []interface{}{v[index]}
+		}
+		if s.isSlice {
+			from, to := 0, len(v)
+			if s.sliceFrom != nil {
+				from = normalizeSliceBound(*s.sliceFrom, len(v))
+			}
+			if s.sliceTo != nil {
+				to = normalizeSliceBound(*s.sliceTo, len(v))
+			}
+			if from >= to {
+				return nil
+			}
+			return append([]interface{}{}, v[from:to]...)
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// ReplaceJSONPath returns a copy of data where every value matched by path is replaced
+func ReplaceJSONPath(data interface{}, path string, replacement interface{}) (interface{}, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) == 0 {
+		return replacement, nil
+	}
+	return replaceJSONPathSegments(data, segments, replacement), nil
+}
+
+func replaceJSONPathSegments(value interface{}, segments []jsonPathSegment, replacement interface{}) interface{} {
+	if len(segments) == 0 {
+		return replacement
+	}
+	segment, rest := segments[0], segments[1:]
+
+	if segment.recursive {
+		// Match at this level, then keep searching below
+		current := segment
+		current.recursive = false
+		value = replaceJSONPathSegments(value, append([]jsonPathSegment{current}, rest...), replacement)
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			copied := make(map[string]interface{}, len(v))
+			for key, child := range v {
+				copied[key] = replaceJSONPathSegments(child, segments, replacement)
+			}
+			return copied
+		case []interface{}:
+			copied := make([]interface{}, len(v))
+			for i, child := range v {
+				copied[i] = replaceJSONPathSegments(child, segments, replacement)
+			}
+			return copied
+		}
+		return value
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			if segment.wildcard || (!segment.isIndex && !segment.isSlice && key == segment.key) {
+				child = replaceJSONPathSegments(child, rest, replacement)
+			}
+			copied[key] = child
+		}
+		return copied
+
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, child := range v {
+			if segment.matchesIndex(i, len(v)) {
+				child = replaceJSONPathSegments(child, rest, replacement)
+			}
+			copied[i] = child
+		}
+		return copied
+	}
+
+	return value
+}
+
+// matchesIndex reports whether an array selector selects index i of an array of the given length
+func (s jsonPathSegment) matchesIndex(i, length int) bool {
+	switch {
+	case s.wildcard:
+		return true
+	case s.isIndex:
+		index := s.index
+		if index < 0 {
+			index += length
+		}
+		return index == i
+	case s.isSlice:
+		from, to := 0, length
+		if s.sliceFrom != nil {
+			from = normalizeSliceBound(*s.sliceFrom, length)
+		}
+		if s.sliceTo != nil {
+			to = normalizeSliceBound(*s.sliceTo, length)
+		}
+		return i >= from && i < to
+	}
+	return false
+}
+
+// isDefiniteJSONPath reports whether a parsed path can match at most one value
+func isDefiniteJSONPath(segments []jsonPathSegment) bool {
+	for _, segment := range segments {
+		if segment.recursive || segment.wildcard || segment.isSlice {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeSliceBound(n, length int) int {
+	if n < 0 {
+		n += length
+	}
+	if n < 0 {
+		return 0
+	}
+	if n > length {
+		return length
+	}
+	return n
+}
+
+// jsonDescendants returns a value and all of its nested values, depth first
+func jsonDescendants(value interface{}) []interface{} {
+	result := []interface{}{value}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			result = append(result, jsonDescendants(v[key])...)
+		}
+	case []interface{}:
+		for _, item := range v {
+			result = append(result, jsonDescendants(item)...)
+		}
+	}
+	return result
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// appendJSONPathKey extends a JSONPath with an object member, using the
+// bracket notation when the name is not a plain identifier
+func appendJSONPathKey(path, key string) string {
+	if isJSONPathIdentifier(key) {
+		return path + "." + key
+	}
+	if strings.Contains(key, "'") {
+		return path + `["` + key + `"]`
+	}
+	return path + "['" + key + "']"
+}
+
+func appendJSONPathIndex(path string, index int) string {
+	return path + "[" + strconv.Itoa(index) + "]"
+}
+
+func isJSONPathIdentifier(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, c := range key {
+		isLetter := c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+		isDigit := c >= '0' && c <= '9'
+		if !isLetter && !(isDigit && i > 0) {
+			return false
+		}
+	}
+	return true
+}
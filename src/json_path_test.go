@@ -0,0 +1,130 @@
+package src
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// jsonPathTestData is the document the JSONPath tests query
+const jsonPathTestData = `{
+  "data": {
+    "user": {"id": 42, "name": "Ada", "tags": ["a", "b", "c", "d"]},
+    "items": [{"id": 1, "name": "x"}, {"id": 2, "name": "y"}, {"id": 3}]
+  },
+  "odd key": true
+}`
+
+func decodeTestJSON(t *testing.T, content string) interface{} {
+	t.Helper()
+	var value interface{}
+	if err := json.Unmarshal([]byte(content), &value); err != nil {
+		t.Fatalf("invalid test JSON: %v", err)
+	}
+	return value
+}
+
+func TestEvaluateJSONPath(t *testing.T) {
+	data := decodeTestJSON(t, jsonPathTestData)
+
+	tests := []struct {
+		path string
+		want string // JSON array of the matched values
+	}{
+		{"$", ""}, // The whole document, checked separately
+		{"$.data.user.name", `["Ada"]`},
+		{"$['odd key']", `[true]`},
+		{`$["data"]["user"].id`, `[42]`},
+		{"$.data.user.tags[0]", `["a"]`},
+		{"$.data.user.tags[-1]", `["d"]`},
+		{"$.data.user.tags[-4]", `["a"]`},
+		{"$.data.user.tags[-5]", `[]`},
+		{"$.data.user.tags[4]", `[]`},
+		{"$.data.user.tags[1:3]", `["b", "c"]`},
+		{"$.data.user.tags[-2:]", `["c", "d"]`},
+		{"$.data.user.tags[:-3]", `["a"]`},
+		{"$.data.user.tags[-10:2]", `["a", "b"]`},
+		{"$.data.user.tags[3:1]", `[]`},
+		{"$.data.items[*].id", `[1, 2, 3]`},
+		{"$.data.items[*].name", `["x", "y"]`},
+		{"$.data.user.*", `[42, "Ada", ["a", "b", "c", "d"]]`},
+		{"$..name", `["x", "y", "Ada"]`}, // Depth first, object keys in sorted order
+		{"$.data..id", `[1, 2, 3, 42]`},
+		{"$.missing.deeper", `[]`},
+		{"$.data.user[0]", `[]`},
+		{"$.data.items.id", `[]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := EvaluateJSONPath(data, tt.path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.want == "" {
+				if len(got) != 1 || !reflect.DeepEqual(got[0], data) {
+					t.Errorf("got %v, want the whole document", got)
+				}
+				return
+			}
+			want := decodeTestJSON(t, tt.want).([]interface{})
+			if len(got) == 0 && len(want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestEvaluateJSONPathErrors(t *testing.T) {
+	for _, path := range []string{
+		"data.user",
+		"$.",
+		"$.data[",
+		"$.data[abc]",
+		"$.data[1:x]",
+		"$data",
+	} {
+		t.Run(path, func(t *testing.T) {
+			if _, err := EvaluateJSONPath(map[string]interface{}{}, path); err == nil {
+				t.Errorf("expected an error for %q", path)
+			}
+		})
+	}
+}
+
+func TestReplaceJSONPath(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		path string
+		want string
+	}{
+		{"member", `{"a": 1, "b": 2}`, "$.a", `{"a": "<ignored>", "b": 2}`},
+		{"every item", `{"items": [{"id": 1}, {"id": 2}]}`, "$.items[*].id", `{"items": [{"id": "<ignored>"}, {"id": "<ignored>"}]}`},
+		{"negative index", `[1, 2, 3]`, "$[-1]", `[1, 2, "<ignored>"]`},
+		{"negative slice", `[1, 2, 3]`, "$[-2:]", `[1, "<ignored>", "<ignored>"]`},
+		{"recursive", `{"a": {"updatedAt": 1}, "updatedAt": 2}`, "$..updatedAt", `{"a": {"updatedAt": "<ignored>"}, "updatedAt": "<ignored>"}`},
+		{"missing path leaves data alone", `{"a": 1}`, "$.b.c", `{"a": 1}`},
+		{"root", `{"a": 1}`, "$", `"<ignored>"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := decodeTestJSON(t, tt.data)
+			original := decodeTestJSON(t, tt.data)
+			got, err := ReplaceJSONPath(data, tt.path, snapshotIgnoredValue)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want := decodeTestJSON(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+			if !reflect.DeepEqual(data, original) {
+				t.Errorf("the input was modified: %v", data)
+			}
+		})
+	}
+}
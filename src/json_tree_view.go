@@ -0,0 +1,293 @@
+package src
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// jsonTreeNode is one value of a JSON document, with its JSONPath
+type jsonTreeNode struct {
+	label    string // Object key or array index, empty for the root
+	path     string
+	value    interface{}
+	children []*jsonTreeNode
+	parent   *jsonTreeNode
+	depth    int
+	expanded bool
+}
+
+func (n *jsonTreeNode) isContainer() bool {
+	switch n.value.(type) {
+	case map[string]interface{}, []interface{}:
+		return true
+	}
+	return false
+}
+
+func newJSONTree(label, path string, value interface{}, parent *jsonTreeNode, depth int) *jsonTreeNode {
+	node := &jsonTreeNode{
+		label:  label,
+		path:   path,
+		value:  value,
+		parent: parent,
+		depth:  depth,
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedKeys(v) {
+			node.children = append(node.children, newJSONTree(key, appendJSONPathKey(path, key), v[key], node, depth+1))
+		}
+	case []interface{}:
+		for i, item := range v {
+			node.children = append(node.children, newJSONTree(fmt.Sprintf("[%d]", i), appendJSONPathIndex(path, i), item, node, depth+1))
+		}
+	}
+	return node
+}
+
+// JSONTreeViewModel explores a JSON document as a collapsible tree
+type JSONTreeViewModel struct {
+	title   string
+	root    *jsonTreeNode
+	visible []*jsonTreeNode // Nodes shown with the current expansion, in display order
+	cursor  int
+	offset  int
+	width   int
+	height  int
+	styles  *Styles
+}
+
+func NewJSONTreeViewModel(title string, data interface{}) JSONTreeViewModel {
+	root := newJSONTree("", "$", data, nil, 0)
+	root.expanded = true
+	for _, child := range root.children {
+		child.expanded = len(child.children) > 0 && len(child.children) <= 10
+	}
+
+	m := JSONTreeViewModel{
+		title:  title,
+		root:   root,
+		width:  80,
+		height: 24,
+		styles: DefaultStyles(),
+	}
+	m.rebuild()
+	return m
+}
+
+func (m JSONTreeViewModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m JSONTreeViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.scrollToCursor()
+		return m, nil
+
+	case tea.KeyMsg:
+		node := m.visible[m.cursor]
+
+		switch msg.String() {
+		case "q", "esc":
+			return m, emit(navigateBackMsg{})
+		case "up", "k":
+			m.moveTo(m.cursor - 1)
+		case "down", "j":
+			m.moveTo(m.cursor + 1)
+		case "pgup", "b":
+			m.moveTo(m.cursor - m.pageHeight())
+		case "pgdown", "f":
+			m.moveTo(m.cursor + m.pageHeight())
+		case "g", "home":
+			m.moveTo(0)
+		case "G", "end":
+			m.moveTo(len(m.visible) - 1)
+		case "right", "l":
+			if node.isContainer() && !node.expanded {
+				node.expanded = true
+				m.rebuild()
+			} else if len(node.children) > 0 {
+				m.moveTo(m.cursor + 1)
+			}
+		case "left", "h":
+			if node.expanded && node != m.root {
+				node.expanded = false
+				m.rebuild()
+			} else if node.parent != nil {
+				m.moveTo(m.indexOf(node.parent))
+			}
+		case "enter", " ":
+			if node.isContainer() {
+				node.expanded = !node.expanded
+				m.rebuild()
+			}
+		case "E":
+			setJSONTreeExpanded(node, true)
+			m.rebuild()
+		case "C":
+			setJSONTreeExpanded(node, false)
+			node.expanded = node == m.root
+			m.rebuild()
+		case "y", "c":
+			return m, emit(copyToClipboardMsg{text: node.path, description: "JSONPath " + node.path})
+		case "v":
+			return m, emit(copyToClipboardMsg{text: compactJSON(node.value), description: "value of " + node.path})
+		}
+	}
+
+	return m, nil
+}
+
+func (m JSONTreeViewModel) View() string {
+	var b strings.Builder
+
+	b.WriteString("\n")
+	b.WriteString(m.styles.Text(fmt.Sprintf("  🌳 %s", m.title), m.styles.SelectedTitleColor))
+	b.WriteString("\n")
+	b.WriteString(m.styles.Text("  📍 "+m.visible[m.cursor].path, m.styles.AquamarineColor))
+	b.WriteString("\n\n")
+
+	end := min(m.offset+m.pageHeight(), len(m.visible))
+	for i := m.offset; i < end; i++ {
+		b.WriteString(m.renderNode(m.visible[i], i == m.cursor))
+		b.WriteString("\n")
+	}
+	for i := end - m.offset; i < m.pageHeight(); i++ {
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(m.styles.FooterStyle.Render("  ↑↓/jk move • →←/hl expand/collapse • E/C all • y copy path • v copy value • q/esc back"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (m JSONTreeViewModel) renderNode(node *jsonTreeNode, selected bool) string {
+	marker := "  "
+	if node.isContainer() {
+		marker = "▸ "
+		if node.expanded {
+			marker = "▾ "
+		}
+	}
+
+	label := node.label
+	if node == m.root {
+		label = "$"
+	}
+
+	var summary string
+	color := m.styles.MutedTitleColor
+	switch v := node.value.(type) {
+	case map[string]interface{}:
+		summary = pluralize(len(v), "key")
+		if !node.expanded {
+			summary, color = "{…} "+summary, m.styles.ThistleColor
+		}
+	case []interface{}:
+		summary = pluralize(len(v), "item")
+		if !node.expanded {
+			summary, color = "[…] "+summary, m.styles.ThistleColor
+		}
+	case string:
+		summary, color = compactJSON(v), m.styles.AquamarineColor
+	case float64:
+		summary, color = compactJSON(v), m.styles.PeachColor
+	case bool:
+		summary, color = compactJSON(v), m.styles.OrchidColor
+	default:
+		summary = "null"
+	}
+
+	indent := strings.Repeat("  ", node.depth)
+	labelColor := m.styles.TitleColor
+	if selected {
+		labelColor = m.styles.SelectedTitleColor
+	}
+	line := indent + m.styles.Text(marker+label+": ", labelColor) + m.styles.Text(summary, color)
+	line = lipgloss.NewStyle().MaxWidth(max(m.width-4, 20)).Render(line)
+
+	if selected {
+		return m.styles.Text("❯ ", m.styles.SelectedTitleColor) + line
+	}
+	return "  " + line
+}
+
+// pageHeight is the number of tree lines that fit between header and footer
+func (m JSONTreeViewModel) pageHeight() int {
+	return max(m.height-7, 3)
+}
+
+// rebuild lists the visible nodes again. The cursor stays on the selected node, or moves to its
+// nearest visible ancestor when the node was collapsed away.
+func (m *JSONTreeViewModel) rebuild() {
+	var selected *jsonTreeNode
+	if m.cursor >= 0 && m.cursor < len(m.visible) {
+		selected = m.visible[m.cursor]
+	}
+
+	m.visible = nil
+	var walk func(node *jsonTreeNode)
+	walk = func(node *jsonTreeNode) {
+		m.visible = append(m.visible, node)
+		if node.expanded {
+			for _, child := range node.children {
+				walk(child)
+			}
+		}
+	}
+	walk(m.root)
+
+	index := 0
+	for node := selected; node != nil; node = node.parent {
+		if i := m.indexOf(node); i >= 0 {
+			index = i
+			break
+		}
+	}
+	m.moveTo(index)
+}
+
+func (m *JSONTreeViewModel) moveTo(index int) {
+	m.cursor = max(0, min(index, len(m.visible)-1))
+	m.scrollToCursor()
+}
+
+func (m *JSONTreeViewModel) scrollToCursor() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	} else if m.cursor >= m.offset+m.pageHeight() {
+		m.offset = m.cursor - m.pageHeight() + 1
+	}
+	m.offset = max(0, min(m.offset, len(m.visible)-m.pageHeight()))
+}
+
+func (m JSONTreeViewModel) indexOf(node *jsonTreeNode) int {
+	for i, visible := range m.visible {
+		if visible == node {
+			return i
+		}
+	}
+	return -1
+}
+
+func setJSONTreeExpanded(node *jsonTreeNode, expanded bool) {
+	node.expanded = expanded && node.isContainer()
+	for _, child := range node.children {
+		setJSONTreeExpanded(child, expanded)
+	}
+}
+
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
@@ -1,22 +1,222 @@
 package src
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 func ParseJSONContent[T any](content string) (*T, error) {
 	var result T
 	if err := json.Unmarshal([]byte(content), &result); err != nil {
-		return nil, fmt.Errorf("ParseJSONContent -> %v", err)
+		return nil, fmt.Errorf("ParseJSONContent -> %w", newDocumentError(content, err))
 	}
 	return &result, nil
 }
 
+var yamlErrorLinePattern = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// DocumentError is an error of a JSON or YAML document, with the line and column it was found at
+// (0 when unknown). Use errors.As to get it out of wrapped errors.
+type DocumentError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *DocumentError) Error() string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+	case e.Line > 0:
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return e.Message
+}
+
+// newDocumentError locates a decoding error in content. Without content (documents converted
+// from YAML), only the message is kept.
+func newDocumentError(content string, err error) *DocumentError {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	documentErr := &DocumentError{Message: err.Error()}
+	offset := int64(-1)
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		// The offset is after the byte that could not be read
+		offset = syntaxErr.Offset - 1
+	case errors.As(err, &typeErr):
+		documentErr.Message = fmt.Sprintf("expected %s, got %s", jsonKindName(typeErr.Type), typeErr.Value)
+		if typeErr.Field != "" {
+			documentErr.Message = typeErr.Field + ": " + documentErr.Message
+		}
+		offset = typeErr.Offset - 1
+		// The offset is after the value, point at its start when the field can be found
+		if document, err := ParseJSONDocument(content); err == nil && typeErr.Field != "" {
+			if node := document.find(strings.Split(typeErr.Field, ".")); node != nil {
+				offset = int64(node.start)
+			}
+		}
+	default:
+		if match := yamlErrorLinePattern.FindStringSubmatch(err.Error()); match != nil {
+			documentErr.Line, _ = strconv.Atoi(match[1])
+			documentErr.Message = strings.TrimPrefix(err.Error(), match[0])
+		}
+	}
+
+	if content != "" && offset >= 0 {
+		documentErr.Line, documentErr.Column = textPosition(content, int(min(offset, int64(len(content)))))
+	}
+	return documentErr
+}
+
+// textPosition turns an offset into a 1-based line and column
+func textPosition(content string, offset int) (int, int) {
+	line := strings.Count(content[:offset], "\n") + 1
+	column := offset - strings.LastIndex(content[:offset], "\n")
+	return line, column
+}
+
+// jsonKindName names a Go type the way JSON documents call it
+func jsonKindName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8,
+		reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Pointer:
+		return jsonKindName(t.Elem())
+	}
+	return t.String()
+}
+
+// ToCompactJSON marshals a value on one line, without escaping &, < and >
+func ToCompactJSON(v interface{}) (string, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return "", fmt.Errorf("ToCompactJSON -> %v", err)
+	}
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+// ToJSON indents a value for files people read and edit, so &, < and > are not escaped
 func ToJSON(v interface{}) (string, error) {
-	jsonBytes, err := json.MarshalIndent(v, "", "  ")
-	if err != nil {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
 		return "", fmt.Errorf("ToJSON -> %v", err)
 	}
-	return string(jsonBytes), nil
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+// IsYAMLFile reports whether a config or request file is written in YAML
+func IsYAMLFile(filePath string) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// ParseDocumentContent parses a JSON or YAML document. YAML is converted to JSON first,
+// so both formats go through the same json tags and custom unmarshalers.
+func ParseDocumentContent[T any](content string, isYAML bool) (*T, error) {
+	if !isYAML {
+		return ParseJSONContent[T](content)
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &node); err != nil {
+		return nil, fmt.Errorf("ParseDocumentContent -> %w", newDocumentError(content, err))
+	}
+	keepYAMLTimestamps(&node)
+	var document interface{}
+	if err := node.Decode(&document); err != nil {
+		return nil, fmt.Errorf("ParseDocumentContent -> %v", err)
+	}
+	converted, err := json.Marshal(normalizeYAML(document))
+	if err != nil {
+		return nil, fmt.Errorf("ParseDocumentContent -> %v", err)
+	}
+	// Offsets in the converted JSON mean nothing in the YAML file
+	var result T
+	if err := json.Unmarshal(converted, &result); err != nil {
+		return nil, fmt.Errorf("ParseDocumentContent -> %w", newDocumentError("", err))
+	}
+	return &result, nil
+}
+
+// keepYAMLTimestamps reads unquoted dates as the strings they are written as, like JSON would
+func keepYAMLTimestamps(node *yaml.Node) {
+	if node.Kind == yaml.ScalarNode && node.ShortTag() == "!!timestamp" {
+		node.Tag = "!!str"
+	}
+	for _, child := range node.Content {
+		keepYAMLTimestamps(child)
+	}
+}
+
+// UpdateDocumentContent writes value into the content of an existing JSON or YAML file,
+// changing only what differs from what the file holds
+func UpdateDocumentContent[T any](content string, value *T, isYAML bool) (string, error) {
+	if isYAML {
+		return UpdateYAMLContent(content, value)
+	}
+	return UpdateJSONContent(content, value)
+}
+
+// ToDocument writes a value as indented JSON, or as YAML when isYAML is set
+func ToDocument(v interface{}, isYAML bool) (string, error) {
+	if isYAML {
+		return ToYAML(v)
+	}
+	return ToJSON(v)
+}
+
+// ToYAML writes a value as block-style YAML, with keys in the order ToJSON would write them
+func ToYAML(v interface{}) (string, error) {
+	content, err := ToJSON(v)
+	if err != nil {
+		return "", fmt.Errorf("ToYAML -> %v", err)
+	}
+
+	// JSON is valid YAML: decoding it into nodes keeps the key order of the JSON output
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &document); err != nil {
+		return "", fmt.Errorf("ToYAML -> %v", err)
+	}
+	clearYAMLStyle(&document)
+
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&document); err != nil {
+		return "", fmt.Errorf("ToYAML -> %v", err)
+	}
+	return buffer.String(), nil
+}
+
+// clearYAMLStyle turns the flow style and quoting of decoded JSON into the encoder's defaults
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
 }
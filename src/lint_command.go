@@ -0,0 +1,72 @@
+package src
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// lintCommand implements "postless lint [collection...]": it loads the project the way the TUI
+// does and lists request files that fail to load or have problems
+func (r *Runner) lintCommand(args []string) int {
+	fs := newCommandFlagSet("lint", "lint [collection...]")
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return ExitCodeError
+	}
+
+	collections, err := r.loadProject()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+	selected, err := selectCollections(collections, positional)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+
+	requestCount := 0
+	for _, collection := range selected {
+		requestCount += len(collection.Requests)
+	}
+	var diagnostics []Diagnostic
+	files := make(map[string]bool)
+	for _, diagnostic := range r.configLoader.Diagnostics() {
+		for _, collection := range selected {
+			// Selected folders only keep the problems of their own files
+			if strings.HasPrefix(diagnostic.FilePath, collection.Path+string(os.PathSeparator)) {
+				diagnostics = append(diagnostics, diagnostic)
+				files[diagnostic.FilePath] = true
+				break
+			}
+		}
+	}
+
+	styles := DefaultStyles()
+	fmt.Println()
+	if len(diagnostics) == 0 {
+		fmt.Println(styles.Text(fmt.Sprintf("  ✓ %s in %s, no problems found", pluralize(requestCount, "request"), pluralize(len(selected), "collection")), styles.AquamarineColor))
+		fmt.Println()
+		return ExitCodeOK
+	}
+
+	currentCollection := ""
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Collection != currentCollection {
+			currentCollection = diagnostic.Collection
+			fmt.Println(styles.Text("  "+currentCollection, styles.TitleColor))
+		}
+		icon, color := "⚠", styles.PeachColor
+		if diagnostic.Severity == DiagnosticError {
+			icon, color = "✗", styles.ErrorColor
+		}
+		fmt.Println(styles.Text(fmt.Sprintf("    %s %s: %s: %s", icon, diagnostic.Location(r.displayPath(diagnostic.FilePath)), diagnostic.Severity, diagnostic.Message), color))
+	}
+
+	errorCount, warningCount := CountDiagnostics(diagnostics)
+	fmt.Println()
+	fmt.Println(styles.Text(fmt.Sprintf("  %s (%s, %s) in %s", pluralize(len(diagnostics), "problem"), pluralize(errorCount, "error"), pluralize(warningCount, "warning"), pluralize(len(files), "file")), styles.ErrorColor))
+	fmt.Println()
+	return ExitCodeFailure
+}
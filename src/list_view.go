@@ -20,6 +20,7 @@ func (i ListItem) Description() string { return i.D }
 func (i ListItem) FilterValue() string { return i.T }
 
 type ListViewModel struct {
+	id       string // Set when embedded in the app, results are sent as listSelectedMsg
 	list     list.Model
 	selected string
 	endValue *ListItem
@@ -27,6 +28,12 @@ type ListViewModel struct {
 	styles   Styles
 }
 
+// NewEmbeddedListViewModel creates a list screen for the app navigation stack
+func NewEmbeddedListViewModel(id, title string, op []ListItem, height int) ListViewModel {
+	styles := DefaultStyles()
+	return ListViewModel{id: id, list: newListModel(title, op, height, styles), styles: *styles}
+}
+
 func (m ListViewModel) Init() tea.Cmd {
 	return nil
 }
@@ -38,6 +45,10 @@ func (m ListViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.endValue == nil {
+			return m.updateEmbedded(msg)
+		}
+
 		switch keypress := msg.String(); keypress {
 
 		case "enter":
@@ -64,6 +75,24 @@ func (m ListViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m ListViewModel) updateEmbedded(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.list.FilterState() != list.Filtering {
+		switch msg.String() {
+		case "enter":
+			if i, ok := m.list.SelectedItem().(ListItem); ok {
+				return m, emit(listSelectedMsg{id: m.id, item: i})
+			}
+			return m, nil
+		case "esc", "q":
+			return m, emit(navigateBackMsg{})
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
 func (m ListViewModel) View() string {
 	if m.quitting {
 		return ""
@@ -72,14 +101,12 @@ func (m ListViewModel) View() string {
 	return m.list.View()
 }
 
-func ListView(title string, op []ListItem, height int, endValue *ListItem) {
+func newListModel(title string, op []ListItem, height int, styles *Styles) list.Model {
 	items := []list.Item{}
 	for _, o := range op {
 		items = append(items, o)
 	}
 
-	styles := DefaultStyles()
-
 	const defaultWidth = 20
 
 	delegate := list.NewDefaultDelegate()
@@ -97,6 +124,14 @@ func ListView(title string, op []ListItem, height int, endValue *ListItem) {
 	l.Styles.PaginationStyle = styles.PaginationStyle
 	l.Styles.HelpStyle = styles.HelpStyle
 
+	return l
+}
+
+func ListView(title string, op []ListItem, height int, endValue *ListItem) {
+	styles := DefaultStyles()
+
+	l := newListModel(title, op, height, styles)
+
 	m := ListViewModel{list: l, endValue: endValue, selected: "", styles: *styles}
 
 	if _, err := tea.NewProgram(m).Run(); err != nil {
@@ -1,9 +1,60 @@
 package src
 
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
+
 type ConfigJSON struct {
-	BaseUrl       string            `json:"baseUrl"`
-	Timeout       int               `json:"timeout,omitempty"` // Timeout in seconds (optional, default: 30)
-	GlobalHeaders map[string]string `json:"globalHeaders,omitempty"`
+	BaseUrl       string                     `json:"baseUrl"`
+	Timeout       int                        `json:"timeout,omitempty"` // Timeout in seconds (optional, default: 30)
+	GlobalHeaders map[string]string          `json:"globalHeaders,omitempty"`
+	Variables     map[string]string          `json:"variables,omitempty"`
+	Environments  map[string]EnvironmentJSON `json:"environments,omitempty"`
+	HistoryLimit  int                        `json:"historyLimit,omitempty"` // Executions kept in history.jsonl (default: 500, negative disables history)
+
+	// HistoryIncludeSecrets keeps Authorization and Cookie values in history.jsonl instead of redacting them
+	HistoryIncludeSecrets bool `json:"historyIncludeSecrets,omitempty"`
+
+	// JSONPaths ignored by every snapshot, e.g. "$..updatedAt"
+	SnapshotIgnore []string `json:"snapshotIgnore,omitempty"`
+
+	// ActiveEnvironment is loaded from state.json, never written to config.json
+	ActiveEnvironment string `json:"-"`
+}
+
+// DefaultEnvironment names the top-level baseUrl and variables, so no environment may use it
+const DefaultEnvironment = "default"
+
+type EnvironmentJSON struct {
+	BaseUrl   string            `json:"baseUrl,omitempty"`
+	Variables map[string]string `json:"variables,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+}
+
+// StateJSON holds local, per-project state that should not be committed
+type StateJSON struct {
+	ActiveEnvironment string            `json:"activeEnvironment,omitempty"`
+	Variables         map[string]string `json:"variables,omitempty"` // Persisted captures
+	Filters           map[string]string `json:"filters,omitempty"`   // Last response filter per request file
+}
+
+// HistoryEntryJSON is one execution recorded in history.jsonl
+type HistoryEntryJSON struct {
+	Timestamp    time.Time           `json:"timestamp"`
+	Environment  string              `json:"environment"`
+	RequestFile  string              `json:"requestFile"` // Relative to the requests directory
+	Name         string              `json:"name"`
+	Request      *ResolvedRequest    `json:"request,omitempty"` // Missing when variables could not be resolved
+	StatusCode   int                 `json:"statusCode,omitempty"`
+	Status       string              `json:"status,omitempty"`
+	Headers      map[string][]string `json:"headers,omitempty"`
+	Body         string              `json:"body,omitempty"`
+	BodyEncoding string              `json:"bodyEncoding,omitempty"` // "base64" for bodies that are not valid UTF-8
+	DurationMs   float64             `json:"durationMs"`
+	Error        string              `json:"error,omitempty"`
 }
 
 type SecretJSON struct {
@@ -11,25 +62,127 @@ type SecretJSON struct {
 }
 
 type RequestJSON struct {
-	Name     string            `json:"name"`
-	Method   string            `json:"method"`
-	URL      string            `json:"url"`
-	SkipAuth bool              `json:"skipAuth"`
-	Headers  map[string]string `json:"headers,omitempty"`
-	Body     interface{}       `json:"body,omitempty"`
+	Name       string                 `json:"name"`
+	Method     string                 `json:"method"`
+	URL        string                 `json:"url"`
+	SkipAuth   bool                   `json:"skipAuth"`
+	Headers    map[string]string      `json:"headers,omitempty"`
+	Body       interface{}            `json:"body,omitempty"`
+	RawBody    string                 `json:"rawBody,omitempty"`  // Sent as-is instead of a JSON body, e.g. form-urlencoded data
+	Form       map[string]string      `json:"form,omitempty"`     // multipart/form-data fields, "@path" uploads a file
+	Insecure   bool                   `json:"insecure,omitempty"` // Skip TLS certificate verification
+	Captures   map[string]CaptureJSON `json:"captures,omitempty"`
+	Assertions *AssertionsJSON        `json:"assertions,omitempty"`
+	Snapshot   *SnapshotJSON          `json:"snapshot,omitempty"`
+}
+
+// CaptureJSON extracts a value from a response into a variable.
+// From is a JSONPath into the body ("$.data.token") or a header ("header:X-Request-Id").
+// In request files it is either a plain string or {"from": "...", "persist": true}.
+type CaptureJSON struct {
+	From    string `json:"from"`
+	Persist bool   `json:"persist,omitempty"` // Save to state.json instead of keeping it for the session only
+}
+
+// AssertionsJSON describes what a correct response looks like
+type AssertionsJSON struct {
+	Status        int                 `json:"status,omitempty"`
+	Headers       map[string]string   `json:"headers,omitempty"`      // Exact header values
+	HeadersMatch  map[string]string   `json:"headersMatch,omitempty"` // Header values as regular expressions
+	JSON          []JSONAssertionJSON `json:"json,omitempty"`
+	MaxDurationMs int                 `json:"maxDurationMs,omitempty"`
+	BodyContains  []string            `json:"bodyContains,omitempty"`
+}
+
+type JSONAssertionJSON struct {
+	Path   string          `json:"path"`
+	Equals json.RawMessage `json:"equals,omitempty"`
+	Exists *bool           `json:"exists,omitempty"`
+	Type   string          `json:"type,omitempty"` // string, number, boolean, object, array or null
 }
 
 type Collection struct {
+	Name      string
+	Path      string
+	Variables map[string]string // Loaded from _variables.json, overrides config and environment variables
+	Requests  []RequestItem     // Every request of the collection, folders included, in tree order
+	Root      Folder            // The collection directory as a tree of folders
+}
+
+// Folder is a directory of a collection. Folders nest to any depth.
+type Folder struct {
 	Name     string
-	Path     string
-	Requests []RequestItem
+	Path     string // Relative to the collection, with forward slashes; "" for the collection itself
+	Folders  []Folder
+	Requests []RequestItem // Requests directly in the folder
+}
+
+// AllRequests lists the requests of the folder and of every folder below it, in tree order:
+// the folder's own requests first, then each subfolder in name order
+func (f *Folder) AllRequests() []RequestItem {
+	requests := append([]RequestItem{}, f.Requests...)
+	for i := range f.Folders {
+		requests = append(requests, f.Folders[i].AllRequests()...)
+	}
+	return requests
+}
+
+// Find returns the folder at a path relative to this one ("admin/roles"), nil when there is none
+func (f *Folder) Find(path string) *Folder {
+	folder := f
+	for _, name := range strings.Split(strings.Trim(path, "/"), "/") {
+		if name == "" {
+			continue
+		}
+		var next *Folder
+		for i := range folder.Folders {
+			if folder.Folders[i].Name == name {
+				next = &folder.Folders[i]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		folder = next
+	}
+	return folder
+}
+
+// add puts a request into the folder at its Folder path, creating the folders on the way
+func (f *Folder) add(item RequestItem) {
+	folder := f
+	for _, name := range strings.Split(item.Folder, "/") {
+		if name == "" {
+			continue
+		}
+		next := folder.Find(name)
+		if next == nil {
+			folder.Folders = append(folder.Folders, Folder{Name: name, Path: strings.TrimPrefix(folder.Path+"/"+name, "/")})
+			next = &folder.Folders[len(folder.Folders)-1]
+		}
+		folder = next
+	}
+	folder.Requests = append(folder.Requests, item)
 }
 
 type RequestItem struct {
-	Name     string
-	FileName string
-	FilePath string
-	Request  *RequestJSON
+	Name      string
+	FileName  string // Path of the file relative to the collection, "admin/list-users.json" in folders
+	FilePath  string
+	Folder    string            // Folder of the request relative to the collection ("admin/roles"), empty at the top
+	Section   string            // Request of an .http file holding several ones, empty for request files
+	Variables map[string]string // Variables scoped to this request (collection and .http file variables)
+	Request   *RequestJSON
+}
+
+// RequestPath identifies the request in history, state and snapshots: its file path,
+// followed by #section for requests of .http files
+func (item *RequestItem) RequestPath() string {
+	if item.Section == "" {
+		return item.FilePath
+	}
+	return item.FilePath + "#" + item.Section
 }
 
 func GetDefaultConfigJSON() *ConfigJSON {
@@ -42,6 +195,10 @@ func GetDefaultConfigJSON() *ConfigJSON {
 	}
 }
 
+func GetDefaultStateJSON() *StateJSON {
+	return &StateJSON{}
+}
+
 func GetDefaultSecretJSON() *SecretJSON {
 	return &SecretJSON{
 		JWT: "",
@@ -55,3 +212,36 @@ func (c *ConfigJSON) GetTimeout() int {
 	}
 	return c.Timeout
 }
+
+// GetHistoryLimit returns the number of executions kept in history, 0 when history is disabled
+func (c *ConfigJSON) GetHistoryLimit() int {
+	if c.HistoryLimit < 0 {
+		return 0
+	}
+	if c.HistoryLimit == 0 {
+		return 500 // Default: 500 executions
+	}
+	return c.HistoryLimit
+}
+
+// GetActiveEnvironment returns the selected environment or nil when none is active
+func (c *ConfigJSON) GetActiveEnvironment() *EnvironmentJSON {
+	if c.ActiveEnvironment == "" {
+		return nil
+	}
+	env, ok := c.Environments[c.ActiveEnvironment]
+	if !ok {
+		return nil
+	}
+	return &env
+}
+
+// GetEnvironmentNames returns the configured environment names sorted alphabetically
+func (c *ConfigJSON) GetEnvironmentNames() []string {
+	names := make([]string, 0, len(c.Environments))
+	for name := range c.Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,105 @@
+package src
+
+import (
+	"reflect"
+	"testing"
+)
+
+// folderTestTree builds a collection tree from the folders of its requests, in the order given
+func folderTestTree(folders ...string) Folder {
+	var root Folder
+	for i, folder := range folders {
+		root.add(RequestItem{Name: string(rune('a' + i)), Folder: folder})
+	}
+	return root
+}
+
+// folderTestNames lists the requests of a folder tree in tree order
+func folderTestNames(folder *Folder) []string {
+	var names []string
+	for _, item := range folder.AllRequests() {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+func TestFolderAdd(t *testing.T) {
+	root := folderTestTree("", "admin/roles", "admin", "billing", "admin/roles", "")
+
+	type folderSummary struct {
+		Path     string
+		Requests []string
+		Folders  []string
+	}
+	summarize := func(folder *Folder) folderSummary {
+		summary := folderSummary{Path: folder.Path}
+		for _, item := range folder.Requests {
+			summary.Requests = append(summary.Requests, item.Name)
+		}
+		for _, child := range folder.Folders {
+			summary.Folders = append(summary.Folders, child.Name)
+		}
+		return summary
+	}
+
+	tests := []struct {
+		path string
+		want folderSummary
+	}{
+		{path: "", want: folderSummary{Requests: []string{"a", "f"}, Folders: []string{"admin", "billing"}}},
+		{path: "admin", want: folderSummary{Path: "admin", Requests: []string{"c"}, Folders: []string{"roles"}}},
+		{path: "admin/roles", want: folderSummary{Path: "admin/roles", Requests: []string{"b", "e"}}},
+		{path: "billing", want: folderSummary{Path: "billing", Requests: []string{"d"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			folder := root.Find(tt.path)
+			if folder == nil {
+				t.Fatalf("folder %q not found", tt.path)
+			}
+			if got := summarize(folder); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if got, want := folderTestNames(&root), []string{"a", "f", "c", "b", "e", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got tree order %q, want %q", got, want)
+	}
+}
+
+func TestFolderFind(t *testing.T) {
+	root := folderTestTree("admin/roles", "admin/users", "reports")
+
+	tests := []struct {
+		path string
+		want string // Path of the folder found, "-" when there is none
+	}{
+		{path: "", want: ""},
+		{path: "/", want: ""},
+		{path: "admin", want: "admin"},
+		{path: "admin/roles", want: "admin/roles"},
+		{path: "/admin/roles/", want: "admin/roles"},
+		{path: "admin//users", want: "admin/users"},
+		{path: "roles", want: "-"},
+		{path: "Admin", want: "-"},
+		{path: "admin/roles/extra", want: "-"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got := "-"
+			if folder := root.Find(tt.path); folder != nil {
+				got = folder.Path
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got, want := folderTestNames(root.Find("admin")), []string{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got requests %q below admin, want %q", got, want)
+	}
+}
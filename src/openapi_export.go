@@ -0,0 +1,281 @@
+package src
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OpenAPI 3 document written by ExportOpenAPI. Structs keep the usual key order in the output.
+type openAPIDocumentJSON struct {
+	OpenAPI    string                                      `json:"openapi" yaml:"openapi"`
+	Info       openAPIInfoJSON                             `json:"info" yaml:"info"`
+	Servers    []openAPIServerJSON                         `json:"servers,omitempty" yaml:"servers,omitempty"`
+	Security   []map[string][]string                       `json:"security,omitempty" yaml:"security,omitempty"`
+	Tags       []openAPITagJSON                            `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Paths      map[string]map[string]*openAPIOperationJSON `json:"paths" yaml:"paths"`
+	Components *openAPIComponentsJSON                      `json:"components,omitempty" yaml:"components,omitempty"`
+}
+
+type openAPIInfoJSON struct {
+	Title   string `json:"title" yaml:"title"`
+	Version string `json:"version" yaml:"version"`
+}
+
+type openAPIServerJSON struct {
+	URL         string `json:"url" yaml:"url"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+type openAPITagJSON struct {
+	Name string `json:"name" yaml:"name"`
+}
+
+type openAPIOperationJSON struct {
+	Tags        []string                       `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Summary     string                         `json:"summary" yaml:"summary"`
+	OperationID string                         `json:"operationId" yaml:"operationId"`
+	Parameters  []openAPIParameterJSON         `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	RequestBody *openAPIRequestBodyJSON        `json:"requestBody,omitempty" yaml:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponseJSON `json:"responses" yaml:"responses"`
+	Security    *[]map[string][]string         `json:"security,omitempty" yaml:"security,omitempty"` // [] for requests sent without the JWT
+}
+
+type openAPIParameterJSON struct {
+	Name     string                 `json:"name" yaml:"name"`
+	In       string                 `json:"in" yaml:"in"`
+	Required bool                   `json:"required,omitempty" yaml:"required,omitempty"`
+	Schema   map[string]interface{} `json:"schema" yaml:"schema"`
+	Example  interface{}            `json:"example,omitempty" yaml:"example,omitempty"`
+}
+
+type openAPIRequestBodyJSON struct {
+	Content map[string]openAPIMediaJSON `json:"content" yaml:"content"`
+}
+
+type openAPIMediaJSON struct {
+	Schema  map[string]interface{} `json:"schema,omitempty" yaml:"schema,omitempty"`
+	Example interface{}            `json:"example,omitempty" yaml:"example,omitempty"`
+}
+
+type openAPIResponseJSON struct {
+	Description string                      `json:"description" yaml:"description"`
+	Content     map[string]openAPIMediaJSON `json:"content,omitempty" yaml:"content,omitempty"`
+}
+
+type openAPIComponentsJSON struct {
+	SecuritySchemes map[string]map[string]string `json:"securitySchemes" yaml:"securitySchemes"`
+}
+
+// ExportOpenAPI builds an OpenAPI 3 skeleton from the documentation model: one operation per
+// request, tagged with its collection. Schemas are inferred from example bodies and sample
+// responses, so they describe what was observed rather than every allowed value.
+// It returns YAML when asYAML is set, JSON otherwise, plus warnings for requests left out.
+func ExportOpenAPI(docs APIDocs, asYAML bool) (string, []string, error) {
+	document := openAPIDocumentJSON{
+		OpenAPI: "3.0.3",
+		Info:    openAPIInfoJSON{Title: docs.Title, Version: "1.0.0"},
+		Paths:   make(map[string]map[string]*openAPIOperationJSON),
+	}
+	if docs.BaseURL != "" {
+		document.Servers = []openAPIServerJSON{{URL: strings.TrimRight(docs.BaseURL, "/")}}
+	}
+
+	var warnings []string
+	authenticated := false
+	for _, collection := range docs.Collections {
+		document.Tags = append(document.Tags, openAPITagJSON{Name: collection.Name})
+
+		for _, request := range collection.Requests {
+			path, query := openAPIPath(request.URL, docs.BaseURL)
+			method := strings.ToLower(request.Method)
+			if document.Paths[path] == nil {
+				document.Paths[path] = make(map[string]*openAPIOperationJSON)
+			}
+			if _, exists := document.Paths[path][method]; exists {
+				warnings = append(warnings, fmt.Sprintf("%s/%s: %s %s is already documented by another request", collection.Name, request.Name, request.Method, path))
+				continue
+			}
+
+			operation := openAPIOperation(request, path, query)
+			if request.Auth {
+				authenticated = true
+			} else {
+				operation.Security = &[]map[string][]string{}
+			}
+			document.Paths[path][method] = operation
+		}
+	}
+
+	if authenticated {
+		document.Security = []map[string][]string{{"bearerAuth": {}}}
+		document.Components = &openAPIComponentsJSON{SecuritySchemes: map[string]map[string]string{
+			"bearerAuth": {"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+		}}
+	}
+
+	if asYAML {
+		var output strings.Builder
+		encoder := yaml.NewEncoder(&output)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(document); err != nil {
+			return "", warnings, fmt.Errorf("ExportOpenAPI -> %v", err)
+		}
+		return output.String(), warnings, nil
+	}
+	output, err := ToJSON(document)
+	if err != nil {
+		return "", warnings, fmt.Errorf("ExportOpenAPI -> %v", err)
+	}
+	return output + "\n", warnings, nil
+}
+
+func openAPIOperation(request DocsRequest, path string, query url.Values) *openAPIOperationJSON {
+	operation := &openAPIOperationJSON{
+		Tags:        []string{request.Collection},
+		Summary:     request.Name,
+		OperationID: openAPIOperationID(request.Collection, strings.TrimSuffix(request.FileName, ".json")),
+		Responses:   make(map[string]openAPIResponseJSON),
+	}
+
+	for _, match := range openAPIPathParamPattern.FindAllStringSubmatch(path, -1) {
+		operation.Parameters = append(operation.Parameters, openAPIParameterJSON{
+			Name: match[1], In: "path", Required: true, Schema: map[string]interface{}{"type": "string"},
+		})
+	}
+	for _, name := range sortedQueryKeys(query) {
+		parameter := openAPIParameterJSON{Name: name, In: "query", Schema: map[string]interface{}{"type": "string"}}
+		if value := query.Get(name); !variablePattern.MatchString(value) {
+			parameter.Example = value
+		}
+		operation.Parameters = append(operation.Parameters, parameter)
+	}
+	for _, header := range request.Headers {
+		// Content-Type is described by the request body, Authorization by the security scheme
+		if strings.EqualFold(header[0], "Content-Type") || strings.EqualFold(header[0], "Authorization") {
+			continue
+		}
+		parameter := openAPIParameterJSON{Name: header[0], In: "header", Required: true, Schema: map[string]interface{}{"type": "string"}}
+		if !variablePattern.MatchString(header[1]) {
+			parameter.Example = header[1]
+		}
+		operation.Parameters = append(operation.Parameters, parameter)
+	}
+
+	if request.HasBody() {
+		media := openAPIMediaJSON{}
+		switch {
+		case len(request.Form) > 0:
+			properties := make(map[string]interface{}, len(request.Form))
+			for name, value := range request.Form {
+				property := map[string]interface{}{"type": "string"}
+				if strings.HasPrefix(value, "@") {
+					property["format"] = "binary"
+				}
+				properties[name] = property
+			}
+			media.Schema = map[string]interface{}{"type": "object", "properties": properties}
+		case request.Body != nil:
+			media.Schema = inferOpenAPISchema(request.Body)
+			media.Example = request.Body
+		default:
+			media.Schema = map[string]interface{}{"type": "string"}
+			media.Example = request.RawBody
+		}
+		contentType, _, _ := strings.Cut(request.ContentType, ";")
+		operation.RequestBody = &openAPIRequestBodyJSON{Content: map[string]openAPIMediaJSON{strings.TrimSpace(contentType): media}}
+	}
+
+	if request.Sample == nil {
+		operation.Responses["default"] = openAPIResponseJSON{Description: "No response recorded yet"}
+		return operation
+	}
+	response := openAPIResponseJSON{Description: strings.TrimSpace(strings.TrimPrefix(request.SampleStatus(), strconv.Itoa(request.Sample.StatusCode)))}
+	if response.Description == "" {
+		response.Description = "Recorded response"
+	}
+	if data, ok := request.SampleJSON(); ok {
+		contentType := request.SampleContentType()
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		response.Content = map[string]openAPIMediaJSON{contentType: {Schema: inferOpenAPISchema(data), Example: data}}
+	}
+	operation.Responses[strconv.Itoa(request.Sample.StatusCode)] = response
+	return operation
+}
+
+// openAPIPath turns a request URL into an OpenAPI path relative to the base URL, with
+// {{name}} variables as {name} path parameters, and returns its query parameters
+func openAPIPath(rawURL, baseURL string) (string, url.Values) {
+	path := rawURL
+	if base := strings.TrimRight(baseURL, "/"); base != "" && strings.HasPrefix(path, base) {
+		path = path[len(base):]
+	} else if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+		path = strings.TrimPrefix(rawURL, parsed.Scheme+"://"+parsed.Host)
+	}
+
+	path, rawQuery, _ := strings.Cut(path, "?")
+	path, _, _ = strings.Cut(path, "#")
+	query, _ := url.ParseQuery(rawQuery)
+
+	path = variablePattern.ReplaceAllString(path, "{$1}")
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path, query
+}
+
+// openAPIOperationID builds a camelCase operation id from the collection and file name
+func openAPIOperationID(collection, fileName string) string {
+	words := strings.Split(requestFileSlug(collection+" "+fileName), "-")
+	for i := 1; i < len(words); i++ {
+		if words[i] != "" {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// inferOpenAPISchema describes the shape of an example value
+func inferOpenAPISchema(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties := make(map[string]interface{}, len(v))
+		for name, item := range v {
+			properties[name] = inferOpenAPISchema(item)
+		}
+		return map[string]interface{}{"type": "object", "properties": properties}
+	case []interface{}:
+		schema := map[string]interface{}{"type": "array", "items": map[string]interface{}{}}
+		if len(v) > 0 {
+			schema["items"] = inferOpenAPISchema(v[0])
+		}
+		return schema
+	case string:
+		return map[string]interface{}{"type": "string"}
+	case float64:
+		if v == float64(int64(v)) {
+			return map[string]interface{}{"type": "integer"}
+		}
+		return map[string]interface{}{"type": "number"}
+	case bool:
+		return map[string]interface{}{"type": "boolean"}
+	case nil:
+		return map[string]interface{}{"nullable": true}
+	}
+	return map[string]interface{}{}
+}
+
+func sortedQueryKeys(query url.Values) []string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,745 @@
+package src
+
+import (
+	"fmt"
+	"hash/fnv"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// openAPIMethods are the operation keys of a path item, in the order requests are created
+var openAPIMethods = []string{"get", "post", "put", "patch", "delete", "head", "options", "trace"}
+
+var openAPIPathParamPattern = regexp.MustCompile(`\{([^{}/]+)\}`)
+
+// openAPIImport carries the state of one specification import
+type openAPIImport struct {
+	root      map[string]interface{}
+	config    *ConfigJSON
+	result    *ImportResult
+	variables map[string]map[string]string // Collection variables to add, per collection
+	warned    map[string]bool
+}
+
+// openAPIRequest is a request generated from one operation, before it is written
+type openAPIRequest struct {
+	collection string
+	operation  string // "GET /pets/{petId}", unique in the document
+	slug       string
+	request    *RequestJSON
+}
+
+// openAPIRequestExtensions are the request file formats that count as an existing operation
+var openAPIRequestExtensions = []string{".json", ".yaml", ".yml"}
+
+// ImportOpenAPI creates one collection per tag and one request per operation of an OpenAPI 3
+// document (JSON or YAML). Request files are named after the operation, so importing a newer
+// version with sync set only adds the operations that have no file yet (in any request format)
+// and never rewrites existing ones. Without sync, the import fails when any of its files already exists.
+func (cl *ConfigLoader) ImportOpenAPI(config *ConfigJSON, content string, sync bool) (*ImportResult, error) {
+	var document interface{}
+	if err := yaml.Unmarshal([]byte(content), &document); err != nil {
+		return nil, fmt.Errorf("ImportOpenAPI -> invalid JSON or YAML: %v", err)
+	}
+	root, ok := normalizeYAML(document).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("ImportOpenAPI -> not an OpenAPI document")
+	}
+	version, _ := root["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		if _, ok := root["swagger"]; ok {
+			return nil, fmt.Errorf("ImportOpenAPI -> Swagger 2.0 is not supported, convert the document to OpenAPI 3")
+		}
+		return nil, fmt.Errorf("ImportOpenAPI -> not an OpenAPI 3 document (missing \"openapi: 3.x\")")
+	}
+
+	imp := &openAPIImport{
+		root:      root,
+		config:    config,
+		result:    &ImportResult{},
+		variables: make(map[string]map[string]string),
+		warned:    make(map[string]bool),
+	}
+	requests := imp.requests()
+
+	var existing []string
+	existingFiles := make(map[string]string) // Request keys of the files found, by collection/slug
+	for _, planned := range requests {
+		requestKey, err := cl.existingRequestFile(planned.collection, planned.slug)
+		if err != nil {
+			return nil, fmt.Errorf("ImportOpenAPI -> %v", err)
+		}
+		if requestKey != "" {
+			existing = append(existing, requestKey)
+			existingFiles[planned.collection+"/"+planned.slug] = requestKey
+		}
+	}
+	if len(existing) > 0 && !sync {
+		listed := strings.Join(existing[:min(len(existing), 3)], ", ")
+		if len(existing) > 3 {
+			listed += ", ..."
+		}
+		verb := "exists"
+		if len(existing) > 1 {
+			verb = "exist"
+		}
+		return nil, fmt.Errorf("ImportOpenAPI -> %s already %s (%s), use --sync to add new operations without touching them", pluralize(len(existing), "request file"), verb, listed)
+	}
+
+	for _, planned := range requests {
+		collectionDir, err := cl.importCollectionDir(planned.collection)
+		if err != nil {
+			return imp.result, fmt.Errorf("ImportOpenAPI -> %v", err)
+		}
+		imported := ImportedRequest{Name: planned.request.Name, Collection: planned.collection}
+
+		if requestKey, ok := existingFiles[planned.collection+"/"+planned.slug]; ok {
+			imported.FilePath = cl.RequestFilePath(requestKey)
+			imp.result.Kept = append(imp.result.Kept, imported)
+			continue
+		}
+		imported.FilePath = filepath.Join(collectionDir, planned.slug+".json")
+		if err := cl.fileManager.SaveRequestJSON(imported.FilePath, planned.request); err != nil {
+			return imp.result, fmt.Errorf("ImportOpenAPI -> %v", err)
+		}
+		imp.result.Requests = append(imp.result.Requests, imported)
+	}
+
+	for _, name := range sortedCollectionNames(imp.variables) {
+		if err := cl.mergeCollectionVariables(name, imp.variables[name]); err != nil {
+			return imp.result, err
+		}
+	}
+	return imp.result, nil
+}
+
+// existingRequestFile returns the request key ("pets/list-pets.yaml") of the request file of a
+// collection named slug in any request format, "" when there is none
+func (cl *ConfigLoader) existingRequestFile(collection, slug string) (string, error) {
+	for _, extension := range openAPIRequestExtensions {
+		requestKey := collection + "/" + slug + extension
+		exists, err := cl.fileManager.CheckIfPathExists(cl.RequestFilePath(requestKey))
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return requestKey, nil
+		}
+	}
+	return "", nil
+}
+
+// requests generates the requests of every operation, sorted by path then method
+func (imp *openAPIImport) requests() []openAPIRequest {
+	info, _ := imp.root["info"].(map[string]interface{})
+	title, _ := info["title"].(string)
+	defaultCollection := importCollectionName(title, "openapi")
+
+	paths, _ := imp.root["paths"].(map[string]interface{})
+	var requests []openAPIRequest
+
+	for _, path := range sortedKeys(paths) {
+		pathItem := imp.resolve(paths[path])
+		if pathItem == nil {
+			continue
+		}
+		for _, method := range openAPIMethods {
+			operation, ok := pathItem[method].(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			collection := defaultCollection
+			if tags, ok := operation["tags"].([]interface{}); ok && len(tags) > 0 {
+				collection = importCollectionName(fmt.Sprint(tags[0]), defaultCollection)
+			}
+			if _, ok := imp.variables[collection]; !ok {
+				imp.variables[collection] = make(map[string]string)
+			}
+
+			label := strings.ToUpper(method) + " " + path
+			operationID, _ := operation["operationId"].(string)
+			slug := requestFileSlug(operationID)
+			if operationID == "" {
+				slug = requestFileSlug(label)
+			}
+
+			request := imp.request(collection, path, strings.ToUpper(method), pathItem, operation)
+			requests = append(requests, openAPIRequest{collection: collection, operation: label, slug: slug, request: request})
+		}
+	}
+	uniqueOpenAPISlugs(requests)
+	return requests
+}
+
+// uniqueOpenAPISlugs renames the files of operations whose names collide in a collection. Each
+// name depends on its operation alone, never on the order of the document, so a newer version of
+// the specification finds the same files: colliding operations are named after their method and
+// path, and those still colliding get a hash of it.
+func uniqueOpenAPISlugs(requests []openAPIRequest) {
+	collisions := func() map[string]int {
+		counts := make(map[string]int)
+		for _, planned := range requests {
+			counts[planned.collection+"/"+planned.slug]++
+		}
+		return counts
+	}
+
+	counts := collisions()
+	for i := range requests {
+		if counts[requests[i].collection+"/"+requests[i].slug] > 1 {
+			requests[i].slug = requestFileSlug(requests[i].operation)
+		}
+	}
+	counts = collisions()
+	for i := range requests {
+		if counts[requests[i].collection+"/"+requests[i].slug] > 1 {
+			hash := fnv.New32a()
+			hash.Write([]byte(requests[i].operation))
+			requests[i].slug = fmt.Sprintf("%s-%08x", requests[i].slug, hash.Sum32())
+		}
+	}
+}
+
+func (imp *openAPIImport) request(collection, path, method string, pathItem, operation map[string]interface{}) *RequestJSON {
+	label := method + " " + path
+	request := &RequestJSON{Method: method}
+	request.Name, _ = operation["summary"].(string)
+	if request.Name == "" {
+		request.Name, _ = operation["operationId"].(string)
+	}
+	if request.Name == "" {
+		request.Name = label
+	}
+
+	headers := make(map[string]string)
+	var query []string
+
+	for _, parameter := range imp.parameters(pathItem, operation) {
+		name, _ := parameter["name"].(string)
+		in, _ := parameter["in"].(string)
+		required, _ := parameter["required"].(bool)
+		example, hasExample := imp.parameterExample(parameter)
+
+		switch {
+		case in == "path":
+			if hasExample {
+				imp.setVariable(collection, label, name, example)
+			}
+		case in == "query" && required:
+			query = append(query, escapeFormPreservingVariables(name)+"={{"+name+"}}")
+			if hasExample {
+				imp.setVariable(collection, label, name, example)
+			}
+		case in == "header" && required:
+			// The specification ignores these as parameters, postless sets them itself
+			if strings.EqualFold(name, "Accept") || strings.EqualFold(name, "Content-Type") || strings.EqualFold(name, "Authorization") {
+				continue
+			}
+			headers[name] = "{{" + name + "}}"
+			if hasExample {
+				imp.setVariable(collection, label, name, example)
+			}
+		case in == "cookie" && required:
+			imp.warn(label, fmt.Sprintf("required cookie %s not converted", name))
+		}
+	}
+
+	request.URL = imp.serverURL(pathItem, operation) + openAPIPathParamPattern.ReplaceAllString(path, "{{$1}}")
+	if len(query) > 0 {
+		request.URL += "?" + strings.Join(query, "&")
+	}
+
+	imp.applySecurity(label, request, headers, operation)
+	imp.applyRequestBody(label, request, headers, operation)
+
+	if len(headers) > 0 {
+		request.Headers = headers
+	}
+	return request
+}
+
+// parameters merges path-level and operation-level parameters, the operation wins
+func (imp *openAPIImport) parameters(pathItem, operation map[string]interface{}) []map[string]interface{} {
+	var parameters []map[string]interface{}
+	index := make(map[string]int)
+	for _, source := range []map[string]interface{}{pathItem, operation} {
+		list, _ := source["parameters"].([]interface{})
+		for _, item := range list {
+			parameter := imp.resolve(item)
+			if parameter == nil {
+				continue
+			}
+			key := fmt.Sprint(parameter["in"], ":", parameter["name"])
+			if i, ok := index[key]; ok {
+				parameters[i] = parameter
+				continue
+			}
+			index[key] = len(parameters)
+			parameters = append(parameters, parameter)
+		}
+	}
+	return parameters
+}
+
+// parameterExample returns a parameter example as text, from its examples or its schema
+func (imp *openAPIImport) parameterExample(parameter map[string]interface{}) (string, bool) {
+	value, ok := imp.mediaExample(parameter)
+	if !ok || value == nil {
+		return "", false
+	}
+	if text, ok := value.(string); ok {
+		return text, true
+	}
+	return compactJSON(value), true
+}
+
+// mediaExample returns the example of a parameter or media type: example, the first of
+// examples, or a value generated from the schema
+func (imp *openAPIImport) mediaExample(node map[string]interface{}) (interface{}, bool) {
+	if example, ok := node["example"]; ok {
+		return example, true
+	}
+	if examples, ok := node["examples"].(map[string]interface{}); ok && len(examples) > 0 {
+		if example := imp.resolve(examples[sortedKeys(examples)[0]]); example != nil {
+			if value, ok := example["value"]; ok {
+				return value, true
+			}
+		}
+	}
+	if schema, ok := node["schema"]; ok {
+		value := imp.sample(schema, nil)
+		return value, value != nil
+	}
+	return nil, false
+}
+
+// serverURL returns the base of request URLs: the first server of the operation, path or
+// document, templated with {{baseUrl}} when it matches a configured base URL
+func (imp *openAPIImport) serverURL(pathItem, operation map[string]interface{}) string {
+	var servers []interface{}
+	for _, source := range []map[string]interface{}{operation, pathItem, imp.root} {
+		if list, ok := source["servers"].([]interface{}); ok && len(list) > 0 {
+			servers = list
+			break
+		}
+	}
+	if len(servers) == 0 {
+		return "{{baseUrl}}"
+	}
+
+	server, _ := servers[0].(map[string]interface{})
+	serverURL, _ := server["url"].(string)
+	variables, _ := server["variables"].(map[string]interface{})
+	serverURL = openAPIPathParamPattern.ReplaceAllStringFunc(serverURL, func(match string) string {
+		if variable, ok := variables[match[1:len(match)-1]].(map[string]interface{}); ok {
+			if value, ok := variable["default"]; ok {
+				return fmt.Sprint(value)
+			}
+		}
+		return match
+	})
+	serverURL = strings.TrimRight(serverURL, "/")
+
+	if !strings.Contains(serverURL, "://") {
+		return "{{baseUrl}}" + serverURL // Relative to wherever the document is served
+	}
+	templated := templateBaseURL(serverURL, imp.config)
+	if templated == serverURL && !imp.warned[serverURL] {
+		imp.warned[serverURL] = true
+		imp.result.Warnings = append(imp.result.Warnings, fmt.Sprintf("server %s does not match a configured baseUrl and is used as-is", serverURL))
+	}
+	return templated
+}
+
+// applySecurity maps the operation's security requirement: bearer and OAuth schemes use the
+// postless JWT, basic and API key schemes become headers, no requirement skips the JWT
+func (imp *openAPIImport) applySecurity(label string, request *RequestJSON, headers map[string]string, operation map[string]interface{}) {
+	security, ok := operation["security"].([]interface{})
+	if !ok {
+		security, ok = imp.root["security"].([]interface{})
+	}
+	if !ok {
+		return
+	}
+	if len(security) == 0 {
+		request.SkipAuth = true
+		return
+	}
+
+	requirement, _ := security[0].(map[string]interface{})
+	components, _ := imp.root["components"].(map[string]interface{})
+	schemes, _ := components["securitySchemes"].(map[string]interface{})
+	if len(requirement) == 0 {
+		request.SkipAuth = true // {} makes authentication optional
+		return
+	}
+
+	for _, name := range sortedKeys(requirement) {
+		scheme := imp.resolve(schemes[name])
+		if scheme == nil {
+			imp.warn(label, fmt.Sprintf("security scheme %s not found", name))
+			continue
+		}
+		schemeType, _ := scheme["type"].(string)
+		httpScheme, _ := scheme["scheme"].(string)
+
+		switch {
+		case schemeType == "http" && strings.EqualFold(httpScheme, "basic"):
+			headers["Authorization"] = "Basic {{" + name + "}}"
+			request.SkipAuth = true
+		case schemeType == "apiKey":
+			keyName, _ := scheme["name"].(string)
+			switch scheme["in"] {
+			case "header":
+				headers[keyName] = "{{" + name + "}}"
+			case "query":
+				separator := "?"
+				if strings.Contains(request.URL, "?") {
+					separator = "&"
+				}
+				request.URL += separator + escapeFormPreservingVariables(keyName) + "={{" + name + "}}"
+			default:
+				imp.warn(label, fmt.Sprintf("API key %s in %v not converted", name, scheme["in"]))
+			}
+		}
+	}
+}
+
+func (imp *openAPIImport) applyRequestBody(label string, request *RequestJSON, headers map[string]string, operation map[string]interface{}) {
+	requestBody := imp.resolve(operation["requestBody"])
+	if requestBody == nil {
+		return
+	}
+	content, _ := requestBody["content"].(map[string]interface{})
+	if len(content) == 0 {
+		return
+	}
+
+	media, mediaType := openAPIPreferredMedia(content)
+	example, ok := imp.mediaExample(media)
+	if !ok || example == nil {
+		imp.warn(label, fmt.Sprintf("no example or schema for the %s body", mediaType))
+		return
+	}
+
+	switch {
+	case strings.Contains(mediaType, "json"):
+		request.Body = example
+		if mediaType != "application/json" {
+			headers["Content-Type"] = mediaType
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		fields, ok := example.(map[string]interface{})
+		if !ok {
+			imp.warn(label, "form body example is not an object")
+			return
+		}
+		var pairs []string
+		for _, name := range sortedKeys(fields) {
+			pairs = append(pairs, escapeFormPreservingVariables(name)+"="+escapeFormPreservingVariables(formValueText(fields[name])))
+		}
+		request.RawBody = strings.Join(pairs, "&")
+		headers["Content-Type"] = mediaType
+	case mediaType == "multipart/form-data":
+		fields, ok := example.(map[string]interface{})
+		if !ok {
+			imp.warn(label, "form body example is not an object")
+			return
+		}
+		properties := imp.schemaProperties(media["schema"])
+		request.Form = make(map[string]string, len(fields))
+		for name, value := range fields {
+			if property := imp.resolve(properties[name]); property != nil && (property["format"] == "binary" || property["format"] == "base64") {
+				request.Form[name] = "@./" + name
+				imp.warn(label, fmt.Sprintf("set the path of the file uploaded as form field %s", name))
+				continue
+			}
+			request.Form[name] = formValueText(value)
+		}
+	default:
+		text, ok := example.(string)
+		if !ok {
+			imp.warn(label, fmt.Sprintf("%s body not converted", mediaType))
+			return
+		}
+		request.RawBody = text
+		headers["Content-Type"] = mediaType
+	}
+}
+
+// openAPIPreferredMedia picks the request body media type postless handles best: JSON first,
+// then forms, then the first declared one
+func openAPIPreferredMedia(content map[string]interface{}) (map[string]interface{}, string) {
+	mediaTypes := sortedKeys(content)
+	preferences := []func(string) bool{
+		func(t string) bool { return t == "application/json" },
+		func(t string) bool { return strings.HasSuffix(t, "+json") || strings.HasSuffix(t, "/json") },
+		func(t string) bool { return t == "application/x-www-form-urlencoded" },
+		func(t string) bool { return t == "multipart/form-data" },
+	}
+
+	mediaType := mediaTypes[0]
+	for _, preferred := range preferences {
+		if index := slices.IndexFunc(mediaTypes, preferred); index >= 0 {
+			mediaType = mediaTypes[index]
+			break
+		}
+	}
+	media, _ := content[mediaType].(map[string]interface{})
+	return media, mediaType
+}
+
+func formValueText(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	return compactJSON(value)
+}
+
+// schemaProperties returns the properties of an object schema, following $ref and allOf
+func (imp *openAPIImport) schemaProperties(node interface{}) map[string]interface{} {
+	schema := imp.resolve(node)
+	if schema == nil {
+		return nil
+	}
+	properties := make(map[string]interface{})
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, part := range allOf {
+			for name, property := range imp.schemaProperties(part) {
+				properties[name] = property
+			}
+		}
+	}
+	if own, ok := schema["properties"].(map[string]interface{}); ok {
+		for name, property := range own {
+			properties[name] = property
+		}
+	}
+	return properties
+}
+
+// sample generates an example value from a schema. refs holds the references being expanded,
+// so recursive schemas stop instead of looping.
+func (imp *openAPIImport) sample(node interface{}, refs []string) interface{} {
+	schemaMap, ok := node.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if ref, ok := schemaMap["$ref"].(string); ok {
+		for _, seen := range refs {
+			if seen == ref {
+				return nil
+			}
+		}
+		refs = append(refs, ref)
+	}
+	schema := imp.resolve(schemaMap)
+	if schema == nil {
+		return nil
+	}
+
+	if example, ok := schema["example"]; ok {
+		return example
+	}
+	if examples, ok := schema["examples"].([]interface{}); ok && len(examples) > 0 {
+		return examples[0]
+	}
+	if value, ok := schema["default"]; ok {
+		return value
+	}
+	if value, ok := schema["const"]; ok {
+		return value
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		merged := make(map[string]interface{})
+		for _, part := range allOf {
+			if object, ok := imp.sample(part, refs).(map[string]interface{}); ok {
+				for name, value := range object {
+					merged[name] = value
+				}
+			}
+		}
+		if properties, ok := imp.sample(withoutKey(schema, "allOf"), refs).(map[string]interface{}); ok {
+			for name, value := range properties {
+				merged[name] = value
+			}
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if alternatives, ok := schema[key].([]interface{}); ok && len(alternatives) > 0 {
+			return imp.sample(alternatives[0], refs)
+		}
+	}
+
+	schemaType := openAPISchemaType(schema)
+	switch schemaType {
+	case "object":
+		object := make(map[string]interface{})
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, property := range properties {
+			if resolved := imp.resolve(property); resolved != nil && resolved["readOnly"] == true {
+				continue
+			}
+			if value := imp.sample(property, refs); value != nil {
+				object[name] = value
+			}
+		}
+		return object
+	case "array":
+		if item := imp.sample(schema["items"], refs); item != nil {
+			return []interface{}{item}
+		}
+		return []interface{}{}
+	case "string":
+		return openAPIStringSample(schema)
+	case "integer", "number":
+		if minimum, ok := schema["minimum"]; ok {
+			return minimum
+		}
+		return 0
+	case "boolean":
+		return false
+	}
+	return nil
+}
+
+// openAPISchemaType returns the type of a schema, the first non-null one for 3.1 type lists
+func openAPISchemaType(schema map[string]interface{}) string {
+	switch schemaType := schema["type"].(type) {
+	case string:
+		return schemaType
+	case []interface{}:
+		for _, candidate := range schemaType {
+			if candidate != "null" {
+				return fmt.Sprint(candidate)
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	if _, ok := schema["items"]; ok {
+		return "array"
+	}
+	return ""
+}
+
+func openAPIStringSample(schema map[string]interface{}) string {
+	switch schema["format"] {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "time":
+		return "00:00:00"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "uri", "url":
+		return "https://example.com"
+	case "hostname":
+		return "example.com"
+	case "ipv4":
+		return "127.0.0.1"
+	case "ipv6":
+		return "::1"
+	case "password":
+		return "password"
+	case "byte":
+		return "c3RyaW5n"
+	}
+	return "string"
+}
+
+func withoutKey(m map[string]interface{}, key string) map[string]interface{} {
+	copied := make(map[string]interface{}, len(m))
+	for name, value := range m {
+		if name != key {
+			copied[name] = value
+		}
+	}
+	return copied
+}
+
+// resolve follows local $ref pointers ("#/components/schemas/User") to the object they name
+func (imp *openAPIImport) resolve(node interface{}) map[string]interface{} {
+	for depth := 0; depth < 32; depth++ {
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		ref, ok := object["$ref"].(string)
+		if !ok {
+			return object
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			if !imp.warned[ref] {
+				imp.warned[ref] = true
+				imp.result.Warnings = append(imp.result.Warnings, fmt.Sprintf("external reference %s not supported", ref))
+			}
+			return nil
+		}
+
+		node = imp.root
+		for _, segment := range strings.Split(ref[2:], "/") {
+			segment = strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+			parent, ok := node.(map[string]interface{})
+			if !ok {
+				node = nil
+				break
+			}
+			node = parent[segment]
+		}
+	}
+	return nil
+}
+
+func (imp *openAPIImport) setVariable(collection, label, name, value string) {
+	if existing, ok := imp.variables[collection][name]; ok && existing != value {
+		imp.warn(label, fmt.Sprintf("variable %s keeps the example %q of an earlier operation, not %q", name, existing, value))
+		return
+	}
+	imp.variables[collection][name] = value
+}
+
+func (imp *openAPIImport) warn(label, message string) {
+	imp.result.Warnings = append(imp.result.Warnings, label+": "+message)
+}
+
+// normalizeYAML converts decoded YAML to the shapes encoding/json produces: string keys
+// everywhere (YAML allows 200: as a key) and timestamps as text
+func normalizeYAML(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = normalizeYAML(item)
+		}
+		return v
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[fmt.Sprint(key)] = normalizeYAML(item)
+		}
+		return converted
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeYAML(item)
+		}
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339)
+	}
+	return value
+}
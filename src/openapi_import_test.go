@@ -0,0 +1,202 @@
+package src
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// openAPITestSpec is version 1 of the specification the sync tests import
+const openAPITestSpec = `openapi: 3.0.3
+info: {title: Pet Store}
+servers: [{url: "https://api.pets.test/v1"}]
+paths:
+  /pets:
+    get: {operationId: listPets, tags: [pets], summary: List pets}
+    post: {operationId: list_pets, tags: [pets]}
+  /pets/{petId}:
+    get:
+      tags: [pets]
+      parameters: [{name: petId, in: path, required: true, example: 7}]
+`
+
+// openAPITestSpecV2 lists the operations in another order and adds new ones, one of them reusing
+// the operationId of an existing operation
+const openAPITestSpecV2 = `openapi: 3.0.3
+info: {title: Pet Store}
+servers: [{url: "https://api.pets.test/v1"}]
+paths:
+  /owners:
+    get: {operationId: listPets, tags: [pets]}
+  /pets/{petId}:
+    get:
+      tags: [pets]
+      parameters: [{name: petId, in: path, required: true, example: 7}]
+  /pets:
+    post: {operationId: list_pets, tags: [pets]}
+    get: {operationId: listPets, tags: [pets], summary: List pets}
+  /health:
+    get: {operationId: health}
+`
+
+func TestImportOpenAPISync(t *testing.T) {
+	cl, fm := newTestConfigLoader(t)
+	config := &ConfigJSON{BaseUrl: "https://api.pets.test/v1"}
+
+	// Steps run in order against the same project
+	tests := []struct {
+		name    string
+		spec    string
+		sync    bool
+		prepare func(t *testing.T)
+		created []string // Request keys
+		kept    []string
+		wantErr string
+	}{
+		{
+			name:    "first import",
+			spec:    openAPITestSpec,
+			created: []string{"pets/get-pets.json", "pets/post-pets.json", "pets/get-pets-pet-id.json"},
+		},
+		{
+			name:    "existing files stop an import without sync",
+			spec:    openAPITestSpec,
+			wantErr: "ImportOpenAPI -> 3 request files already exist (pets/get-pets.json, pets/post-pets.json, pets/get-pets-pet-id.json), use --sync to add new operations without touching them",
+		},
+		{
+			name: "requests converted to YAML are recognized",
+			spec: openAPITestSpec,
+			sync: true,
+			prepare: func(t *testing.T) {
+				dir := filepath.Join(fm.RequestsDir, "pets")
+				if err := os.Rename(filepath.Join(dir, "get-pets-pet-id.json"), filepath.Join(dir, "get-pets-pet-id.yml")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			kept: []string{"pets/get-pets.json", "pets/post-pets.json", "pets/get-pets-pet-id.yml"},
+		},
+		{
+			name:    "a newer version only adds its new operations",
+			spec:    openAPITestSpecV2,
+			sync:    true,
+			created: []string{"Pet Store/health.json", "pets/get-owners.json"},
+			kept:    []string{"pets/get-pets.json", "pets/post-pets.json", "pets/get-pets-pet-id.yml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.prepare != nil {
+				tt.prepare(t)
+			}
+			result, err := cl.ImportOpenAPI(config, tt.spec, tt.sync)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			requestKeys := func(requests []ImportedRequest) []string {
+				var keys []string
+				for _, request := range requests {
+					key, err := filepath.Rel(fm.RequestsDir, request.FilePath)
+					if err != nil {
+						t.Fatal(err)
+					}
+					keys = append(keys, filepath.ToSlash(key))
+				}
+				return keys
+			}
+			if got := requestKeys(result.Requests); !reflect.DeepEqual(got, tt.created) {
+				t.Errorf("created %q, want %q", got, tt.created)
+			}
+			if got := requestKeys(result.Kept); !reflect.DeepEqual(got, tt.kept) {
+				t.Errorf("kept %q, want %q", got, tt.kept)
+			}
+		})
+	}
+
+	content, err := os.ReadFile(filepath.Join(fm.RequestsDir, "pets", "get-pets.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	request, err := ParseJSONContent[RequestJSON](string(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.Name != "List pets" || request.Method != "GET" || request.URL != "{{baseUrl}}/pets" {
+		t.Errorf("got request %q %s %s", request.Name, request.Method, request.URL)
+	}
+	variables, err := cl.loadCollectionVariables(filepath.Join(fm.RequestsDir, "pets"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if variables["petId"] != "7" {
+		t.Errorf("got collection variables %v", variables)
+	}
+}
+
+func TestUniqueOpenAPISlugs(t *testing.T) {
+	tests := []struct {
+		name     string
+		requests []openAPIRequest
+		want     []string
+	}{
+		{
+			name: "unique names are kept",
+			requests: []openAPIRequest{
+				{collection: "pets", operation: "GET /pets", slug: "list-pets"},
+				{collection: "owners", operation: "GET /owners", slug: "list-pets"},
+			},
+			want: []string{"list-pets", "list-pets"},
+		},
+		{
+			name: "colliding names use the method and path",
+			requests: []openAPIRequest{
+				{collection: "pets", operation: "GET /pets", slug: "list-pets"},
+				{collection: "pets", operation: "POST /pets", slug: "list-pets"},
+				{collection: "pets", operation: "GET /pets/{id}", slug: "get-pet"},
+			},
+			want: []string{"get-pets", "post-pets", "get-pet"},
+		},
+		{
+			name: "still colliding names get a hash of the operation",
+			requests: []openAPIRequest{
+				{collection: "pets", operation: "GET /pets/{id}", slug: "get-pets-id"},
+				{collection: "pets", operation: "GET /pets-id", slug: "get-pets-id"},
+			},
+			want: []string{"get-pets-id-8e9452ee", "get-pets-id-8af64de2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reversed := make([]openAPIRequest, len(tt.requests))
+			for i, request := range tt.requests {
+				reversed[len(reversed)-1-i] = request
+			}
+
+			uniqueOpenAPISlugs(tt.requests)
+			var got []string
+			for _, request := range tt.requests {
+				got = append(got, request.slug)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+
+			// Listing the operations in another order gives each one the same name
+			uniqueOpenAPISlugs(reversed)
+			for i, request := range reversed {
+				if request.slug != got[len(got)-1-i] {
+					t.Errorf("%s is named %q in reverse order, %q otherwise", request.operation, request.slug, got[len(got)-1-i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,628 @@
+package src
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Postman v2.1 collection format, only the parts postless can use
+type postmanCollection struct {
+	Info struct {
+		Name   string `json:"name"`
+		Schema string `json:"schema"`
+	} `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanKeyValue `json:"variable"`
+	Auth     *postmanAuth      `json:"auth"`
+	Event    []postmanEvent    `json:"event"`
+}
+
+// postmanItem is a folder when Item is set, a request otherwise
+type postmanItem struct {
+	Name    string          `json:"name"`
+	Item    []postmanItem   `json:"item"`
+	Request *postmanRequest `json:"request"`
+	Auth    *postmanAuth    `json:"auth"` // Folder authentication, inherited by its requests
+	Event   []postmanEvent  `json:"event"`
+}
+
+type postmanRequest struct {
+	Method string            `json:"method"`
+	URL    postmanURL        `json:"url"`
+	Header []postmanKeyValue `json:"header"`
+	Body   *postmanBody      `json:"body"`
+	Auth   *postmanAuth      `json:"auth"`
+}
+
+// postmanURL is either a plain string or an object with the raw URL and its parts
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Protocol string            `json:"protocol"`
+	Host     []string          `json:"host"`
+	Path     []string          `json:"path"`
+	Query    []postmanKeyValue `json:"query"`
+	Variable []postmanKeyValue `json:"variable"` // Values of :name path variables
+}
+
+func (u *postmanURL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		*u = postmanURL{Raw: raw}
+		return nil
+	}
+	type plain postmanURL
+	return json.Unmarshal(data, (*plain)(u))
+}
+
+type postmanKeyValue struct {
+	Key      string          `json:"key"`
+	Value    json.RawMessage `json:"value"` // Usually a string, collection variables may hold any JSON
+	Disabled bool            `json:"disabled"`
+	Enabled  *bool           `json:"enabled"` // Environment files use enabled instead of disabled
+	Type     string          `json:"type"`
+	Src      json.RawMessage `json:"src"` // Form file path, a string or a list of paths
+}
+
+// text returns the value as a string, JSON values other than strings as their JSON text
+func (kv postmanKeyValue) text() string {
+	if len(kv.Value) == 0 || string(kv.Value) == "null" {
+		return ""
+	}
+	var value string
+	if err := json.Unmarshal(kv.Value, &value); err == nil {
+		return value
+	}
+	return string(kv.Value)
+}
+
+func (kv postmanKeyValue) active() bool {
+	return !kv.Disabled && (kv.Enabled == nil || *kv.Enabled)
+}
+
+type postmanBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw"`
+	URLEncoded []postmanKeyValue `json:"urlencoded"`
+	FormData   []postmanKeyValue `json:"formdata"`
+	GraphQL    *struct {
+		Query     string `json:"query"`
+		Variables string `json:"variables"`
+	} `json:"graphql"`
+	Options struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+	Disabled bool `json:"disabled"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Bearer []postmanKeyValue `json:"bearer"`
+	Basic  []postmanKeyValue `json:"basic"`
+	APIKey []postmanKeyValue `json:"apikey"`
+}
+
+func (a *postmanAuth) param(params []postmanKeyValue, key string) string {
+	for _, param := range params {
+		if param.Key == key {
+			return param.text()
+		}
+	}
+	return ""
+}
+
+type postmanEvent struct {
+	Listen string `json:"listen"`
+	Script struct {
+		Exec json.RawMessage `json:"exec"` // A string or a list of lines
+	} `json:"script"`
+}
+
+// postmanEnvironment is a Postman environment or globals export
+type postmanEnvironment struct {
+	Name   string            `json:"name"`
+	Values []postmanKeyValue `json:"values"`
+	Scope  string            `json:"_postman_variable_scope"`
+}
+
+var postmanPathVariablePattern = regexp.MustCompile(`(^|/):([A-Za-z_][A-Za-z0-9_.-]*)`)
+
+// postmanDynamicVariablePattern matches Postman's generated values like {{$guid}}
+var postmanDynamicVariablePattern = regexp.MustCompile(`\{\{\s*\$[A-Za-z]+\s*\}\}`)
+
+// ImportPostman imports a Postman v2.1 collection, environment or globals export.
+// Folders become collections, top-level requests go into a collection named after the export.
+func (cl *ConfigLoader) ImportPostman(config *ConfigJSON, content string) (*ImportResult, error) {
+	var probe struct {
+		Info   json.RawMessage `json:"info"`
+		Values json.RawMessage `json:"values"`
+	}
+	if err := json.Unmarshal([]byte(content), &probe); err != nil {
+		return nil, fmt.Errorf("ImportPostman -> invalid JSON: %v", err)
+	}
+
+	switch {
+	case probe.Info != nil:
+		var collection postmanCollection
+		if err := json.Unmarshal([]byte(content), &collection); err != nil {
+			return nil, fmt.Errorf("ImportPostman -> invalid collection: %v", err)
+		}
+		return cl.importPostmanCollection(config, &collection)
+	case probe.Values != nil:
+		var environment postmanEnvironment
+		if err := json.Unmarshal([]byte(content), &environment); err != nil {
+			return nil, fmt.Errorf("ImportPostman -> invalid environment: %v", err)
+		}
+		return cl.importPostmanEnvironment(config, &environment)
+	default:
+		return nil, fmt.Errorf("ImportPostman -> not a Postman collection or environment export")
+	}
+}
+
+// postmanImport carries the state of one collection import
+type postmanImport struct {
+	cl        *ConfigLoader
+	config    *ConfigJSON
+	result    *ImportResult
+	variables map[string]map[string]string // Collection variables to add, per postless collection
+}
+
+func (cl *ConfigLoader) importPostmanCollection(config *ConfigJSON, collection *postmanCollection) (*ImportResult, error) {
+	if collection.Info.Schema != "" && !strings.Contains(collection.Info.Schema, "v2.1") && !strings.Contains(collection.Info.Schema, "v2.0") {
+		return nil, fmt.Errorf("ImportPostman -> unsupported collection schema %s, export it as Collection v2.1", collection.Info.Schema)
+	}
+
+	imp := &postmanImport{
+		cl:        cl,
+		config:    config,
+		result:    &ImportResult{},
+		variables: make(map[string]map[string]string),
+	}
+
+	rootName := importCollectionName(collection.Info.Name, "postman")
+	imp.checkEvents(collection.Info.Name, collection.Event)
+
+	// Collection variables apply to every folder, so every created collection gets them
+	var shared map[string]string
+	for _, variable := range collection.Variable {
+		if variable.active() && variable.Key != "" {
+			if shared == nil {
+				shared = make(map[string]string)
+			}
+			shared[variable.Key] = variable.text()
+		}
+	}
+
+	for _, item := range collection.Item {
+		if item.Request == nil {
+			if err := imp.importFolder(importCollectionName(item.Name, "postman"), item.Name, item, collection.Auth); err != nil {
+				return imp.result, err
+			}
+			continue
+		}
+		if err := imp.importRequest(rootName, item.Name, item, collection.Auth); err != nil {
+			return imp.result, err
+		}
+	}
+
+	for name := range imp.variables {
+		for key, value := range shared {
+			if _, ok := imp.variables[name][key]; !ok {
+				imp.variables[name][key] = value
+			}
+		}
+	}
+	for _, name := range sortedCollectionNames(imp.variables) {
+		if err := cl.mergeCollectionVariables(name, imp.variables[name]); err != nil {
+			return imp.result, err
+		}
+	}
+	return imp.result, nil
+}
+
+// importFolder writes the requests of a folder into one collection. Nested folders are
+// flattened into the collection of their top-level folder.
+func (imp *postmanImport) importFolder(collection, path string, folder postmanItem, auth *postmanAuth) error {
+	if folder.Auth != nil {
+		auth = folder.Auth
+	}
+	imp.checkEvents(path, folder.Event)
+	if _, ok := imp.variables[collection]; !ok {
+		imp.variables[collection] = make(map[string]string)
+	}
+
+	for _, item := range folder.Item {
+		itemPath := path + "/" + item.Name
+		if item.Request == nil {
+			if err := imp.importFolder(collection, itemPath, item, auth); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := imp.importRequest(collection, itemPath, item, auth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (imp *postmanImport) importRequest(collection, path string, item postmanItem, auth *postmanAuth) error {
+	imp.checkEvents(path, item.Event)
+	if item.Request.Auth != nil {
+		auth = item.Request.Auth
+	}
+	if _, ok := imp.variables[collection]; !ok {
+		imp.variables[collection] = make(map[string]string)
+	}
+
+	request, pathVariables, warnings := convertPostmanRequest(item.Name, item.Request, auth)
+	for _, warning := range warnings {
+		imp.warn(path, warning)
+	}
+	for name, value := range pathVariables {
+		if existing, ok := imp.variables[collection][name]; ok && existing != value {
+			imp.warn(path, fmt.Sprintf("path variable :%s keeps the value %q of an earlier request, not %q", name, existing, value))
+			continue
+		}
+		imp.variables[collection][name] = value
+	}
+	request.URL = templateBaseURL(request.URL, imp.config)
+
+	filePath, err := imp.cl.SaveImportedRequest(collection, request)
+	if err != nil {
+		return err
+	}
+	imp.result.Requests = append(imp.result.Requests, ImportedRequest{Name: request.Name, Collection: collection, FilePath: filePath})
+	return nil
+}
+
+func (imp *postmanImport) warn(path, message string) {
+	imp.result.Warnings = append(imp.result.Warnings, path+": "+message)
+}
+
+// checkEvents reports scripts, postless has no scripting
+func (imp *postmanImport) checkEvents(path string, events []postmanEvent) {
+	for _, event := range events {
+		if postmanScriptEmpty(event.Script.Exec) {
+			continue
+		}
+		switch event.Listen {
+		case "prerequest":
+			imp.warn(path, "pre-request script not converted")
+		case "test":
+			imp.warn(path, "test script not converted, use assertions and captures instead")
+		default:
+			imp.warn(path, event.Listen+" script not converted")
+		}
+	}
+}
+
+func postmanScriptEmpty(exec json.RawMessage) bool {
+	var lines []string
+	if err := json.Unmarshal(exec, &lines); err != nil {
+		var line string
+		if err := json.Unmarshal(exec, &line); err != nil {
+			return true
+		}
+		lines = []string{line}
+	}
+	return strings.TrimSpace(strings.Join(lines, "")) == ""
+}
+
+// convertPostmanRequest converts one request, returning the values of its :name path variables
+func convertPostmanRequest(name string, source *postmanRequest, auth *postmanAuth) (*RequestJSON, map[string]string, []string) {
+	var warnings []string
+	request := &RequestJSON{
+		Name:   name,
+		Method: strings.ToUpper(source.Method),
+	}
+	if request.Method == "" {
+		request.Method = "GET"
+	}
+
+	// Path variables (:id) become postless variables ({{id}})
+	pathVariables := make(map[string]string)
+	rawURL := postmanRawURL(&source.URL)
+	base, query, hasQuery := strings.Cut(rawURL, "?")
+	base = postmanPathVariablePattern.ReplaceAllString(base, "$1{{$2}}")
+	if hasQuery {
+		base += "?" + query
+	}
+	request.URL = base
+	for _, variable := range source.URL.Variable {
+		if variable.Key != "" {
+			pathVariables[variable.Key] = variable.text()
+		}
+	}
+
+	headers := make(map[string]string)
+	for _, header := range source.Header {
+		if !header.active() || header.Key == "" {
+			continue
+		}
+		if existing, ok := lookupHeader(headers, header.Key); ok {
+			warnings = append(warnings, fmt.Sprintf("duplicate header %s, only the last value is kept", header.Key))
+			delete(headers, existing)
+		}
+		headers[header.Key] = header.text()
+	}
+
+	warnings = append(warnings, applyPostmanAuth(request, headers, auth)...)
+	warnings = append(warnings, applyPostmanBody(request, headers, source.Body)...)
+
+	texts := append([]string{request.URL, request.RawBody}, mapValues(headers)...)
+	texts = append(texts, mapValues(request.Form)...)
+	if request.Body != nil {
+		texts = append(texts, compactJSON(request.Body))
+	}
+	for _, text := range texts {
+		for _, match := range postmanDynamicVariablePattern.FindAllString(text, -1) {
+			warnings = append(warnings, fmt.Sprintf("dynamic variable %s has no postless equivalent, define it as a variable", match))
+		}
+	}
+
+	if len(headers) > 0 {
+		request.Headers = headers
+	}
+	return request, pathVariables, warnings
+}
+
+// postmanRawURL returns the URL as typed in Postman, rebuilding it from its parts when missing
+func postmanRawURL(u *postmanURL) string {
+	if u.Raw != "" {
+		return u.Raw
+	}
+	rawURL := strings.Join(u.Host, ".")
+	if u.Protocol != "" {
+		rawURL = u.Protocol + "://" + rawURL
+	}
+	if len(u.Path) > 0 {
+		rawURL += "/" + strings.Join(u.Path, "/")
+	}
+	var query []string
+	for _, param := range u.Query {
+		if param.active() {
+			query = append(query, param.Key+"="+param.text())
+		}
+	}
+	if len(query) > 0 {
+		rawURL += "?" + strings.Join(query, "&")
+	}
+	return rawURL
+}
+
+// applyPostmanAuth turns Postman authentication into headers. Requests with their own
+// authentication (or none) skip the postless JWT.
+func applyPostmanAuth(request *RequestJSON, headers map[string]string, auth *postmanAuth) []string {
+	if auth == nil {
+		return nil
+	}
+
+	switch auth.Type {
+	case "noauth":
+		request.SkipAuth = true
+	case "bearer":
+		headers["Authorization"] = "Bearer " + auth.param(auth.Bearer, "token")
+		request.SkipAuth = true
+	case "basic":
+		credentials := auth.param(auth.Basic, "username") + ":" + auth.param(auth.Basic, "password")
+		request.SkipAuth = true
+		if variablePattern.MatchString(credentials) {
+			headers["Authorization"] = "Basic {{basicAuth}}"
+			return []string{fmt.Sprintf("basic auth uses variables, set {{basicAuth}} to the base64 of %q", credentials)}
+		}
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	case "apikey":
+		key, value := auth.param(auth.APIKey, "key"), auth.param(auth.APIKey, "value")
+		if auth.param(auth.APIKey, "in") == "query" {
+			separator := "?"
+			if strings.Contains(request.URL, "?") {
+				separator = "&"
+			}
+			request.URL += separator + key + "=" + value
+		} else {
+			headers[key] = value
+		}
+	default:
+		return []string{fmt.Sprintf("%s authentication not converted", auth.Type)}
+	}
+	return nil
+}
+
+func applyPostmanBody(request *RequestJSON, headers map[string]string, body *postmanBody) []string {
+	if body == nil || body.Disabled || body.Mode == "" {
+		return nil
+	}
+
+	switch body.Mode {
+	case "raw":
+		if strings.TrimSpace(body.Raw) == "" {
+			return nil
+		}
+		contentTypeName, hasContentType := lookupHeader(headers, "Content-Type")
+		contentType := strings.ToLower(headers[contentTypeName])
+		language := body.Options.Raw.Language
+
+		var parsed interface{}
+		if (language == "json" || strings.Contains(contentType, "json")) && json.Unmarshal([]byte(body.Raw), &parsed) == nil {
+			request.Body = parsed
+			// postless sends JSON bodies as application/json already
+			if hasContentType && contentType == "application/json" {
+				delete(headers, contentTypeName)
+			}
+			return nil
+		}
+
+		request.RawBody = body.Raw
+		if !hasContentType {
+			headers["Content-Type"] = postmanRawContentType(language)
+		}
+	case "urlencoded":
+		var pairs []string
+		for _, field := range body.URLEncoded {
+			if field.active() {
+				pairs = append(pairs, escapeFormPreservingVariables(field.Key)+"="+escapeFormPreservingVariables(field.text()))
+			}
+		}
+		request.RawBody = strings.Join(pairs, "&")
+		if _, ok := lookupHeader(headers, "Content-Type"); !ok {
+			headers["Content-Type"] = "application/x-www-form-urlencoded"
+		}
+	case "formdata":
+		var warnings []string
+		form := make(map[string]string)
+		for _, field := range body.FormData {
+			if !field.active() {
+				continue
+			}
+			if _, ok := form[field.Key]; ok {
+				warnings = append(warnings, fmt.Sprintf("duplicate form field %s, only the last value is kept", field.Key))
+			}
+			if field.Type == "file" {
+				var src string
+				if err := json.Unmarshal(field.Src, &src); err != nil || src == "" {
+					warnings = append(warnings, fmt.Sprintf("form file %s has no single source file, set its path by hand", field.Key))
+				}
+				form[field.Key] = "@" + src
+				continue
+			}
+			form[field.Key] = field.text()
+		}
+		if len(form) > 0 {
+			request.Form = form
+		}
+		// The multipart boundary is generated when sending
+		if name, ok := lookupHeader(headers, "Content-Type"); ok {
+			delete(headers, name)
+		}
+		return warnings
+	case "graphql":
+		if body.GraphQL == nil {
+			return nil
+		}
+		payload := map[string]interface{}{"query": body.GraphQL.Query}
+		if strings.TrimSpace(body.GraphQL.Variables) != "" {
+			var variables interface{}
+			if err := json.Unmarshal([]byte(body.GraphQL.Variables), &variables); err != nil {
+				return []string{"GraphQL variables are not valid JSON and were dropped"}
+			}
+			payload["variables"] = variables
+		}
+		request.Body = payload
+	default:
+		return []string{fmt.Sprintf("%s body not converted", body.Mode)}
+	}
+	return nil
+}
+
+func postmanRawContentType(language string) string {
+	switch language {
+	case "json":
+		return "application/json"
+	case "xml":
+		return "application/xml"
+	case "html":
+		return "text/html"
+	case "javascript":
+		return "application/javascript"
+	default:
+		return "text/plain"
+	}
+}
+
+// escapeFormPreservingVariables URL-encodes form text, leaving {{name}} references intact
+func escapeFormPreservingVariables(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range variablePattern.FindAllStringIndex(text, -1) {
+		b.WriteString(url.QueryEscape(text[last:match[0]]))
+		b.WriteString(text[match[0]:match[1]])
+		last = match[1]
+	}
+	b.WriteString(url.QueryEscape(text[last:]))
+	return b.String()
+}
+
+// importPostmanEnvironment adds a Postman environment to config.json, or its globals to the
+// project variables. Values already in config.json are kept.
+func (cl *ConfigLoader) importPostmanEnvironment(config *ConfigJSON, environment *postmanEnvironment) (*ImportResult, error) {
+	result := &ImportResult{}
+	name := environment.Name
+
+	var variables map[string]string
+	var env EnvironmentJSON
+	if environment.Scope == "globals" {
+		if config.Variables == nil {
+			config.Variables = make(map[string]string)
+		}
+		variables = config.Variables
+		name = "globals"
+	} else {
+		if name == "" {
+			return nil, fmt.Errorf("ImportPostman -> environment has no name")
+		}
+		if name == DefaultEnvironment {
+			return nil, fmt.Errorf("ImportPostman -> environment name %q is reserved for the top-level baseUrl, rename it", name)
+		}
+		if config.Environments == nil {
+			config.Environments = make(map[string]EnvironmentJSON)
+		}
+		env = config.Environments[name]
+		if env.Variables == nil {
+			env.Variables = make(map[string]string)
+		}
+		variables = env.Variables
+	}
+
+	for _, value := range environment.Values {
+		if !value.active() || value.Key == "" {
+			continue
+		}
+		// The environment base URL drives {{baseUrl}} and the environment switcher
+		if value.Key == "baseUrl" && environment.Scope != "globals" {
+			if env.BaseUrl == "" {
+				env.BaseUrl = value.text()
+			}
+			continue
+		}
+		if existing, ok := variables[value.Key]; ok {
+			if existing != value.text() {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %s already set, kept %q", name, value.Key, existing))
+			}
+			continue
+		}
+		variables[value.Key] = value.text()
+	}
+
+	if environment.Scope != "globals" {
+		config.Environments[name] = env
+	}
+	if err := cl.SaveConfigJSON(config); err != nil {
+		return nil, fmt.Errorf("ImportPostman -> %v", err)
+	}
+	result.Environments = append(result.Environments, name)
+	return result, nil
+}
+
+func sortedCollectionNames(m map[string]map[string]string) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func mapValues(m map[string]string) []string {
+	values := make([]string, 0, len(m))
+	for _, value := range m {
+		values = append(values, value)
+	}
+	return values
+}
@@ -0,0 +1,275 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestConvertPostmanRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		request       string // Postman request object
+		auth          string // Inherited authentication, none when empty
+		want          string // The request as JSON
+		pathVariables map[string]string
+		warnings      []string
+	}{
+		{
+			name:          "path variables and disabled headers",
+			request:       `{"method": "get", "url": {"raw": "{{host}}/users/:id/posts?page=1", "variable": [{"key": "id", "value": "42"}]}, "header": [{"key": "X-Off", "value": "1", "disabled": true}, {"key": "Accept", "value": "text/plain"}]}`,
+			want:          `{"name": "Get", "method": "GET", "url": "{{host}}/users/{{id}}/posts?page=1", "skipAuth": false, "headers": {"Accept": "text/plain"}}`,
+			pathVariables: map[string]string{"id": "42"},
+		},
+		{
+			name:    "URL built from its parts",
+			request: `{"method": "GET", "url": {"protocol": "https", "host": ["api", "test"], "path": ["v1", "items"], "query": [{"key": "a", "value": "1"}, {"key": "b", "value": "2", "disabled": true}]}}`,
+			want:    `{"name": "Get", "method": "GET", "url": "https://api.test/v1/items?a=1", "skipAuth": false}`,
+		},
+		{
+			name:     "duplicate headers",
+			request:  `{"url": "https://api.test", "header": [{"key": "x-id", "value": "1"}, {"key": "X-Id", "value": "2"}]}`,
+			want:     `{"name": "Get", "method": "GET", "url": "https://api.test", "skipAuth": false, "headers": {"X-Id": "2"}}`,
+			warnings: []string{"duplicate header X-Id, only the last value is kept"},
+		},
+		{
+			name:    "inherited bearer authentication",
+			request: `{"url": "https://api.test"}`,
+			auth:    `{"type": "bearer", "bearer": [{"key": "token", "value": "{{token}}"}]}`,
+			want:    `{"name": "Get", "method": "GET", "url": "https://api.test", "skipAuth": true, "headers": {"Authorization": "Bearer {{token}}"}}`,
+		},
+		{
+			name:    "basic authentication",
+			request: `{"url": "https://api.test"}`,
+			auth:    `{"type": "basic", "basic": [{"key": "username", "value": "ada"}, {"key": "password", "value": "pw"}]}`,
+			want:    `{"name": "Get", "method": "GET", "url": "https://api.test", "skipAuth": true, "headers": {"Authorization": "Basic YWRhOnB3"}}`,
+		},
+		{
+			name:     "basic authentication with variables",
+			request:  `{"url": "https://api.test"}`,
+			auth:     `{"type": "basic", "basic": [{"key": "username", "value": "{{user}}"}, {"key": "password", "value": "pw"}]}`,
+			want:     `{"name": "Get", "method": "GET", "url": "https://api.test", "skipAuth": true, "headers": {"Authorization": "Basic {{basicAuth}}"}}`,
+			warnings: []string{`basic auth uses variables, set {{basicAuth}} to the base64 of "{{user}}:pw"`},
+		},
+		{
+			name:    "API key in the query",
+			request: `{"url": "https://api.test?a=1"}`,
+			auth:    `{"type": "apikey", "apikey": [{"key": "key", "value": "api_key"}, {"key": "value", "value": "{{key}}"}, {"key": "in", "value": "query"}]}`,
+			want:    `{"name": "Get", "method": "GET", "url": "https://api.test?a=1&api_key={{key}}", "skipAuth": false}`,
+		},
+		{
+			name:     "unsupported authentication",
+			request:  `{"url": "https://api.test"}`,
+			auth:     `{"type": "oauth1"}`,
+			want:     `{"name": "Get", "method": "GET", "url": "https://api.test", "skipAuth": false}`,
+			warnings: []string{"oauth1 authentication not converted"},
+		},
+		{
+			name:    "raw JSON body",
+			request: `{"method": "POST", "url": "https://api.test", "header": [{"key": "Content-Type", "value": "application/json"}], "body": {"mode": "raw", "raw": "{\"id\": 1}", "options": {"raw": {"language": "json"}}}}`,
+			want:    `{"name": "Get", "method": "POST", "url": "https://api.test", "skipAuth": false, "body": {"id": 1}}`,
+		},
+		{
+			name:    "raw XML body",
+			request: `{"method": "POST", "url": "https://api.test", "body": {"mode": "raw", "raw": "<a/>", "options": {"raw": {"language": "xml"}}}}`,
+			want:    `{"name": "Get", "method": "POST", "url": "https://api.test", "skipAuth": false, "headers": {"Content-Type": "application/xml"}, "rawBody": "<a/>"}`,
+		},
+		{
+			name:    "urlencoded body",
+			request: `{"method": "POST", "url": "https://api.test", "body": {"mode": "urlencoded", "urlencoded": [{"key": "q", "value": "a b"}, {"key": "id", "value": "{{id}}"}, {"key": "off", "value": "1", "disabled": true}]}}`,
+			want:    `{"name": "Get", "method": "POST", "url": "https://api.test", "skipAuth": false, "headers": {"Content-Type": "application/x-www-form-urlencoded"}, "rawBody": "q=a+b&id={{id}}"}`,
+		},
+		{
+			name:     "form data",
+			request:  `{"method": "POST", "url": "https://api.test", "header": [{"key": "Content-Type", "value": "multipart/form-data"}], "body": {"mode": "formdata", "formdata": [{"key": "title", "value": "A"}, {"key": "file", "type": "file", "src": "/tmp/a.png"}, {"key": "many", "type": "file", "src": ["a", "b"]}]}}`,
+			want:     `{"name": "Get", "method": "POST", "url": "https://api.test", "skipAuth": false, "form": {"file": "@/tmp/a.png", "many": "@", "title": "A"}}`,
+			warnings: []string{"form file many has no single source file, set its path by hand"},
+		},
+		{
+			name:    "GraphQL body",
+			request: `{"method": "POST", "url": "https://api.test/graphql", "body": {"mode": "graphql", "graphql": {"query": "{ me { id } }", "variables": "{\"a\": 1}"}}}`,
+			want:    `{"name": "Get", "method": "POST", "url": "https://api.test/graphql", "skipAuth": false, "body": {"query": "{ me { id } }", "variables": {"a": 1}}}`,
+		},
+		{
+			name:     "dynamic variables",
+			request:  `{"url": "https://api.test/{{$guid}}"}`,
+			want:     `{"name": "Get", "method": "GET", "url": "https://api.test/{{$guid}}", "skipAuth": false}`,
+			warnings: []string{"dynamic variable {{$guid}} has no postless equivalent, define it as a variable"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var source postmanRequest
+			if err := json.Unmarshal([]byte(tt.request), &source); err != nil {
+				t.Fatalf("invalid test request: %v", err)
+			}
+			var auth *postmanAuth
+			if tt.auth != "" {
+				if err := json.Unmarshal([]byte(tt.auth), &auth); err != nil {
+					t.Fatalf("invalid test auth: %v", err)
+				}
+			}
+
+			request, pathVariables, warnings := convertPostmanRequest("Get", &source, auth)
+			got, err := ToCompactJSON(request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := compactTestJSON(t, tt.want); got != want {
+				t.Errorf("got  %s\nwant %s", got, want)
+			}
+			if fmt.Sprint(pathVariables) != fmt.Sprint(tt.pathVariables) { // nil and empty maps alike
+				t.Errorf("got path variables %v, want %v", pathVariables, tt.pathVariables)
+			}
+			if !reflect.DeepEqual(warnings, tt.warnings) {
+				t.Errorf("got warnings %q, want %q", warnings, tt.warnings)
+			}
+		})
+	}
+}
+
+func TestImportPostmanCollection(t *testing.T) {
+	cl, fm := newTestConfigLoader(t)
+	config := &ConfigJSON{BaseUrl: "https://api.test"}
+
+	const collection = `{
+	  "info": {"name": "Shop API", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"},
+	  "variable": [{"key": "tenant", "value": "acme"}],
+	  "auth": {"type": "bearer", "bearer": [{"key": "token", "value": "{{token}}"}]},
+	  "item": [
+	    {"name": "Health", "request": {"url": "https://api.test/health", "auth": {"type": "noauth"}}},
+	    {"name": "Users", "auth": {"type": "basic", "basic": [{"key": "username", "value": "ada"}, {"key": "password", "value": "pw"}]}, "item": [
+	      {"name": "Get user", "request": {"url": {"raw": "https://api.test/users/:id", "variable": [{"key": "id", "value": "1"}]}}},
+	      {"name": "Admin", "item": [
+	        {"name": "Get user", "request": {"url": {"raw": "https://api.test/admin/users/:id", "variable": [{"key": "id", "value": "2"}]}},
+	         "event": [{"listen": "test", "script": {"exec": ["pm.test('ok')"]}}]}
+	      ]}
+	    ]}
+	  ]
+	}`
+
+	result, err := cl.ImportPostman(config, collection)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var files []string
+	for _, request := range result.Requests {
+		key, err := filepath.Rel(fm.RequestsDir, request.FilePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, filepath.ToSlash(key))
+	}
+	if want := []string{"Shop API/health.json", "Users/get-user.json", "Users/get-user-2.json"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("got files %q, want %q", files, want)
+	}
+	wantWarnings := []string{
+		"Users/Admin/Get user: test script not converted, use assertions and captures instead",
+		`Users/Admin/Get user: path variable :id keeps the value "1" of an earlier request, not "2"`,
+	}
+	if !reflect.DeepEqual(result.Warnings, wantWarnings) {
+		t.Errorf("got warnings %q, want %q", result.Warnings, wantWarnings)
+	}
+
+	tests := []struct {
+		file string
+		want string // The request file as JSON
+	}{
+		{"Shop API/health.json", `{"name": "Health", "method": "GET", "url": "{{baseUrl}}/health", "skipAuth": true}`},
+		{"Users/get-user.json", `{"name": "Get user", "method": "GET", "url": "{{baseUrl}}/users/{{id}}", "skipAuth": true, "headers": {"Authorization": "Basic YWRhOnB3"}}`},
+		{"Users/get-user-2.json", `{"name": "Get user", "method": "GET", "url": "{{baseUrl}}/admin/users/{{id}}", "skipAuth": true, "headers": {"Authorization": "Basic YWRhOnB3"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			content, err := os.ReadFile(filepath.Join(fm.RequestsDir, filepath.FromSlash(tt.file)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			request, err := ParseJSONContent[RequestJSON](string(content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ToCompactJSON(request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := compactTestJSON(t, tt.want); got != want {
+				t.Errorf("got  %s\nwant %s", got, want)
+			}
+		})
+	}
+
+	variables, err := cl.loadCollectionVariables(filepath.Join(fm.RequestsDir, "Users"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"id": "1", "tenant": "acme"}; !reflect.DeepEqual(variables, want) {
+		t.Errorf("got collection variables %v, want %v", variables, want)
+	}
+}
+
+func TestImportPostmanEnvironment(t *testing.T) {
+	tests := []struct {
+		name     string
+		export   string
+		want     string // config.json after the import
+		warnings []string
+		wantErr  string
+	}{
+		{
+			name:   "environment",
+			export: `{"name": "staging", "values": [{"key": "baseUrl", "value": "https://staging.test"}, {"key": "tenant", "value": "globex"}, {"key": "off", "value": "1", "enabled": false}]}`,
+			want:   `{"baseUrl": "https://api.test", "variables": {"tenant": "acme"}, "environments": {"staging": {"baseUrl": "https://staging.test", "variables": {"tenant": "globex"}}}}`,
+		},
+		{
+			name:     "globals keep existing values",
+			export:   `{"name": "Globals", "_postman_variable_scope": "globals", "values": [{"key": "tenant", "value": "initech"}, {"key": "region", "value": "eu"}]}`,
+			want:     `{"baseUrl": "https://api.test", "variables": {"region": "eu", "tenant": "acme"}}`,
+			warnings: []string{`globals: tenant already set, kept "acme"`},
+		},
+		{name: "reserved name", export: `{"name": "default", "values": []}`, wantErr: `ImportPostman -> environment name "default" is reserved for the top-level baseUrl, rename it`},
+		{name: "no name", export: `{"values": []}`, wantErr: "ImportPostman -> environment has no name"},
+		{name: "not an export", export: `{"item": []}`, wantErr: "ImportPostman -> not a Postman collection or environment export"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl, fm := newTestConfigLoader(t)
+			const original = `{"baseUrl": "https://api.test", "variables": {"tenant": "acme"}}`
+			if err := fm.WriteConfigContent(original); err != nil {
+				t.Fatal(err)
+			}
+			config, err := ParseJSONContent[ConfigJSON](original)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := cl.ImportPostman(config, tt.export)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result.Warnings, tt.warnings) {
+				t.Errorf("got warnings %q, want %q", result.Warnings, tt.warnings)
+			}
+
+			content, err := fm.GetConfigContent()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := decodeTestJSON(t, content), decodeTestJSON(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("got config %s\nwant %s", content, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package src
+
+import (
+	"net/http"
+	"strings"
+)
+
+// RedactedValue stands in for credentials left out of history and exports
+const RedactedValue = "[redacted]"
+
+// credentialHeaders are the request headers that carry credentials
+var credentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// isCredentialHeader reports whether the named request header carries credentials
+func isCredentialHeader(name string) bool {
+	for _, credential := range credentialHeaders {
+		if strings.EqualFold(name, credential) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactHeaders returns a copy of headers with the values of the named headers replaced.
+// Authorization values keep their scheme ("Bearer [redacted]") so the kind of credentials stays visible.
+func redactHeaders(headers http.Header, names []string) http.Header {
+	redacted := headers.Clone()
+	for _, name := range names {
+		values := redacted.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		replaced := make([]string, len(values))
+		for i, value := range values {
+			replaced[i] = RedactedValue
+			if strings.HasSuffix(name, "Authorization") {
+				if scheme, _, ok := strings.Cut(value, " "); ok {
+					replaced[i] = scheme + " " + RedactedValue
+				}
+			}
+		}
+		redacted[http.CanonicalHeaderKey(name)] = replaced
+	}
+	return redacted
+}
+
+// Redacted returns a copy of the request with the values of its credential headers replaced
+func (r *ResolvedRequest) Redacted() *ResolvedRequest {
+	if r == nil {
+		return nil
+	}
+	redacted := *r
+	redacted.Headers = redactHeaders(r.Headers, credentialHeaders)
+	return &redacted
+}
+
+// redactedHeaders lists the credential headers of a request that were redacted when recorded
+func (r *ResolvedRequest) redactedHeaders() []string {
+	var names []string
+	for _, name := range credentialHeaders {
+		for _, value := range r.Headers.Values(name) {
+			if strings.HasSuffix(value, RedactedValue) {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// RestoreCredentials fills the credentials redacted from a recorded request with the ones the
+// request would be sent with now: the current JWT, or the headers of its request file.
+// Headers that have no current value are left out.
+func (c *HTTPClient) RestoreCredentials(recorded *ResolvedRequest, item *RequestItem) *ResolvedRequest {
+	names := recorded.redactedHeaders()
+	if len(names) == 0 {
+		return recorded
+	}
+
+	restored := *recorded
+	restored.Headers = recorded.Headers.Clone()
+	current, err := c.ResolveRequest(item.Request, item.Variables)
+	for _, name := range names {
+		if err == nil && len(current.Headers.Values(name)) > 0 {
+			restored.Headers[http.CanonicalHeaderKey(name)] = current.Headers.Values(name)
+		} else {
+			restored.Headers.Del(name)
+		}
+	}
+	return &restored
+}
@@ -0,0 +1,298 @@
+package src
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ReportFormatJUnit = "junit"
+	ReportFormatTAP   = "tap"
+	ReportFormatJSON  = "json"
+)
+
+// InferReportFormat guesses the report format from a file extension
+func InferReportFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xml":
+		return ReportFormatJUnit
+	case ".tap":
+		return ReportFormatTAP
+	case ".json":
+		return ReportFormatJSON
+	default:
+		return ""
+	}
+}
+
+// WriteTestReport writes a machine-readable report of a test run
+func WriteTestReport(w io.Writer, format string, run *TestRunResult) error {
+	switch format {
+	case ReportFormatJUnit:
+		return writeJUnitReport(w, run)
+	case ReportFormatTAP:
+		return writeTAPReport(w, run)
+	case ReportFormatJSON:
+		return writeJSONReport(w, run)
+	default:
+		return fmt.Errorf("WriteTestReport -> unknown format %q (use junit, tap or json)", format)
+	}
+}
+
+func durationSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
+
+func failedAssertionsText(result RequestRunResult) string {
+	var lines []string
+	for _, assertion := range result.Assertions {
+		if assertion.Passed {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s\n  expected: %s\n  actual:   %s", assertion.Description, assertion.Expected, assertion.Actual))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// JUnit XML
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func writeJUnitReport(w io.Writer, run *TestRunResult) error {
+	passed, failed, errored := run.Counts()
+	root := junitTestSuites{
+		Name:     "postless",
+		Tests:    passed + failed + errored,
+		Failures: failed,
+		Errors:   errored,
+		Time:     durationSeconds(run.Duration),
+	}
+
+	suiteIndex := map[string]int{}
+	for _, result := range run.Results {
+		index, ok := suiteIndex[result.Collection]
+		if !ok {
+			index = len(root.Suites)
+			suiteIndex[result.Collection] = index
+			root.Suites = append(root.Suites, junitTestSuite{
+				Name:       result.Collection,
+				Timestamp:  run.StartedAt.Format(time.RFC3339),
+				Properties: []junitProperty{{Name: "environment", Value: run.Environment}},
+			})
+		}
+		suite := &root.Suites[index]
+
+		testCase := junitTestCase{
+			Name:      result.Name,
+			ClassName: result.Collection,
+			File:      result.FilePath,
+			Time:      durationSeconds(result.Duration),
+			SystemOut: fmt.Sprintf("%s %s\nstatus: %d\nsize: %d bytes", result.Method, result.URL, result.StatusCode, result.Size),
+		}
+
+		suite.Tests++
+		switch {
+		case result.Error != "":
+			suite.Errors++
+			testCase.Error = &junitProblem{Message: result.Error, Type: "error", Text: result.Error}
+		case !result.Passed():
+			suite.Failures++
+			text := failedAssertionsText(result)
+			testCase.Failure = &junitProblem{Message: strings.SplitN(text, "\n", 2)[0], Type: "assertion", Text: text}
+		}
+
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	// Suite durations are the sum of their requests
+	for i := range root.Suites {
+		var total time.Duration
+		for _, result := range run.Results {
+			if result.Collection == root.Suites[i].Name {
+				total += result.Duration
+			}
+		}
+		root.Suites[i].Time = durationSeconds(total)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("writeJUnitReport -> %v", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(root); err != nil {
+		return fmt.Errorf("writeJUnitReport -> %v", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// TAP version 13
+
+func writeTAPReport(w io.Writer, run *TestRunResult) error {
+	var b strings.Builder
+
+	b.WriteString("TAP version 13\n")
+	b.WriteString(fmt.Sprintf("1..%d\n", len(run.Results)))
+
+	for i, result := range run.Results {
+		status := "ok"
+		if !result.Passed() {
+			status = "not ok"
+		}
+		b.WriteString(fmt.Sprintf("%s %d - %s / %s\n", status, i+1, result.Collection, result.Name))
+
+		// YAML diagnostic block
+		b.WriteString("  ---\n")
+		b.WriteString(fmt.Sprintf("  method: %s\n", result.Method))
+		b.WriteString(fmt.Sprintf("  url: %s\n", strconv.Quote(result.URL)))
+		b.WriteString(fmt.Sprintf("  status: %d\n", result.StatusCode))
+		b.WriteString(fmt.Sprintf("  duration_ms: %d\n", result.Duration.Milliseconds()))
+		b.WriteString(fmt.Sprintf("  size: %d\n", result.Size))
+		if result.Error != "" {
+			b.WriteString(fmt.Sprintf("  error: %s\n", strconv.Quote(result.Error)))
+		}
+		if len(result.Assertions) > 0 {
+			b.WriteString("  assertions:\n")
+			for _, assertion := range result.Assertions {
+				b.WriteString(fmt.Sprintf("    - description: %s\n", strconv.Quote(assertion.Description)))
+				b.WriteString(fmt.Sprintf("      passed: %t\n", assertion.Passed))
+				if !assertion.Passed {
+					b.WriteString(fmt.Sprintf("      expected: %s\n", strconv.Quote(assertion.Expected)))
+					b.WriteString(fmt.Sprintf("      actual: %s\n", strconv.Quote(assertion.Actual)))
+				}
+			}
+		}
+		b.WriteString("  ...\n")
+	}
+
+	passed, failed, errored := run.Counts()
+	b.WriteString(fmt.Sprintf("# passed %d, failed %d, errors %d\n", passed, failed, errored))
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("writeTAPReport -> %v", err)
+	}
+	return nil
+}
+
+// Structured JSON
+
+type jsonReport struct {
+	StartedAt   string              `json:"startedAt"`
+	DurationMs  int64               `json:"durationMs"`
+	Environment string              `json:"environment"`
+	Summary     jsonReportSummary   `json:"summary"`
+	Results     []jsonReportRequest `json:"results"`
+}
+
+type jsonReportSummary struct {
+	Total  int `json:"total"`
+	Passed int `json:"passed"`
+	Failed int `json:"failed"`
+	Errors int `json:"errors"`
+}
+
+type jsonReportRequest struct {
+	Collection string            `json:"collection"`
+	Name       string            `json:"name"`
+	File       string            `json:"file,omitempty"`
+	Method     string            `json:"method"`
+	URL        string            `json:"url"`
+	StatusCode int               `json:"statusCode"`
+	Status     string            `json:"status"`
+	DurationMs int64             `json:"durationMs"`
+	Size       int64             `json:"size"`
+	Passed     bool              `json:"passed"`
+	Assertions []AssertionResult `json:"assertions"`
+	Error      string            `json:"error,omitempty"`
+}
+
+func writeJSONReport(w io.Writer, run *TestRunResult) error {
+	passed, failed, errored := run.Counts()
+	report := jsonReport{
+		StartedAt:   run.StartedAt.Format(time.RFC3339),
+		DurationMs:  run.Duration.Milliseconds(),
+		Environment: run.Environment,
+		Summary: jsonReportSummary{
+			Total:  len(run.Results),
+			Passed: passed,
+			Failed: failed,
+			Errors: errored,
+		},
+		Results: []jsonReportRequest{},
+	}
+
+	for _, result := range run.Results {
+		assertions := result.Assertions
+		if assertions == nil {
+			assertions = []AssertionResult{}
+		}
+		report.Results = append(report.Results, jsonReportRequest{
+			Collection: result.Collection,
+			Name:       result.Name,
+			File:       result.FilePath,
+			Method:     result.Method,
+			URL:        result.URL,
+			StatusCode: result.StatusCode,
+			Status:     result.Status,
+			DurationMs: result.Duration.Milliseconds(),
+			Size:       result.Size,
+			Passed:     result.Passed(),
+			Assertions: assertions,
+			Error:      result.Error,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("writeJSONReport -> %v", err)
+	}
+	return nil
+}
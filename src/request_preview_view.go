@@ -3,7 +3,6 @@ package src
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -14,8 +13,6 @@ type RequestPreviewViewModel struct {
 	config          *ConfigJSON
 	secret          *SecretJSON
 	configLoader    *ConfigLoader
-	action          *string
-	quitting        bool
 	styles          *Styles
 }
 
@@ -25,7 +22,6 @@ func NewRequestPreviewViewModel(selectedRequest *RequestItem, config *ConfigJSON
 		config:          config,
 		secret:          secret,
 		configLoader:    configLoader,
-		quitting:        false,
 		styles:          DefaultStyles(),
 	}
 }
@@ -38,18 +34,24 @@ func (m RequestPreviewViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "ctrl+c", "q", "esc":
-			*m.action = "cancel"
-			m.quitting = true
-			return m, tea.Quit
+		case "q", "esc":
+			return m, emit(navigateBackMsg{})
 		case "e", "E":
-			*m.action = "edit"
-			m.quitting = true
-			return m, tea.Quit
+			return m, emit(editBodyMsg{item: m.selectedRequest})
 		case "enter":
-			*m.action = "execute"
-			m.quitting = true
-			return m, tea.Quit
+			return m, emit(executeRequestMsg{item: m.selectedRequest})
+		case "c":
+			resolved, err := NewHTTPClient(m.config, m.secret, m.configLoader).ResolveRequest(m.selectedRequest.Request, m.selectedRequest.Variables)
+			if err != nil {
+				return m, emit(statusMsg{text: "⚠️  Cannot build the cURL command: " + err.Error(), isError: true})
+			}
+			command, err := ExportCurl(resolved)
+			if err != nil {
+				return m, emit(statusMsg{text: "⚠️  Cannot build the cURL command: " + err.Error(), isError: true})
+			}
+			return m, emit(copyToClipboardMsg{text: command, description: "cURL command"})
+		case "g":
+			return m, emit(generateCodeMsg{item: m.selectedRequest})
 		}
 	}
 
@@ -57,10 +59,6 @@ func (m RequestPreviewViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m RequestPreviewViewModel) View() string {
-	if m.quitting {
-		return ""
-	}
-
 	req := m.selectedRequest.Request
 
 	// Build the view
@@ -75,8 +73,10 @@ func (m RequestPreviewViewModel) View() string {
 	// Method and URL
 	methodColor := getMethodColor(req.Method, m.styles)
 	view += m.styles.Text(fmt.Sprintf("  Method:   %s", req.Method), methodColor) + "\n"
+	view += m.styles.Text(fmt.Sprintf("  Env:      %s", m.configLoader.GetEnvironmentLabel(m.config)), m.styles.ThistleColor) + "\n"
 
-	url := m.configLoader.ReplaceVariables(req.URL, m.config)
+	resolver := m.configLoader.NewResolver(m.config, m.selectedRequest.Variables)
+	url := resolver.ResolveLenient(req.URL)
 	view += m.styles.Text(fmt.Sprintf("  URL:      %s", url), m.styles.FooterColor) + "\n"
 	view += "\n"
 
@@ -94,14 +94,21 @@ func (m RequestPreviewViewModel) View() string {
 	// Global headers
 	if m.config.GlobalHeaders != nil {
 		for key, value := range m.config.GlobalHeaders {
-			view += m.styles.Text(fmt.Sprintf("    %s: %s", key, value), m.styles.MutedTitleColor) + "\n"
+			view += m.styles.Text(fmt.Sprintf("    %s: %s", key, resolver.ResolveLenient(value)), m.styles.MutedTitleColor) + "\n"
+		}
+	}
+
+	// Environment headers
+	if env := m.config.GetActiveEnvironment(); env != nil {
+		for key, value := range env.Headers {
+			view += m.styles.Text(fmt.Sprintf("    %s: %s", key, resolver.ResolveLenient(value)), m.styles.ThistleColor) + "\n"
 		}
 	}
 
 	// Request-specific headers
 	if req.Headers != nil {
 		for key, value := range req.Headers {
-			view += m.styles.Text(fmt.Sprintf("    %s: %s", key, value), m.styles.FooterColor) + "\n"
+			view += m.styles.Text(fmt.Sprintf("    %s: %s", key, resolver.ResolveLenient(value)), m.styles.FooterColor) + "\n"
 		}
 	}
 	view += "\n"
@@ -112,13 +119,23 @@ func (m RequestPreviewViewModel) View() string {
 		bodyJSON, _ := json.MarshalIndent(req.Body, "    ", "  ")
 		view += m.styles.Text(string(bodyJSON), m.styles.FooterColor) + "\n"
 	}
+	if req.RawBody != "" {
+		view += m.styles.Text("  Body:", m.styles.TitleColor) + "\n"
+		view += m.styles.Text("    "+resolver.ResolveLenient(req.RawBody), m.styles.FooterColor) + "\n"
+	}
+	if len(req.Form) > 0 {
+		view += m.styles.Text("  Form:", m.styles.TitleColor) + "\n"
+		for _, name := range sortedStringKeys(req.Form) {
+			view += m.styles.Text(fmt.Sprintf("    %s=%s", name, resolver.ResolveLenient(req.Form[name])), m.styles.FooterColor) + "\n"
+		}
+	}
 
 	view += "\n"
 	view += m.styles.Text("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━", m.styles.TitleColor) + "\n"
 	view += "\n"
 
 	// Footer with instructions
-	view += m.styles.Text("Press ENTER to execute • E to edit body • Q/ESC to cancel", m.styles.FooterColor) + "\n"
+	view += m.styles.Text("Press ENTER to execute • E to edit body • C to copy as cURL • G to generate code • Q/ESC to go back", m.styles.FooterColor) + "\n"
 
 	return view
 }
@@ -139,13 +156,3 @@ func getMethodColor(method string, styles *Styles) lipgloss.Color {
 		return styles.FooterColor
 	}
 }
-
-func RequestPreviewView(selectedRequest *RequestItem, config *ConfigJSON, secret *SecretJSON, configLoader *ConfigLoader, action *string) {
-	m := NewRequestPreviewViewModel(selectedRequest, config, secret, configLoader)
-	m.action = action
-
-	if _, err := tea.NewProgram(m).Run(); err != nil {
-		fmt.Println("RequestPreviewView -> ", err)
-		os.Exit(1)
-	}
-}
@@ -0,0 +1,214 @@
+package src
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type DiffKind string
+
+const (
+	DiffAdded   DiffKind = "added"
+	DiffRemoved DiffKind = "removed"
+	DiffChanged DiffKind = "changed"
+)
+
+// DefaultIgnoredDiffHeaders change on every response and are left out of header diffs
+var DefaultIgnoredDiffHeaders = []string{"Date"}
+
+// DiffEntry is one difference: a JSONPath for bodies, a header name for headers
+type DiffEntry struct {
+	Kind   DiffKind
+	Path   string
+	Before interface{}
+	After  interface{}
+}
+
+// ResponseDiff lists what changed between two responses of the same request
+type ResponseDiff struct {
+	StatusBefore string
+	StatusAfter  string
+	Headers      []DiffEntry
+	Body         []DiffEntry
+}
+
+func (d ResponseDiff) StatusChanged() bool {
+	return d.StatusBefore != d.StatusAfter
+}
+
+// Empty reports whether both responses are equivalent
+func (d ResponseDiff) Empty() bool {
+	return !d.StatusChanged() && len(d.Headers) == 0 && len(d.Body) == 0
+}
+
+// DiffResponses compares status, headers (except ignoredHeaders) and body.
+// JSON bodies are compared structurally, path by path; other bodies as a whole.
+func DiffResponses(before, after *HTTPResponse, ignoredHeaders []string) ResponseDiff {
+	diff := ResponseDiff{
+		StatusBefore: responseStatusLabel(before),
+		StatusAfter:  responseStatusLabel(after),
+		Headers:      diffHeaders(before.Headers, after.Headers, ignoredHeaders),
+	}
+
+	if before.IsJSON && after.IsJSON {
+		diffJSON("$", before.BodyJSON, after.BodyJSON, &diff.Body)
+	} else if before.BodyString != after.BodyString {
+		diff.Body = append(diff.Body, DiffEntry{Kind: DiffChanged, Path: "body", Before: before.BodyString, After: after.BodyString})
+	}
+
+	return diff
+}
+
+func responseStatusLabel(response *HTTPResponse) string {
+	if response.Error != nil {
+		return "error: " + response.Error.Error()
+	}
+	return response.Status
+}
+
+func diffHeaders(before, after map[string][]string, ignored []string) []DiffEntry {
+	skip := map[string]bool{}
+	for _, name := range ignored {
+		skip[http.CanonicalHeaderKey(strings.TrimSpace(name))] = true
+	}
+
+	beforeHeaders := http.Header(before)
+	afterHeaders := http.Header(after)
+
+	names := map[string]bool{}
+	for name := range beforeHeaders {
+		names[http.CanonicalHeaderKey(name)] = true
+	}
+	for name := range afterHeaders {
+		names[http.CanonicalHeaderKey(name)] = true
+	}
+
+	var sorted []string
+	for name := range names {
+		if !skip[name] {
+			sorted = append(sorted, name)
+		}
+	}
+	sort.Strings(sorted)
+
+	var entries []DiffEntry
+	for _, name := range sorted {
+		beforeValue := strings.Join(beforeHeaders.Values(name), ", ")
+		afterValue := strings.Join(afterHeaders.Values(name), ", ")
+		_, inBefore := beforeHeaders[name]
+		_, inAfter := afterHeaders[name]
+
+		switch {
+		case !inBefore:
+			entries = append(entries, DiffEntry{Kind: DiffAdded, Path: name, After: afterValue})
+		case !inAfter:
+			entries = append(entries, DiffEntry{Kind: DiffRemoved, Path: name, Before: beforeValue})
+		case beforeValue != afterValue:
+			entries = append(entries, DiffEntry{Kind: DiffChanged, Path: name, Before: beforeValue, After: afterValue})
+		}
+	}
+	return entries
+}
+
+// diffJSON walks both documents together, recording differences by JSONPath
+func diffJSON(path string, before, after interface{}, entries *[]DiffEntry) {
+	switch b := before.(type) {
+	case map[string]interface{}:
+		a, ok := after.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := map[string]bool{}
+		for key := range b {
+			keys[key] = true
+		}
+		for key := range a {
+			keys[key] = true
+		}
+		var sorted []string
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+
+		for _, key := range sorted {
+			childPath := appendJSONPathKey(path, key)
+			beforeValue, inBefore := b[key]
+			afterValue, inAfter := a[key]
+			switch {
+			case !inBefore:
+				*entries = append(*entries, DiffEntry{Kind: DiffAdded, Path: childPath, After: afterValue})
+			case !inAfter:
+				*entries = append(*entries, DiffEntry{Kind: DiffRemoved, Path: childPath, Before: beforeValue})
+			default:
+				diffJSON(childPath, beforeValue, afterValue, entries)
+			}
+		}
+		return
+
+	case []interface{}:
+		a, ok := after.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < max(len(b), len(a)); i++ {
+			childPath := appendJSONPathIndex(path, i)
+			switch {
+			case i >= len(b):
+				*entries = append(*entries, DiffEntry{Kind: DiffAdded, Path: childPath, After: a[i]})
+			case i >= len(a):
+				*entries = append(*entries, DiffEntry{Kind: DiffRemoved, Path: childPath, Before: b[i]})
+			default:
+				diffJSON(childPath, b[i], a[i], entries)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		*entries = append(*entries, DiffEntry{Kind: DiffChanged, Path: path, Before: before, After: after})
+	}
+}
+
+// renderDiffLines formats a diff for both the terminal and the TUI
+func renderDiffLines(diff ResponseDiff, styles *Styles) []responseLine {
+	var lines []responseLine
+
+	if diff.StatusChanged() {
+		lines = append(lines, responseLine{fmt.Sprintf("~ Status: %s → %s", diff.StatusBefore, diff.StatusAfter), styles.PeachColor})
+	} else {
+		lines = append(lines, responseLine{fmt.Sprintf("  Status: %s (unchanged)", diff.StatusAfter), styles.MutedTitleColor})
+	}
+	lines = append(lines, responseLine{"", styles.FooterColor})
+
+	section := func(title string, entries []DiffEntry, format func(interface{}) string) {
+		if len(entries) == 0 {
+			lines = append(lines, responseLine{fmt.Sprintf("  %s: no differences", title), styles.MutedTitleColor})
+			return
+		}
+		lines = append(lines, responseLine{fmt.Sprintf("  %s: %d difference(s)", title, len(entries)), styles.TitleColor})
+		for _, entry := range entries {
+			switch entry.Kind {
+			case DiffAdded:
+				lines = append(lines, responseLine{fmt.Sprintf("+ %s: %s", entry.Path, format(entry.After)), styles.AquamarineColor})
+			case DiffRemoved:
+				lines = append(lines, responseLine{fmt.Sprintf("- %s: %s", entry.Path, format(entry.Before)), styles.CoralColor})
+			case DiffChanged:
+				lines = append(lines, responseLine{fmt.Sprintf("~ %s: %s → %s", entry.Path, format(entry.Before), format(entry.After)), styles.PeachColor})
+			}
+		}
+	}
+
+	section("Headers", diff.Headers, func(value interface{}) string {
+		return fmt.Sprintf("%v", value)
+	})
+	lines = append(lines, responseLine{"", styles.FooterColor})
+	section("Body", diff.Body, func(value interface{}) string {
+		return truncateForDisplay(compactJSON(value), 120)
+	})
+
+	return lines
+}
@@ -0,0 +1,132 @@
+package src
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDiffJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   []DiffEntry
+	}{
+		{name: "equal documents", before: `{"a": [1, {"b": null}]}`, after: `{"a": [1, {"b": null}]}`},
+		{
+			name:   "keys in sorted order",
+			before: `{"z": 1, "b": 2, "a": 3}`,
+			after:  `{"z": 2, "c": 2, "a": 3}`,
+			want: []DiffEntry{
+				{Kind: DiffRemoved, Path: "$.b", Before: 2.0},
+				{Kind: DiffAdded, Path: "$.c", After: 2.0},
+				{Kind: DiffChanged, Path: "$.z", Before: 1.0, After: 2.0},
+			},
+		},
+		{
+			name:   "array items by index",
+			before: `{"items": [1, 2, 3]}`,
+			after:  `{"items": [1, 5]}`,
+			want: []DiffEntry{
+				{Kind: DiffChanged, Path: "$.items[1]", Before: 2.0, After: 5.0},
+				{Kind: DiffRemoved, Path: "$.items[2]", Before: 3.0},
+			},
+		},
+		{
+			name:   "nested paths and odd keys",
+			before: `{"data": {"first name": "Ada"}}`,
+			after:  `{"data": {"first name": "Grace"}}`,
+			want:   []DiffEntry{{Kind: DiffChanged, Path: "$.data['first name']", Before: "Ada", After: "Grace"}},
+		},
+		{
+			name:   "type change is one change",
+			before: `{"a": {"b": 1}}`,
+			after:  `{"a": [1]}`,
+			want:   []DiffEntry{{Kind: DiffChanged, Path: "$.a", Before: map[string]interface{}{"b": 1.0}, After: []interface{}{1.0}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []DiffEntry
+			diffJSON("$", decodeTestJSON(t, tt.before), decodeTestJSON(t, tt.after), &got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffResponses(t *testing.T) {
+	tests := []struct {
+		name           string
+		before         *HTTPResponse
+		after          *HTTPResponse
+		ignoredHeaders []string
+		want           ResponseDiff
+		empty          bool
+	}{
+		{
+			name:           "same response",
+			before:         &HTTPResponse{Status: "200 OK", Headers: map[string][]string{"Date": {"Mon"}}, BodyString: "ok"},
+			after:          &HTTPResponse{Status: "200 OK", Headers: map[string][]string{"Date": {"Tue"}}, BodyString: "ok"},
+			ignoredHeaders: DefaultIgnoredDiffHeaders,
+			want:           ResponseDiff{StatusBefore: "200 OK", StatusAfter: "200 OK"},
+			empty:          true,
+		},
+		{
+			name:           "status and headers",
+			before:         &HTTPResponse{Status: "200 OK", Headers: map[string][]string{"X-Old": {"1"}, "Vary": {"A", "B"}, "x-request-id": {"a"}}},
+			after:          &HTTPResponse{Status: "404 Not Found", Headers: map[string][]string{"X-New": {"2"}, "Vary": {"A"}, "X-Request-Id": {"b"}}},
+			ignoredHeaders: []string{" x-request-id "},
+			want: ResponseDiff{
+				StatusBefore: "200 OK",
+				StatusAfter:  "404 Not Found",
+				Headers: []DiffEntry{
+					{Kind: DiffChanged, Path: "Vary", Before: "A, B", After: "A"},
+					{Kind: DiffAdded, Path: "X-New", After: "2"},
+					{Kind: DiffRemoved, Path: "X-Old", Before: "1"},
+				},
+			},
+		},
+		{
+			name:   "JSON bodies are compared by path",
+			before: &HTTPResponse{Status: "200 OK", IsJSON: true, BodyJSON: map[string]interface{}{"id": 1.0}},
+			after:  &HTTPResponse{Status: "200 OK", IsJSON: true, BodyJSON: map[string]interface{}{"id": 2.0}},
+			want: ResponseDiff{
+				StatusBefore: "200 OK",
+				StatusAfter:  "200 OK",
+				Body:         []DiffEntry{{Kind: DiffChanged, Path: "$.id", Before: 1.0, After: 2.0}},
+			},
+		},
+		{
+			name:   "other bodies are compared as text",
+			before: &HTTPResponse{Status: "200 OK", IsJSON: true, BodyJSON: map[string]interface{}{}, BodyString: "{}"},
+			after:  &HTTPResponse{Status: "200 OK", BodyString: "<html>"},
+			want: ResponseDiff{
+				StatusBefore: "200 OK",
+				StatusAfter:  "200 OK",
+				Body:         []DiffEntry{{Kind: DiffChanged, Path: "body", Before: "{}", After: "<html>"}},
+			},
+		},
+		{
+			name:   "failed request",
+			before: &HTTPResponse{Status: "200 OK"},
+			after:  &HTTPResponse{Error: errors.New("connection refused")},
+			want:   ResponseDiff{StatusBefore: "200 OK", StatusAfter: "error: connection refused"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DiffResponses(tt.before, tt.after, tt.ignoredHeaders)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if got.Empty() != tt.empty {
+				t.Errorf("Empty() = %v, want %v", got.Empty(), tt.empty)
+			}
+		})
+	}
+}
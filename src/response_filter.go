@@ -0,0 +1,284 @@
+package src
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ApplyResponseFilter narrows a JSON document down with a filter expression.
+//
+// Expressions starting with $ are JSONPath: a definite path ($.user.name) returns the matched
+// value, any other path ($.items[*].id) returns the array of matches.
+//
+// Anything else is a jq-like pipeline supporting ., .a.b, .[n], .[], .["key"], |, [...] to
+// collect results, {a, b: .c} to build objects, length and keys. A pipeline producing a
+// single value returns it, otherwise the results are returned as an array.
+func ApplyResponseFilter(data interface{}, expression string) (interface{}, error) {
+	expression = strings.TrimSpace(expression)
+	if expression == "" {
+		return nil, fmt.Errorf("empty filter")
+	}
+
+	if strings.HasPrefix(expression, "$") {
+		segments, err := parseJSONPath(expression)
+		if err != nil {
+			return nil, err
+		}
+		values, err := EvaluateJSONPath(data, expression)
+		if err != nil {
+			return nil, err
+		}
+		if !isDefiniteJSONPath(segments) {
+			if values == nil {
+				values = []interface{}{}
+			}
+			return values, nil
+		}
+		if len(values) == 0 {
+			return nil, fmt.Errorf("no value at %s", expression)
+		}
+		return values[0], nil
+	}
+
+	values, err := evaluateJQ(data, expression)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 1 {
+		return values[0], nil
+	}
+	if values == nil {
+		values = []interface{}{}
+	}
+	return values, nil
+}
+
+// evaluateJQ runs a pipeline on one input and returns the stream of outputs
+func evaluateJQ(input interface{}, expression string) ([]interface{}, error) {
+	stages, err := splitJQ(expression, '|')
+	if err != nil {
+		return nil, err
+	}
+
+	stream := []interface{}{input}
+	for _, stage := range stages {
+		var next []interface{}
+		for _, value := range stream {
+			outputs, err := evaluateJQStage(value, strings.TrimSpace(stage))
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, outputs...)
+		}
+		stream = next
+	}
+	return stream, nil
+}
+
+func evaluateJQStage(input interface{}, stage string) ([]interface{}, error) {
+	switch {
+	case stage == "":
+		return nil, fmt.Errorf("empty filter stage")
+
+	case stage == ".":
+		return []interface{}{input}, nil
+
+	case stage == "length":
+		switch v := input.(type) {
+		case map[string]interface{}:
+			return []interface{}{float64(len(v))}, nil
+		case []interface{}:
+			return []interface{}{float64(len(v))}, nil
+		case string:
+			return []interface{}{float64(len([]rune(v)))}, nil
+		case nil:
+			return []interface{}{float64(0)}, nil
+		}
+		return nil, fmt.Errorf("length: %s has no length", jsonTypeName(input))
+
+	case stage == "keys":
+		switch v := input.(type) {
+		case map[string]interface{}:
+			keys := []interface{}{}
+			for _, key := range sortedKeys(v) {
+				keys = append(keys, key)
+			}
+			return []interface{}{keys}, nil
+		case []interface{}:
+			indices := []interface{}{}
+			for i := range v {
+				indices = append(indices, float64(i))
+			}
+			return []interface{}{indices}, nil
+		}
+		return nil, fmt.Errorf("keys: %s has no keys", jsonTypeName(input))
+
+	case strings.HasPrefix(stage, "[") && strings.HasSuffix(stage, "]"):
+		inner := strings.TrimSpace(stage[1 : len(stage)-1])
+		collected := []interface{}{}
+		if inner != "" {
+			values, err := evaluateJQ(input, inner)
+			if err != nil {
+				return nil, err
+			}
+			collected = append(collected, values...)
+		}
+		return []interface{}{collected}, nil
+
+	case strings.HasPrefix(stage, "{") && strings.HasSuffix(stage, "}"):
+		return evaluateJQObject(input, stage[1:len(stage)-1])
+
+	case strings.HasPrefix(stage, "."):
+		return evaluateJQPath(input, stage)
+	}
+
+	return nil, fmt.Errorf("unsupported filter %q", stage)
+}
+
+// evaluateJQPath translates a jq path (.a.b[0][]) into JSONPath.
+// Like jq, a missing member of a definite path yields null.
+func evaluateJQPath(input interface{}, path string) ([]interface{}, error) {
+	if strings.HasPrefix(path, "..") {
+		return nil, fmt.Errorf("unsupported filter %q", path)
+	}
+
+	jsonPath := "$" + strings.ReplaceAll(path, "[]", "[*]")
+	segments, err := parseJSONPath(jsonPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q", path)
+	}
+
+	values, err := EvaluateJSONPath(input, jsonPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 && isDefiniteJSONPath(segments) {
+		return []interface{}{nil}, nil
+	}
+	return values, nil
+}
+
+// evaluateJQObject builds objects from "key, key: filter, "quoted key": filter".
+// A field producing several values yields one object per combination, as in jq.
+func evaluateJQObject(input interface{}, body string) ([]interface{}, error) {
+	fields, err := splitJQ(body, ',')
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []map[string]interface{}{{}}
+	for _, field := range fields {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		key, expression := field, ""
+		if parts, err := splitJQ(field, ':'); err == nil && len(parts) > 1 {
+			key = strings.TrimSpace(parts[0])
+			expression = strings.TrimSpace(field[len(parts[0])+1:])
+		}
+		if len(key) >= 2 && key[0] == '"' && key[len(key)-1] == '"' {
+			key = key[1 : len(key)-1]
+		}
+		if expression == "" {
+			expression = "." + key
+			if !isJSONPathIdentifier(key) {
+				expression = `.["` + key + `"]`
+			}
+		}
+
+		values, err := evaluateJQ(input, expression)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []map[string]interface{}
+		for _, object := range objects {
+			for _, value := range values {
+				copied := make(map[string]interface{}, len(object)+1)
+				for k, v := range object {
+					copied[k] = v
+				}
+				copied[key] = value
+				next = append(next, copied)
+			}
+		}
+		objects = next
+	}
+
+	result := make([]interface{}, len(objects))
+	for i, object := range objects {
+		result[i] = object
+	}
+	return result, nil
+}
+
+// splitJQ splits an expression on a separator outside of brackets, braces, parentheses and strings
+func splitJQ(expression string, separator byte) ([]string, error) {
+	var parts []string
+	var stack []byte
+	inString := false
+	start := 0
+
+	for i := 0; i < len(expression); i++ {
+		c := expression[i]
+		if inString {
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '[', '{', '(':
+			stack = append(stack, c)
+		case ']', '}', ')':
+			open := map[byte]byte{']': '[', '}': '{', ')': '('}[c]
+			if len(stack) == 0 || stack[len(stack)-1] != open {
+				return nil, fmt.Errorf("invalid filter %q: unbalanced %q", expression, c)
+			}
+			stack = stack[:len(stack)-1]
+		case separator:
+			if len(stack) == 0 {
+				parts = append(parts, expression[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	if inString || len(stack) > 0 {
+		return nil, fmt.Errorf("invalid filter %q: unterminated expression", expression)
+	}
+	return append(parts, expression[start:]), nil
+}
+
+// filterResponse returns a copy of the response whose JSON body is replaced by the filter result
+func filterResponse(response *HTTPResponse, expression string) (*HTTPResponse, error) {
+	if response.Error != nil {
+		return response, nil
+	}
+	if !response.IsJSON {
+		return nil, fmt.Errorf("cannot filter a non-JSON response")
+	}
+
+	result, err := ApplyResponseFilter(response.BodyJSON, expression)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := ToJSON(result)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := *response
+	filtered.BodyJSON = result
+	filtered.Body = []byte(body)
+	filtered.BodyString = body
+	return &filtered, nil
+}
@@ -0,0 +1,104 @@
+package src
+
+import (
+	"reflect"
+	"testing"
+)
+
+// responseFilterTestData is the body the filter tests narrow down
+const responseFilterTestData = `{
+  "data": {
+    "user": {"id": 42, "name": "Ada", "first name": "Augusta"},
+    "items": [{"id": 1, "tags": ["a", "b"]}, {"id": 2, "tags": []}, {"id": 3, "tags": ["c"]}]
+  },
+  "count": 3
+}`
+
+func TestApplyResponseFilter(t *testing.T) {
+	data := decodeTestJSON(t, responseFilterTestData)
+
+	tests := []struct {
+		expression string
+		want       string // JSON of the result
+		wantErr    string
+	}{
+		// JSONPath: definite paths return the value, others an array of matches
+		{expression: "$.data.user.name", want: `"Ada"`},
+		{expression: "  $.count  ", want: `3`},
+		{expression: "$.data.items[-1].id", want: `3`},
+		{expression: "$.data.items[*].id", want: `[1, 2, 3]`},
+		{expression: "$.data.items[-2:].id", want: `[2, 3]`},
+		{expression: "$..tags[0]", want: `["a", "c"]`},
+		{expression: "$.data.nothing[*]", want: `[]`},
+		{expression: "$.data.nothing", wantErr: "no value at $.data.nothing"},
+		{expression: "$.data[", wantErr: `invalid JSONPath "$.data[": unclosed [ at 6`},
+
+		// jq-like pipelines
+		{expression: ".", want: responseFilterTestData},
+		{expression: ".data.user.id", want: `42`},
+		{expression: `.data.user["first name"]`, want: `"Augusta"`},
+		{expression: ".data.missing", want: `null`},
+		{expression: ".data.items[1].id", want: `2`},
+		{expression: ".data.items[-1].id", want: `3`},
+		{expression: ".data.items[].id", want: `[1, 2, 3]`},
+		{expression: ".data.items | length", want: `3`},
+		{expression: ".data.user.name | length", want: `3`},
+		{expression: ".data.user | keys", want: `["first name", "id", "name"]`},
+		{expression: ".data.items | keys", want: `[0, 1, 2]`},
+		{expression: "[.data.items[] | .tags[]]", want: `["a", "b", "c"]`},
+		{expression: "[.data.items[] | .tags[] | .nothing]", want: `[null, null, null]`},
+		{expression: "[]", want: `[]`},
+		{expression: ".data.user | {id, name}", want: `{"id": 42, "name": "Ada"}`},
+		{expression: `.data.user | {"first name", who: .name}`, want: `{"first name": "Augusta", "who": "Ada"}`},
+		{expression: ".data.items[] | {id, tag: .tags[]}", want: `[{"id": 1, "tag": "a"}, {"id": 1, "tag": "b"}, {"id": 3, "tag": "c"}]`},
+		{expression: `{label: .data.user.name, "pipe|in key": .count}`, want: `{"label": "Ada", "pipe|in key": 3}`},
+		{expression: ".count | keys", wantErr: "keys: number has no keys"},
+		{expression: ".count | length", wantErr: "length: number has no length"},
+		{expression: ".data |", wantErr: "empty filter stage"},
+		{expression: "..id", wantErr: `unsupported filter "..id"`},
+		{expression: "map(.id)", wantErr: `unsupported filter "map(.id)"`},
+		{expression: "[.data", wantErr: `invalid filter "[.data": unterminated expression`},
+		{expression: ".data]", wantErr: `invalid filter ".data]": unbalanced ']'`},
+		{expression: " ", wantErr: "empty filter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := ApplyResponseFilter(data, tt.expression)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want := decodeTestJSON(t, tt.want); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestFilterResponse(t *testing.T) {
+	response := &HTTPResponse{
+		IsJSON:   true,
+		BodyJSON: decodeTestJSON(t, responseFilterTestData),
+	}
+
+	filtered, err := filterResponse(response, ".data.user.name")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filtered.BodyString != `"Ada"` || string(filtered.Body) != `"Ada"` {
+		t.Errorf("got body %q", filtered.BodyString)
+	}
+	if response.BodyJSON.(map[string]interface{})["count"] != 3.0 {
+		t.Error("the original response was modified")
+	}
+
+	if _, err := filterResponse(&HTTPResponse{BodyString: "plain"}, "."); err == nil {
+		t.Error("expected an error for a non-JSON response")
+	}
+}
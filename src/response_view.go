@@ -0,0 +1,706 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	responseTabBody = iota
+	responseTabHeaders
+	responseTabRequest
+	responseTabTiming
+)
+
+var responseTabNames = []string{"Body", "Headers", "Request", "Timing"}
+
+// responseLine is one plain-text line of a tab, colored as a whole.
+// Keeping the text unstyled lets search match and highlight it.
+type responseLine struct {
+	text  string
+	color lipgloss.Color
+}
+
+// searchMatch is one occurrence of the query in the unwrapped text of a tab, split into the
+// parts it covers on each wrapped line
+type searchMatch struct {
+	parts []lineSpan
+}
+
+type lineSpan struct {
+	line  int
+	start int // Byte offsets in the line text
+	end   int
+}
+
+// ResponseViewModel shows a response in tabs with a pager and incremental search
+type ResponseViewModel struct {
+	item     *RequestItem
+	response *HTTPResponse
+	captures []CaptureResult
+	resolved *ResolvedRequest // Re-sent as-is with "r" when the response comes from history
+	loading  bool
+	recorded bool // Re-opened from history
+	tab      int
+	source   []responseLine // Lines of the current tab as built, searched before wrapping
+	lines    []responseLine // Lines of the current tab, wrapped to the screen width
+	offset   int
+
+	searching   bool // Typing a search query
+	searchQuery string
+	matches     []searchMatch
+	matchIndex  int
+
+	filtering   bool   // Typing a filter expression
+	filterDraft string // Expression being typed
+	filter      string // Applied JSONPath or jq-like filter, remembered per request
+	filtered    *HTTPResponse
+	filterErr   error
+
+	width  int
+	height int
+	styles *Styles
+}
+
+func NewResponseViewModel(item *RequestItem, filter string) ResponseViewModel {
+	return ResponseViewModel{
+		item:    item,
+		filter:  filter,
+		loading: true,
+		width:   80,
+		height:  24,
+		styles:  DefaultStyles(),
+	}
+}
+
+// NewHistoryResponseViewModel shows a recorded response; "r" re-sends the exact recorded request
+func NewHistoryResponseViewModel(item *RequestItem, entry HistoryEntryJSON, filter string) ResponseViewModel {
+	m := NewResponseViewModel(item, filter)
+	m.loading = false
+	m.recorded = true
+	m.response = entry.Response()
+	m.resolved = entry.Request
+	m.applyFilter()
+	return m
+}
+
+func (m ResponseViewModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m ResponseViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.refresh()
+		return m, nil
+
+	case requestExecutedMsg:
+		if msg.item != m.item {
+			return m, nil
+		}
+		m.loading = false
+		m.response = msg.response
+		m.captures = msg.captures
+		m.tab = responseTabBody
+		m.offset = 0
+		m.applyFilter()
+		m.refresh()
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.searching {
+			return m.updateSearch(msg)
+		}
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
+
+		switch msg.String() {
+		case "q":
+			return m, emit(navigateBackMsg{})
+		case "esc":
+			if m.searchQuery != "" {
+				m.clearSearch()
+				return m, nil
+			}
+			return m, emit(navigateBackMsg{})
+		case "r":
+			if m.loading {
+				return m, nil
+			}
+			if m.recorded && m.resolved == nil {
+				return m, nil // Failed before the request was built
+			}
+			return m, emit(executeRequestMsg{item: m.item, resolved: m.resolved, replace: true})
+		}
+
+		if m.loading {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "tab", "right", "l":
+			m.switchTab((m.tab + 1) % len(responseTabNames))
+		case "shift+tab", "left", "h":
+			m.switchTab((m.tab + len(responseTabNames) - 1) % len(responseTabNames))
+		case "1", "2", "3", "4":
+			m.switchTab(int(msg.String()[0] - '1'))
+		case "up", "k":
+			m.scrollTo(m.offset - 1)
+		case "down", "j":
+			m.scrollTo(m.offset + 1)
+		case "pgup", "b":
+			m.scrollTo(m.offset - m.pageHeight())
+		case "pgdown", " ":
+			m.scrollTo(m.offset + m.pageHeight())
+		case "ctrl+u":
+			m.scrollTo(m.offset - m.pageHeight()/2)
+		case "ctrl+d":
+			m.scrollTo(m.offset + m.pageHeight()/2)
+		case "g", "home":
+			m.scrollTo(0)
+		case "G", "end":
+			m.scrollTo(len(m.lines))
+		case "/":
+			m.searching = true
+			m.searchQuery = ""
+			m.findMatches()
+		case "f":
+			if m.response.Error == nil && m.response.IsJSON {
+				m.filtering = true
+				m.filterDraft = m.filter
+			}
+		case "t":
+			if response := m.displayedResponse(); response.Error == nil && response.IsJSON {
+				return m, emit(jsonTreeMsg{title: m.item.Name, data: response.BodyJSON})
+			}
+		case "d":
+			return m, emit(diffPreviousMsg{item: m.item, response: m.response})
+		case "n":
+			m.jumpToMatch(m.matchIndex + 1)
+		case "N":
+			m.jumpToMatch(m.matchIndex - 1)
+		}
+	}
+
+	return m, nil
+}
+
+func (m ResponseViewModel) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.searching = false
+		m.clearSearch()
+	case "enter":
+		m.searching = false
+		if m.searchQuery == "" {
+			m.clearSearch()
+		}
+	case "backspace":
+		if len(m.searchQuery) > 0 {
+			runes := []rune(m.searchQuery)
+			m.searchQuery = string(runes[:len(runes)-1])
+			m.findMatches()
+			m.jumpToMatch(0)
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.searchQuery += string(msg.Runes)
+			m.findMatches()
+			m.jumpToMatch(0)
+		}
+	}
+	return m, nil
+}
+
+func (m ResponseViewModel) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.filtering = false
+	case "enter":
+		m.filtering = false
+		m.filter = strings.TrimSpace(m.filterDraft)
+		m.tab = responseTabBody
+		m.offset = 0
+		m.applyFilter()
+		m.refresh()
+		return m, emit(filterChangedMsg{item: m.item, filter: m.filter})
+	case "backspace":
+		if len(m.filterDraft) > 0 {
+			runes := []rune(m.filterDraft)
+			m.filterDraft = string(runes[:len(runes)-1])
+		}
+	case "ctrl+u":
+		m.filterDraft = ""
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.filterDraft += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+func (m ResponseViewModel) View() string {
+	if m.loading {
+		var b strings.Builder
+		b.WriteString("\n")
+		b.WriteString(m.styles.Text(fmt.Sprintf("  Request: %s", m.item.Name), m.styles.SelectedTitleColor) + "\n\n")
+		b.WriteString(m.styles.Text("⏳ Executing request...", m.styles.ThistleColor) + "\n\n")
+		b.WriteString(m.styles.Text("ESC to go back", m.styles.FooterColor) + "\n")
+		return b.String()
+	}
+
+	var b strings.Builder
+
+	// Header: tabs and a one line summary
+	var tabViews []string
+	for i, name := range responseTabNames {
+		if i == m.tab {
+			tabViews = append(tabViews, m.styles.Text(fmt.Sprintf("[ %s ]", name), m.styles.SelectedTitleColor))
+		} else {
+			tabViews = append(tabViews, m.styles.Text(fmt.Sprintf("  %s  ", name), m.styles.MutedTitleColor))
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, tabViews...))
+	b.WriteString("\n")
+	b.WriteString(m.renderSummary())
+	b.WriteString("\n\n")
+
+	// Visible page
+	end := min(m.offset+m.pageHeight(), len(m.lines))
+	for i := m.offset; i < end; i++ {
+		b.WriteString(m.renderLine(i))
+		b.WriteString("\n")
+	}
+	for i := end - m.offset; i < m.pageHeight(); i++ {
+		b.WriteString("\n")
+	}
+
+	// Footer: search or filter prompt, or help
+	b.WriteString("\n")
+	if m.filtering {
+		b.WriteString(m.styles.Text("  ⧩ filter: "+m.filterDraft+"█", m.styles.SearchTextColor))
+		b.WriteString(m.styles.Text("  $.a[*].b JSONPath or .a[] | {b} jq • enter apply • esc cancel", m.styles.MutedTitleColor))
+		b.WriteString("\n")
+		return b.String()
+	}
+	if m.searching {
+		b.WriteString(m.styles.Text("  🔍 /"+m.searchQuery+"█", m.styles.SearchTextColor))
+		b.WriteString(m.styles.Text("  "+m.matchCounter(), m.styles.MutedTitleColor))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	position := "all"
+	if len(m.lines) > m.pageHeight() {
+		position = fmt.Sprintf("%d%%", min(100, (m.offset+m.pageHeight())*100/len(m.lines)))
+	}
+	helpText := fmt.Sprintf("  %s • tab switch • ↑↓/jk/pgup/pgdn scroll • / search", position)
+	if m.searchQuery != "" {
+		helpText += " • n/N " + m.matchCounter()
+	}
+	if m.response.Error == nil && m.response.IsJSON {
+		helpText += " • f filter • t tree"
+	}
+	helpText += " • d diff • r re-send • q/esc back"
+	b.WriteString(m.styles.FooterStyle.Render(helpText))
+	b.WriteString("\n")
+	return b.String()
+}
+
+func (m ResponseViewModel) renderSummary() string {
+	response := m.response
+
+	recorded := ""
+	if m.recorded {
+		recorded = m.styles.Text(" • 🕘 "+response.StartedAt.Local().Format("Jan 02 15:04:05"), m.styles.PeachColor)
+	}
+
+	if response.Error != nil {
+		return m.styles.Text("  ❌ "+formatError(response.Error), m.styles.ErrorColor) + recorded
+	}
+
+	summary := m.styles.Text(fmt.Sprintf("  %s %s", getStatusIcon(response.StatusCode), response.Status), getStatusColor(response.StatusCode, m.styles))
+	summary += m.styles.Text(fmt.Sprintf(" • %s • %s", response.Duration.Round(time.Millisecond), formatBytes(response.Size)), m.styles.MutedTitleColor)
+
+	var captured []string
+	for _, capture := range m.captures {
+		if capture.Error == nil {
+			captured = append(captured, capture.Name)
+		}
+	}
+	if len(captured) > 0 {
+		summary += m.styles.Text(" • 🔗 "+strings.Join(captured, ", "), m.styles.AquamarineColor)
+	}
+	if failed := len(m.captures) - len(captured); failed > 0 {
+		summary += m.styles.Text(fmt.Sprintf(" • %d capture(s) failed, see Timing", failed), m.styles.CoralColor)
+	}
+	if m.filter != "" && response.IsJSON {
+		color := m.styles.ThistleColor
+		if m.filterErr != nil {
+			color = m.styles.ErrorColor
+		}
+		summary += m.styles.Text(" • ⧩ "+m.filter, color)
+	}
+	return summary + recorded
+}
+
+// renderLine styles a line, highlighting search matches on it
+func (m ResponseViewModel) renderLine(index int) string {
+	line := m.lines[index]
+	style := lipgloss.NewStyle().Foreground(line.color)
+
+	if m.searchQuery == "" {
+		return style.Render(line.text)
+	}
+
+	highlight := lipgloss.NewStyle().Background(m.styles.HighlightBgColor).Foreground(m.styles.HighlightFgColor)
+	current := highlight.Background(m.styles.PeachColor).Bold(true)
+
+	var b strings.Builder
+	last := 0
+	for i, match := range m.matches {
+		for _, part := range match.parts {
+			if part.line != index {
+				continue
+			}
+			b.WriteString(style.Render(line.text[last:part.start]))
+			if i == m.matchIndex {
+				b.WriteString(current.Render(line.text[part.start:part.end]))
+			} else {
+				b.WriteString(highlight.Render(line.text[part.start:part.end]))
+			}
+			last = part.end
+		}
+	}
+	b.WriteString(style.Render(line.text[last:]))
+	return b.String()
+}
+
+func (m ResponseViewModel) matchCounter() string {
+	if m.searchQuery == "" {
+		return ""
+	}
+	if len(m.matches) == 0 {
+		return "(no matches)"
+	}
+	return fmt.Sprintf("(%d/%d)", m.matchIndex+1, len(m.matches))
+}
+
+// pageHeight is the number of content lines that fit between header and footer
+func (m ResponseViewModel) pageHeight() int {
+	return max(m.height-7, 3)
+}
+
+func (m *ResponseViewModel) scrollTo(offset int) {
+	m.offset = max(0, min(offset, len(m.lines)-m.pageHeight()))
+}
+
+func (m *ResponseViewModel) switchTab(tab int) {
+	if tab == m.tab {
+		return
+	}
+	m.tab = tab
+	m.offset = 0
+	m.refresh()
+	m.jumpToMatch(0)
+}
+
+// refresh rebuilds the lines of the current tab, e.g. after a resize
+func (m *ResponseViewModel) refresh() {
+	if m.response == nil {
+		return
+	}
+	m.source = m.tabLines()
+	m.lines = wrapResponseLines(m.source, m.wrapWidth())
+	m.findMatches()
+	m.scrollTo(m.offset)
+}
+
+// applyFilter recomputes the filtered response for the current filter
+func (m *ResponseViewModel) applyFilter() {
+	m.filtered = nil
+	m.filterErr = nil
+	if m.filter == "" || m.response == nil || m.response.Error != nil || !m.response.IsJSON {
+		return
+	}
+	m.filtered, m.filterErr = filterResponse(m.response, m.filter)
+}
+
+// displayedResponse is the filtered response when a filter applies, the original one otherwise
+func (m ResponseViewModel) displayedResponse() *HTTPResponse {
+	if m.filtered != nil {
+		return m.filtered
+	}
+	return m.response
+}
+
+func (m *ResponseViewModel) clearSearch() {
+	m.searchQuery = ""
+	m.matches = nil
+	m.matchIndex = 0
+}
+
+func (m ResponseViewModel) wrapWidth() int {
+	return max(m.width-1, 20)
+}
+
+// findMatches searches the current tab, ignoring ASCII case. Lines are searched before wrapping,
+// so a match split across wrapped lines is still found and highlighted on each of them.
+func (m *ResponseViewModel) findMatches() {
+	m.matches = nil
+	m.matchIndex = 0
+	if m.searchQuery == "" {
+		return
+	}
+
+	query := lowerASCII(m.searchQuery)
+	first := 0 // Wrapped line where the current source line starts
+	for _, line := range m.source {
+		offsets := wrapOffsets(line.text, m.wrapWidth())
+		text := lowerASCII(line.text)
+		for start := 0; ; {
+			idx := strings.Index(text[start:], query)
+			if idx < 0 {
+				break
+			}
+			matchStart, matchEnd := start+idx, start+idx+len(query)
+			var match searchMatch
+			for k := 0; k+1 < len(offsets); k++ {
+				from, to := max(matchStart, offsets[k]), min(matchEnd, offsets[k+1])
+				if from < to {
+					match.parts = append(match.parts, lineSpan{line: first + k, start: from - offsets[k], end: to - offsets[k]})
+				}
+			}
+			m.matches = append(m.matches, match)
+			start = matchEnd
+		}
+		first += len(offsets) - 1
+	}
+}
+
+// jumpToMatch selects a match (wrapping around) and scrolls it into view
+func (m *ResponseViewModel) jumpToMatch(index int) {
+	if len(m.matches) == 0 {
+		return
+	}
+	m.matchIndex = (index%len(m.matches) + len(m.matches)) % len(m.matches)
+
+	line := m.matches[m.matchIndex].parts[0].line
+	if line < m.offset || line >= m.offset+m.pageHeight() {
+		m.scrollTo(line - m.pageHeight()/3)
+	}
+}
+
+func (m ResponseViewModel) tabLines() []responseLine {
+	switch m.tab {
+	case responseTabHeaders:
+		return m.headerLines()
+	case responseTabRequest:
+		return m.requestLines()
+	case responseTabTiming:
+		return m.timingLines()
+	default:
+		return m.bodyLines()
+	}
+}
+
+func (m ResponseViewModel) bodyLines() []responseLine {
+	response := m.displayedResponse()
+
+	var lines []responseLine
+	if m.filterErr != nil {
+		lines = append(lines,
+			responseLine{"⚠️  Filter failed: " + m.filterErr.Error(), m.styles.ErrorColor},
+			responseLine{"", m.styles.FooterColor},
+		)
+	}
+
+	if response.Error != nil {
+		lines = append(lines,
+			responseLine{"❌ Error:", m.styles.ErrorColor},
+			responseLine{"  " + formatError(response.Error), m.styles.CoralColor},
+		)
+		if details := response.Error.Error(); details != formatError(response.Error) {
+			lines = append(lines, responseLine{"  " + details, m.styles.MutedTitleColor})
+		}
+		return lines
+	}
+	if len(response.Body) == 0 {
+		return []responseLine{{"(empty body)", m.styles.MutedTitleColor}}
+	}
+
+	body := response.BodyString
+	if response.IsJSON {
+		prettyJSON, _ := json.MarshalIndent(response.BodyJSON, "", "  ")
+		body = string(prettyJSON)
+	}
+	return append(lines, textLines(body, m.styles.FooterColor)...)
+}
+
+func (m ResponseViewModel) headerLines() []responseLine {
+	if m.response.Error != nil || len(m.response.Headers) == 0 {
+		return []responseLine{{"(no response headers)", m.styles.MutedTitleColor}}
+	}
+	return headerLines(m.response.Headers, m.styles)
+}
+
+func (m ResponseViewModel) requestLines() []responseLine {
+	request := m.response.Request
+	if request == nil {
+		return []responseLine{{"(the request could not be built)", m.styles.MutedTitleColor}}
+	}
+
+	lines := []responseLine{
+		{fmt.Sprintf("%s %s", request.Method, request.URL), m.styles.SelectedTitleColor},
+		{"", m.styles.FooterColor},
+	}
+	lines = append(lines, headerLines(request.Headers, m.styles)...)
+
+	if len(request.Form) > 0 {
+		lines = append(lines, responseLine{"", m.styles.FooterColor})
+		for _, name := range sortedStringKeys(request.Form) {
+			lines = append(lines, responseLine{name + "=" + request.Form[name], m.styles.FooterColor})
+		}
+	} else if request.RawBody != "" {
+		lines = append(lines, responseLine{"", m.styles.FooterColor})
+		lines = append(lines, textLines(request.RawBody, m.styles.FooterColor)...)
+	} else if request.Body != nil {
+		prettyJSON, _ := json.MarshalIndent(request.Body, "", "  ")
+		lines = append(lines, responseLine{"", m.styles.FooterColor})
+		lines = append(lines, textLines(string(prettyJSON), m.styles.FooterColor)...)
+	}
+	return lines
+}
+
+func (m ResponseViewModel) timingLines() []responseLine {
+	response := m.response
+	timing := response.Timing
+
+	total := timing.Total
+	if total == 0 {
+		total = response.Duration
+	}
+
+	phases := []struct {
+		label    string
+		duration time.Duration
+	}{
+		{"DNS lookup", timing.DNS},
+		{"TCP connect", timing.Connect},
+		{"TLS handshake", timing.TLSHandshake},
+		{"Time to first byte", timing.FirstByte},
+		{"Download", timing.Download},
+	}
+
+	lines := []responseLine{
+		{fmt.Sprintf("Started   %s", response.StartedAt.Local().Format("2006-01-02 15:04:05.000")), m.styles.MutedTitleColor},
+		{"", m.styles.FooterColor},
+	}
+
+	// History only keeps the total duration
+	if m.recorded {
+		phases = nil
+	}
+
+	const barWidth = 40
+	for _, phase := range phases {
+		bar := ""
+		if total > 0 && phase.duration > 0 {
+			bar = strings.Repeat("█", max(1, int(int64(phase.duration)*barWidth/int64(total))))
+		}
+		lines = append(lines, responseLine{
+			fmt.Sprintf("%-20s %10s  %s", phase.label, phase.duration.Round(time.Microsecond), bar),
+			m.styles.ThistleColor,
+		})
+	}
+	lines = append(lines, responseLine{
+		fmt.Sprintf("%-20s %10s", "Total", total.Round(time.Microsecond)),
+		m.styles.AquamarineColor,
+	})
+
+	if len(m.captures) > 0 {
+		lines = append(lines, responseLine{"", m.styles.FooterColor}, responseLine{"Captures:", m.styles.TitleColor})
+		for _, capture := range m.captures {
+			if capture.Error != nil {
+				lines = append(lines, responseLine{fmt.Sprintf("  ✗ %s ← %s: %v", capture.Name, capture.From, capture.Error), m.styles.CoralColor})
+			} else {
+				lines = append(lines, responseLine{fmt.Sprintf("  ✓ %s ← %s", capture.Name, capture.From), m.styles.AquamarineColor})
+			}
+		}
+	}
+	return lines
+}
+
+func headerLines(headers map[string][]string, styles *Styles) []responseLine {
+	var lines []responseLine
+	keys := make([]string, 0, len(headers))
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, value := range headers[key] {
+			lines = append(lines, responseLine{fmt.Sprintf("%s: %s", key, value), styles.FooterColor})
+		}
+	}
+	return lines
+}
+
+func textLines(text string, color lipgloss.Color) []responseLine {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\t", "    ")
+
+	var lines []responseLine
+	for _, line := range strings.Split(text, "\n") {
+		lines = append(lines, responseLine{line, color})
+	}
+	return lines
+}
+
+// wrapResponseLines splits lines longer than width so the pager never relies on terminal wrapping
+func wrapResponseLines(lines []responseLine, width int) []responseLine {
+	var wrapped []responseLine
+	for _, line := range lines {
+		offsets := wrapOffsets(line.text, width)
+		for k := 0; k+1 < len(offsets); k++ {
+			wrapped = append(wrapped, responseLine{line.text[offsets[k]:offsets[k+1]], line.color})
+		}
+	}
+	return wrapped
+}
+
+// wrapOffsets returns the byte offsets where a line is split every width runes, followed by
+// its length: a line that fits gives [0, len]
+func wrapOffsets(text string, width int) []int {
+	offsets := []int{0}
+	count := 0
+	for i := range text {
+		if count > 0 && count%width == 0 {
+			offsets = append(offsets, i)
+		}
+		count++
+	}
+	return append(offsets, len(text))
+}
+
+// lowerASCII lowercases ASCII letters only, so byte offsets still match the original text
+func lowerASCII(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + ('a' - 'A')
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,85 @@
+package src
+
+import (
+	"fmt"
+	"os"
+)
+
+// runCommand implements "postless run <request>"
+func (r *Runner) runCommand(args []string) int {
+	fs := newCommandFlagSet("run", "run <collection>/<request>|<file> [flags]")
+	raw := fs.Bool("raw", false, "print only the response body")
+	okStatus := fs.String("ok-status", "2xx", "comma separated statuses accepted as success, e.g. 2xx,304")
+	environment := fs.String("env", "", "environment to use instead of the active one")
+	filter := fs.String("filter", "", "JSONPath ($.items[*].id) or jq-like (.items[] | {id}) filter applied to the JSON body")
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return ExitCodeError
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return ExitCodeError
+	}
+
+	matcher, err := parseStatusMatcher(*okStatus)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --ok-status: %v\n", err)
+		return ExitCodeError
+	}
+
+	collections, err := r.loadProjectForCommand(*environment)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+
+	item, err := r.findRequest(collections, positional[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+
+	httpClient := NewHTTPClient(r.config, r.secret, r.configLoader)
+	response, _ := httpClient.ExecuteRequest(item.Request, item.Variables)
+	r.recordHistory(item, response)
+
+	captures, captureErr := r.configLoader.ApplyCaptures(item.Request.Captures, response, r.secret)
+
+	// Captures always read the full response, the filter only changes what is printed
+	displayed := response
+	if *filter != "" {
+		displayed, err = filterResponse(response, *filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Filter failed: %v\n", err)
+			return ExitCodeError
+		}
+	}
+
+	if *raw {
+		if response.Error != nil {
+			fmt.Fprintln(os.Stderr, formatError(response.Error))
+		} else {
+			writeRawResponse(os.Stdout, displayed)
+		}
+	} else {
+		r.printResponse(displayed, item.Name)
+		r.printCaptures(captures)
+	}
+
+	if captureErr != nil {
+		fmt.Fprintln(os.Stderr, "Failed to save captures:", captureErr)
+	}
+
+	if response.Error != nil {
+		return ExitCodeError
+	}
+	if !matcher.Match(response.StatusCode) {
+		if *raw {
+			fmt.Fprintf(os.Stderr, "Unexpected status %s\n", response.Status)
+		}
+		return ExitCodeFailure
+	}
+
+	return ExitCodeOK
+}
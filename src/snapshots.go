@@ -0,0 +1,181 @@
+package src
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	SnapshotCreated  = "created"
+	SnapshotUpdated  = "updated"
+	SnapshotMatched  = "matched"
+	SnapshotMismatch = "mismatch"
+
+	// Stored instead of ignored values so snapshots do not change on every run
+	snapshotIgnoredValue = "<ignored>"
+)
+
+// SnapshotJSON enables golden-file checks of a request's response body.
+// In request files it is either a boolean or {"ignore": ["$.createdAt", "$.items[*].id"]},
+// where "disabled": true keeps the ignored paths of a snapshot that is turned off.
+type SnapshotJSON struct {
+	Ignore   []string `json:"ignore,omitempty"`   // JSONPaths whose values are not compared
+	Disabled bool     `json:"disabled,omitempty"` // "snapshot": false, excluded from --snapshots
+}
+
+// UnmarshalJSON accepts the boolean form as well as the object form
+func (s *SnapshotJSON) UnmarshalJSON(data []byte) error {
+	var enabled bool
+	if err := json.Unmarshal(data, &enabled); err == nil {
+		*s = SnapshotJSON{Disabled: !enabled}
+		return nil
+	}
+
+	type snapshotAlias SnapshotJSON
+	var alias snapshotAlias
+	if err := json.Unmarshal(data, &alias); err != nil {
+		return fmt.Errorf("snapshot must be a boolean or an object with \"ignore\": %v", err)
+	}
+	*s = SnapshotJSON(alias)
+	return nil
+}
+
+// MarshalJSON writes the boolean form back when there is nothing to ignore, and the object
+// form with both fields otherwise
+func (s SnapshotJSON) MarshalJSON() ([]byte, error) {
+	if len(s.Ignore) == 0 {
+		return json.Marshal(!s.Disabled)
+	}
+	type snapshotAlias SnapshotJSON
+	return json.Marshal(snapshotAlias(s))
+}
+
+// SnapshotResult is the outcome of comparing a response with its stored snapshot
+type SnapshotResult struct {
+	Path   string
+	Status string      // SnapshotCreated, SnapshotUpdated, SnapshotMatched or SnapshotMismatch
+	Diff   []DiffEntry // Differences from the snapshot, for SnapshotMismatch
+}
+
+// SnapshotPath returns where the snapshot of a request is stored: login.json -> login.snap.json,
+// and auth.http#login -> auth.login.snap.json for requests of .http files
+func SnapshotPath(requestPath string) string {
+	filePath, section, found := strings.Cut(requestPath, "#")
+	base := strings.TrimSuffix(filePath, filepath.Ext(filePath))
+	if found {
+		base += "." + section
+	}
+	return base + SnapshotFileSuffix
+}
+
+// snapshotEnabled reports whether a request is checked against a snapshot.
+// With all set (--snapshots), every request is checked unless it opts out with "snapshot": false.
+func snapshotEnabled(request *RequestJSON, all bool) bool {
+	if request.Snapshot != nil {
+		return !request.Snapshot.Disabled
+	}
+	return all
+}
+
+// CheckSnapshot compares a response body with the request's snapshot.
+// The snapshot is written when it does not exist yet, or when update is set.
+func (cl *ConfigLoader) CheckSnapshot(config *ConfigJSON, item *RequestItem, response *HTTPResponse, update bool) (*SnapshotResult, error) {
+	ignore := append([]string{}, config.SnapshotIgnore...)
+	if item.Request.Snapshot != nil {
+		ignore = append(ignore, item.Request.Snapshot.Ignore...)
+	}
+
+	actual, err := maskSnapshotBody(snapshotBody(response), ignore)
+	if err != nil {
+		return nil, fmt.Errorf("CheckSnapshot -> %v", err)
+	}
+
+	result := &SnapshotResult{Path: SnapshotPath(item.RequestPath())}
+
+	exists, err := cl.fileManager.CheckIfPathExists(result.Path)
+	if err != nil {
+		return nil, fmt.Errorf("CheckSnapshot -> %v", err)
+	}
+
+	if exists {
+		content, err := cl.fileManager.ReadFileContent(result.Path)
+		if err != nil {
+			return nil, fmt.Errorf("CheckSnapshot -> %v", err)
+		}
+		var stored interface{}
+		if err := json.Unmarshal([]byte(content), &stored); err != nil {
+			return nil, fmt.Errorf("CheckSnapshot -> invalid snapshot %s: %v", result.Path, err)
+		}
+		// Mask again in case paths were added to the ignore list since the snapshot was taken
+		expected, err := maskSnapshotBody(stored, ignore)
+		if err != nil {
+			return nil, fmt.Errorf("CheckSnapshot -> %v", err)
+		}
+
+		diffJSON("$", expected, actual, &result.Diff)
+		if len(result.Diff) == 0 {
+			result.Status = SnapshotMatched
+			return result, nil
+		}
+		if !update {
+			result.Status = SnapshotMismatch
+			return result, nil
+		}
+	}
+
+	content, err := ToJSON(actual)
+	if err != nil {
+		return nil, fmt.Errorf("CheckSnapshot -> %v", err)
+	}
+	if err := cl.fileManager.WriteFileContent(result.Path, content+"\n"); err != nil {
+		return nil, fmt.Errorf("CheckSnapshot -> %v", err)
+	}
+
+	result.Status = SnapshotCreated
+	if exists {
+		result.Status = SnapshotUpdated
+	}
+	result.Diff = nil
+	return result, nil
+}
+
+// snapshotBody is the JSON body, or the raw text for other content types
+func snapshotBody(response *HTTPResponse) interface{} {
+	if response.IsJSON {
+		return response.BodyJSON
+	}
+	return response.BodyString
+}
+
+func maskSnapshotBody(body interface{}, ignore []string) (interface{}, error) {
+	for _, path := range ignore {
+		masked, err := ReplaceJSONPath(body, path, snapshotIgnoredValue)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot ignore: %v", err)
+		}
+		body = masked
+	}
+	return body, nil
+}
+
+// snapshotAssertions reports each difference from the snapshot as a failed assertion
+func snapshotAssertions(result *SnapshotResult) []AssertionResult {
+	var assertions []AssertionResult
+	for _, entry := range result.Diff {
+		assertion := AssertionResult{
+			Description: fmt.Sprintf("snapshot %s %s", entry.Path, entry.Kind),
+			Expected:    "(absent)",
+			Actual:      "(absent)",
+		}
+		if entry.Kind != DiffAdded {
+			assertion.Expected = truncateForDisplay(compactJSON(entry.Before), 200)
+		}
+		if entry.Kind != DiffRemoved {
+			assertion.Actual = truncateForDisplay(compactJSON(entry.After), 200)
+		}
+		assertions = append(assertions, assertion)
+	}
+	return assertions
+}
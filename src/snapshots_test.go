@@ -0,0 +1,179 @@
+package src
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// newTestConfigLoader returns a config loader working in a project in a temporary directory
+func newTestConfigLoader(t *testing.T) (*ConfigLoader, *FileManager) {
+	t.Helper()
+	dir := t.TempDir()
+	postlessDir := filepath.Join(dir, PostlessDirName)
+	fm := &FileManager{
+		CurrentDir:        dir,
+		PostlessDir:       postlessDir,
+		ConfigPath:        filepath.Join(postlessDir, ConfigFileName),
+		SecretPath:        filepath.Join(postlessDir, SecretFileName),
+		StatePath:         filepath.Join(postlessDir, StateFileName),
+		HistoryPath:       filepath.Join(postlessDir, HistoryFileName),
+		RequestsDir:       filepath.Join(postlessDir, RequestsDirName),
+		PostlessDirExists: true,
+	}
+	if err := os.MkdirAll(fm.RequestsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return NewConfigLoader(fm), fm
+}
+
+func TestSnapshotJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    SnapshotJSON
+		output  string // Marshaled back
+		wantErr bool
+	}{
+		{name: "true", input: `true`, want: SnapshotJSON{}, output: `true`},
+		{name: "false", input: `false`, want: SnapshotJSON{Disabled: true}, output: `false`},
+		{name: "ignored paths", input: `{"ignore": ["$.id"]}`, want: SnapshotJSON{Ignore: []string{"$.id"}}, output: `{"ignore":["$.id"]}`},
+		{
+			name:   "disabled with ignored paths keeps both",
+			input:  `{"ignore": ["$.id"], "disabled": true}`,
+			want:   SnapshotJSON{Ignore: []string{"$.id"}, Disabled: true},
+			output: `{"ignore":["$.id"],"disabled":true}`,
+		},
+		{name: "empty object", input: `{}`, want: SnapshotJSON{}, output: `true`},
+		{name: "string", input: `"yes"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got SnapshotJSON
+			err := json.Unmarshal([]byte(tt.input), &got)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			output, err := json.Marshal(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(output) != tt.output {
+				t.Errorf("marshaled %s, want %s", output, tt.output)
+			}
+		})
+	}
+}
+
+func TestSnapshotPath(t *testing.T) {
+	tests := []struct {
+		requestPath string
+		want        string
+	}{
+		{"requests/auth/login.json", "requests/auth/login.snap.json"},
+		{"requests/auth/login.yaml", "requests/auth/login.snap.json"},
+		{"requests/auth/auth.http#login", "requests/auth/auth.login.snap.json"},
+	}
+
+	for _, tt := range tests {
+		if got := SnapshotPath(tt.requestPath); got != tt.want {
+			t.Errorf("SnapshotPath(%q) = %q, want %q", tt.requestPath, got, tt.want)
+		}
+	}
+}
+
+func TestSnapshotEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot *SnapshotJSON
+		all      bool
+		want     bool
+	}{
+		{name: "no setting", want: false},
+		{name: "no setting with --snapshots", all: true, want: true},
+		{name: "enabled", snapshot: &SnapshotJSON{}, want: true},
+		{name: "disabled with --snapshots", snapshot: &SnapshotJSON{Disabled: true}, all: true, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snapshotEnabled(&RequestJSON{Snapshot: tt.snapshot}, tt.all); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSnapshot(t *testing.T) {
+	cl, fm := newTestConfigLoader(t)
+	dir, err := fm.CreateCollectionDir("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ConfigJSON{SnapshotIgnore: []string{"$.requestId"}}
+	item := &RequestItem{
+		FilePath: filepath.Join(dir, "get-user.json"),
+		Request:  &RequestJSON{Snapshot: &SnapshotJSON{Ignore: []string{"$.updatedAt"}}},
+	}
+
+	// Steps run in order against the same snapshot file
+	tests := []struct {
+		name   string
+		body   string
+		update bool
+		want   string
+		diff   []DiffEntry
+	}{
+		{name: "first run creates the snapshot", body: `{"id": 1, "name": "Ada", "updatedAt": 1, "requestId": "a"}`, want: SnapshotCreated},
+		{name: "ignored values may change", body: `{"id": 1, "name": "Ada", "updatedAt": 2, "requestId": "b"}`, want: SnapshotMatched},
+		{
+			name: "differences are reported",
+			body: `{"id": 1, "name": "Grace", "updatedAt": 3, "team": "x"}`,
+			want: SnapshotMismatch,
+			diff: []DiffEntry{
+				{Kind: DiffChanged, Path: "$.name", Before: "Ada", After: "Grace"},
+				{Kind: DiffRemoved, Path: "$.requestId", Before: snapshotIgnoredValue},
+				{Kind: DiffAdded, Path: "$.team", After: "x"},
+			},
+		},
+		{name: "a mismatch leaves the snapshot alone", body: `{"id": 1, "name": "Ada", "requestId": "c", "updatedAt": 4}`, want: SnapshotMatched},
+		{name: "update rewrites it", body: `{"id": 2}`, update: true, want: SnapshotUpdated},
+		{name: "the updated snapshot is used", body: `{"id": 2}`, want: SnapshotMatched},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &HTTPResponse{IsJSON: true, BodyJSON: decodeTestJSON(t, tt.body)}
+			result, err := cl.CheckSnapshot(config, item, response, tt.update)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Status != tt.want {
+				t.Errorf("got status %s, want %s", result.Status, tt.want)
+			}
+			if !reflect.DeepEqual(result.Diff, tt.diff) {
+				t.Errorf("got diff %+v, want %+v", result.Diff, tt.diff)
+			}
+		})
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "get-user.snap.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(content); got != "{\n  \"id\": 2\n}\n" {
+		t.Errorf("got snapshot file %q", got)
+	}
+}
@@ -0,0 +1,181 @@
+package src
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// testCommand implements "postless test [collection...]"
+func (r *Runner) testCommand(args []string) int {
+	fs := newCommandFlagSet("test", "test [collection...] [flags]")
+	environment := fs.String("env", "", "environment to use instead of the active one")
+	reporter := fs.String("reporter", "", "report format: junit, tap or json (default: inferred from --report-file)")
+	reportFile := fs.String("report-file", "", "write a machine-readable report to this file")
+	snapshots := fs.Bool("snapshots", false, "check every response body against its snapshot, creating missing ones")
+	updateSnapshots := fs.Bool("update-snapshots", false, "rewrite snapshots with the current responses instead of failing")
+
+	positional, err := parseCommandFlags(fs, args)
+	if err != nil {
+		return ExitCodeError
+	}
+
+	reportFormat := *reporter
+	if reportFormat == "" && *reportFile != "" {
+		reportFormat = InferReportFormat(*reportFile)
+	}
+	if *reportFile != "" && reportFormat == "" {
+		fmt.Fprintln(os.Stderr, "Cannot infer the report format, use --reporter junit|tap|json")
+		return ExitCodeError
+	}
+	if reportFormat != "" && *reportFile == "" {
+		fmt.Fprintln(os.Stderr, "--reporter requires --report-file")
+		return ExitCodeError
+	}
+
+	collections, err := r.loadProjectForCommand(*environment)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+
+	selected, err := selectCollections(collections, positional)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return ExitCodeError
+	}
+
+	styles := DefaultStyles()
+	fmt.Println()
+	fmt.Println(styles.Text(fmt.Sprintf("  Running tests (env: %s)", r.configLoader.GetEnvironmentLabel(r.config)), styles.SelectedTitleColor))
+	fmt.Println()
+
+	currentCollection, currentFolder := "", ""
+	options := TestOptions{Snapshots: *snapshots, UpdateSnapshots: *updateSnapshots}
+	run := r.runCollectionTests(selected, options, func(result RequestRunResult) {
+		if result.Collection != currentCollection {
+			currentCollection, currentFolder = result.Collection, ""
+			fmt.Println(styles.Text("  "+currentCollection, styles.TitleColor))
+		}
+		if result.Folder != currentFolder {
+			currentFolder = result.Folder
+			fmt.Println(styles.Text("    📁 "+currentFolder, styles.ThistleColor))
+		}
+		r.printTestResult(result)
+	})
+
+	r.printTestSummary(run)
+
+	if *reportFile != "" {
+		var report bytes.Buffer
+		if err := WriteTestReport(&report, reportFormat, run); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return ExitCodeError
+		}
+		if err := r.fileManager.WriteFileContent(*reportFile, report.String()); err != nil {
+			fmt.Fprintln(os.Stderr, "Failed to write report:", err)
+			return ExitCodeError
+		}
+		fmt.Println(styles.Text(fmt.Sprintf("  📝 %s report written to %s", reportFormat, *reportFile), styles.MutedTitleColor))
+		fmt.Println()
+	}
+
+	if !run.Passed() {
+		return ExitCodeFailure
+	}
+	return ExitCodeOK
+}
+
+// selectCollections filters collections by name, keeping all of them when no name is given.
+// "collection/folder" selects the requests of a folder, subfolders included.
+func selectCollections(collections []Collection, names []string) ([]Collection, error) {
+	if len(names) == 0 {
+		return collections, nil
+	}
+
+	var selected []Collection
+	for _, name := range names {
+		collectionName, folderPath, _ := strings.Cut(name, "/")
+		found := false
+		for _, collection := range collections {
+			if collection.Name != collectionName {
+				continue
+			}
+			if strings.Trim(folderPath, "/") != "" {
+				folder := collection.Root.Find(folderPath)
+				if folder == nil {
+					return nil, fmt.Errorf("folder %q not found in collection %q", folderPath, collectionName)
+				}
+				collection.Path = filepath.Join(collection.Path, filepath.FromSlash(folder.Path))
+				collection.Root = *folder
+				collection.Requests = folder.AllRequests()
+			}
+			selected = append(selected, collection)
+			found = true
+			break
+		}
+		if !found {
+			return nil, fmt.Errorf("collection %q not found", collectionName)
+		}
+	}
+	return selected, nil
+}
+
+func (r *Runner) printTestResult(result RequestRunResult) {
+	styles := DefaultStyles()
+
+	duration := result.Duration.Round(time.Millisecond)
+
+	if result.Error != "" {
+		fmt.Println(styles.Text(fmt.Sprintf("    ✗ %s  %s %s", result.Name, result.Method, result.URL), styles.ErrorColor))
+		fmt.Println(styles.Text("        "+result.Error, styles.CoralColor))
+		return
+	}
+
+	line := fmt.Sprintf("%s  %s %d (%s)", result.Name, result.Method, result.StatusCode, duration)
+	if result.Snapshot != nil && result.Snapshot.Status != SnapshotMismatch {
+		line += "  📸 snapshot " + result.Snapshot.Status
+	}
+	if result.Passed() {
+		fmt.Println(styles.Text("    ✓ "+line, styles.AquamarineColor))
+		return
+	}
+
+	fmt.Println(styles.Text("    ✗ "+line, styles.CoralColor))
+	for _, assertion := range result.Assertions {
+		if assertion.Passed {
+			continue
+		}
+		fmt.Println(styles.Text(fmt.Sprintf("        ✗ %s", assertion.Description), styles.CoralColor))
+		fmt.Println(styles.Text(fmt.Sprintf("            expected: %s", assertion.Expected), styles.FooterColor))
+		fmt.Println(styles.Text(fmt.Sprintf("            actual:   %s", assertion.Actual), styles.MutedTitleColor))
+	}
+	if result.Snapshot != nil && result.Snapshot.Status == SnapshotMismatch {
+		fmt.Println(styles.Text(fmt.Sprintf("        📸 response differs from %s, run with --update-snapshots to accept it", result.Snapshot.Path), styles.MutedTitleColor))
+	}
+}
+
+func (r *Runner) printTestSummary(run *TestRunResult) {
+	styles := DefaultStyles()
+	passed, failed, errored := run.Counts()
+
+	assertionCount := 0
+	for _, result := range run.Results {
+		assertionCount += len(result.Assertions)
+	}
+
+	color := styles.AquamarineColor
+	if !run.Passed() {
+		color = styles.ErrorColor
+	}
+
+	fmt.Println()
+	fmt.Println(styles.Text("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━", styles.TitleColor))
+	fmt.Println(styles.Text(fmt.Sprintf("  %d passed • %d failed • %d errors", passed, failed, errored), color))
+	fmt.Println(styles.Text(fmt.Sprintf("  %d requests • %d assertions • %s", len(run.Results), assertionCount, run.Duration.Round(time.Millisecond)), styles.MutedTitleColor))
+	fmt.Println(styles.Text("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━", styles.TitleColor))
+	fmt.Println()
+}
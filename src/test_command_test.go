@@ -0,0 +1,101 @@
+package src
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSelectCollections(t *testing.T) {
+	users := Collection{Name: "users", Path: filepath.Join("requests", "users")}
+	for _, item := range []RequestItem{
+		{Name: "list"},
+		{Name: "get-admin", Folder: "admin"},
+		{Name: "get-role", Folder: "admin/roles"},
+		{Name: "export", Folder: "reports"},
+	} {
+		users.Root.add(item)
+		users.Requests = append(users.Requests, item)
+	}
+	orders := Collection{Name: "orders", Path: filepath.Join("requests", "orders"), Requests: []RequestItem{{Name: "create"}}}
+	orders.Root.add(orders.Requests[0])
+	collections := []Collection{users, orders}
+
+	type selection struct {
+		Name     string
+		Path     string
+		Requests []string
+	}
+
+	tests := []struct {
+		name    string
+		names   []string
+		want    []selection
+		wantErr string
+	}{
+		{
+			name: "every collection without names",
+			want: []selection{
+				{Name: "users", Path: "requests/users", Requests: []string{"list", "get-admin", "get-role", "export"}},
+				{Name: "orders", Path: "requests/orders", Requests: []string{"create"}},
+			},
+		},
+		{
+			name:  "collections in the order given",
+			names: []string{"orders", "users"},
+			want: []selection{
+				{Name: "orders", Path: "requests/orders", Requests: []string{"create"}},
+				{Name: "users", Path: "requests/users", Requests: []string{"list", "get-admin", "get-role", "export"}},
+			},
+		},
+		{
+			name:  "a folder with its subfolders",
+			names: []string{"users/admin"},
+			want:  []selection{{Name: "users", Path: "requests/users/admin", Requests: []string{"get-admin", "get-role"}}},
+		},
+		{
+			name:  "a nested folder and a trailing slash",
+			names: []string{"users/admin/roles/", "users/"},
+			want: []selection{
+				{Name: "users", Path: "requests/users/admin/roles", Requests: []string{"get-role"}},
+				{Name: "users", Path: "requests/users", Requests: []string{"list", "get-admin", "get-role", "export"}},
+			},
+		},
+		{name: "unknown folder", names: []string{"users/roles"}, wantErr: `folder "roles" not found in collection "users"`},
+		{name: "unknown collection", names: []string{"users", "payments/refunds"}, wantErr: `collection "payments" not found`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := selectCollections(collections, tt.names)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []selection
+			for _, collection := range selected {
+				summary := selection{Name: collection.Name, Path: filepath.ToSlash(collection.Path)}
+				for _, item := range collection.Requests {
+					summary.Requests = append(summary.Requests, item.Name)
+				}
+				if names := folderTestNames(&collection.Root); !reflect.DeepEqual(names, summary.Requests) {
+					t.Errorf("%s: root holds %q, requests are %q", collection.Name, names, summary.Requests)
+				}
+				got = append(got, summary)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if len(users.Requests) != 4 || users.Path != filepath.Join("requests", "users") {
+		t.Error("selecting a folder modified the collection")
+	}
+}
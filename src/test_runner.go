@@ -0,0 +1,135 @@
+package src
+
+import (
+	"fmt"
+	"time"
+)
+
+// RequestRunResult is the outcome of one request executed by the test runner
+type RequestRunResult struct {
+	Collection string
+	Folder     string // Folder of the request inside the collection, empty at the top
+	Name       string
+	FilePath   string
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+	Duration   time.Duration
+	Size       int64
+	Assertions []AssertionResult
+	Snapshot   *SnapshotResult // Set when the response body was checked against a snapshot
+	Error      string
+
+	Response *HTTPResponse
+}
+
+// Passed reports whether the request completed and every assertion held
+func (r RequestRunResult) Passed() bool {
+	if r.Error != "" {
+		return false
+	}
+	for _, assertion := range r.Assertions {
+		if !assertion.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+type TestRunResult struct {
+	StartedAt   time.Time
+	Duration    time.Duration
+	Environment string
+	Results     []RequestRunResult
+}
+
+// Counts returns the number of passed, failed (assertions) and errored (transport) requests
+func (t *TestRunResult) Counts() (passed, failed, errored int) {
+	for _, result := range t.Results {
+		switch {
+		case result.Error != "":
+			errored++
+		case result.Passed():
+			passed++
+		default:
+			failed++
+		}
+	}
+	return passed, failed, errored
+}
+
+func (t *TestRunResult) Passed() bool {
+	_, failed, errored := t.Counts()
+	return failed == 0 && errored == 0
+}
+
+// TestOptions controls the snapshot checks of a test run
+type TestOptions struct {
+	Snapshots       bool // Check every request against a snapshot, not only those with "snapshot" set
+	UpdateSnapshots bool // Rewrite snapshots instead of failing on differences
+}
+
+// runCollectionTests executes every request of the given collections in order.
+// Captures are applied after each request so later requests can use them.
+func (r *Runner) runCollectionTests(collections []Collection, options TestOptions, onResult func(RequestRunResult)) *TestRunResult {
+	run := &TestRunResult{
+		StartedAt:   time.Now(),
+		Environment: r.configLoader.GetEnvironmentLabel(r.config),
+	}
+	httpClient := NewHTTPClient(r.config, r.secret, r.configLoader)
+
+	for _, collection := range collections {
+		for _, item := range collection.Requests {
+			response, _ := httpClient.ExecuteRequest(item.Request, item.Variables)
+			r.recordHistory(&item, response)
+
+			result := RequestRunResult{
+				Collection: collection.Name,
+				Folder:     item.Folder,
+				Name:       item.Name,
+				FilePath:   item.FilePath,
+				Method:     item.Request.Method,
+				URL:        r.configLoader.ReplaceVariables(item.Request.URL, r.config, item.Variables),
+				StatusCode: response.StatusCode,
+				Status:     response.Status,
+				Duration:   response.Duration,
+				Size:       response.Size,
+				Response:   response,
+			}
+			if response.Request != nil {
+				result.URL = response.Request.URL
+			}
+
+			if response.Error != nil {
+				result.Error = formatError(response.Error)
+			} else {
+				result.Assertions = EvaluateAssertions(item.Request.Assertions, response)
+				if _, err := r.configLoader.ApplyCaptures(item.Request.Captures, response, r.secret); err != nil {
+					result.Error = fmt.Sprintf("failed to save captures: %v", err)
+				}
+
+				if snapshotEnabled(item.Request, options.Snapshots) {
+					snapshot, err := r.configLoader.CheckSnapshot(r.config, &item, response, options.UpdateSnapshots)
+					if err != nil {
+						if result.Error != "" {
+							result.Error += "; "
+						}
+						result.Error += err.Error()
+					} else {
+						result.Snapshot = snapshot
+						result.Assertions = append(result.Assertions, snapshotAssertions(snapshot)...)
+					}
+				}
+			}
+
+			run.Results = append(run.Results, result)
+			if onResult != nil {
+				onResult(result)
+			}
+		}
+	}
+
+	run.Duration = time.Since(run.StartedAt)
+	return run
+}
@@ -10,6 +10,7 @@ import (
 )
 
 type TextInputViewModel struct {
+	id        string // Set when embedded in the app, results are sent as textInputSubmittedMsg
 	textInput textinput.Model
 	title     string
 	endValue  *string
@@ -26,6 +27,16 @@ func (m TextInputViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.endValue == nil {
+			switch msg.String() {
+			case "esc":
+				return m, emit(navigateBackMsg{})
+			case "enter":
+				return m, emit(textInputSubmittedMsg{id: m.id, value: strings.TrimSpace(m.textInput.Value())})
+			}
+			break
+		}
+
 		switch msg.String() {
 		case "ctrl+c", "esc":
 			*m.endValue = ExitSignal
@@ -55,18 +66,31 @@ func (m TextInputViewModel) View() string {
 	)
 }
 
-func TextFieldView(title, placeHolder string, endValue *string) {
-	styles := DefaultStyles()
-
+func newTextInput(placeHolder string) textinput.Model {
 	ti := textinput.New()
 	ti.Placeholder = placeHolder
 	ti.SetValue(placeHolder) // Set initial value
 	ti.Focus()
 	ti.CharLimit = 500 // Increase limit for long JWTs
 	ti.Width = 80
+	return ti
+}
+
+// NewEmbeddedTextInputViewModel creates a text input screen for the app navigation stack
+func NewEmbeddedTextInputViewModel(id, title, placeHolder string) TextInputViewModel {
+	return TextInputViewModel{
+		id:        id,
+		textInput: newTextInput(placeHolder),
+		title:     title,
+		styles:    *DefaultStyles(),
+	}
+}
+
+func TextFieldView(title, placeHolder string, endValue *string) {
+	styles := DefaultStyles()
 
 	m := TextInputViewModel{
-		textInput: ti,
+		textInput: newTextInput(placeHolder),
 		title:     title,
 		endValue:  endValue,
 		quitting:  false,
@@ -0,0 +1,131 @@
+package src
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// variablePattern matches {{name}} references, allowing surrounding spaces
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.$-]+)\s*\}\}`)
+
+type VariableResolver struct {
+	variables map[string]string
+}
+
+// NewVariableResolver merges the given scopes, later scopes override earlier ones
+func NewVariableResolver(scopes ...map[string]string) *VariableResolver {
+	variables := make(map[string]string)
+	for _, scope := range scopes {
+		for name, value := range scope {
+			variables[name] = value
+		}
+	}
+	return &VariableResolver{variables: variables}
+}
+
+func (v *VariableResolver) Lookup(name string) (string, bool) {
+	value, ok := v.variables[name]
+	return value, ok
+}
+
+func (v *VariableResolver) Variables() map[string]string {
+	return v.variables
+}
+
+// Resolve replaces every {{name}} reference recursively, failing on undefined or cyclic variables
+func (v *VariableResolver) Resolve(text string) (string, error) {
+	return v.resolve(text, nil)
+}
+
+func (v *VariableResolver) resolve(text string, stack []string) (string, error) {
+	var resolveErr error
+
+	result := variablePattern.ReplaceAllStringFunc(text, func(match string) string {
+		if resolveErr != nil {
+			return match
+		}
+
+		name := variablePattern.FindStringSubmatch(match)[1]
+
+		for i, seen := range stack {
+			if seen == name {
+				cycle := append(append([]string{}, stack[i:]...), name)
+				resolveErr = fmt.Errorf("cyclic variable reference: %s", strings.Join(cycle, " -> "))
+				return match
+			}
+		}
+
+		value, ok := v.variables[name]
+		if !ok {
+			if len(stack) > 0 {
+				resolveErr = fmt.Errorf("undefined variable {{%s}} (referenced by {{%s}})", name, stack[len(stack)-1])
+			} else {
+				resolveErr = fmt.Errorf("undefined variable {{%s}}", name)
+			}
+			return match
+		}
+
+		resolved, err := v.resolve(value, append(stack, name))
+		if err != nil {
+			resolveErr = err
+			return match
+		}
+		return resolved
+	})
+
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+	return result, nil
+}
+
+// ResolveLenient resolves what it can and leaves undefined or cyclic references untouched.
+// Used for display where an error would hide the whole value.
+func (v *VariableResolver) ResolveLenient(text string) string {
+	return variablePattern.ReplaceAllStringFunc(text, func(match string) string {
+		resolved, err := v.Resolve(match)
+		if err != nil {
+			return match
+		}
+		return resolved
+	})
+}
+
+// ResolveBody returns a deep copy of a JSON body with every string leaf resolved
+func (v *VariableResolver) ResolveBody(body interface{}) (interface{}, error) {
+	return v.resolveValue(body, "$")
+}
+
+func (v *VariableResolver) resolveValue(value interface{}, path string) (interface{}, error) {
+	switch val := value.(type) {
+	case string:
+		resolved, err := v.Resolve(val)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		return resolved, nil
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(val))
+		for key, item := range val {
+			resolved, err := v.resolveValue(item, path+"."+key)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = resolved
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(val))
+		for i, item := range val {
+			resolved, err := v.resolveValue(item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			result[i] = resolved
+		}
+		return result, nil
+	default:
+		return val, nil
+	}
+}
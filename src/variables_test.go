@@ -0,0 +1,134 @@
+package src
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVariableResolverResolve(t *testing.T) {
+	resolver := NewVariableResolver(
+		map[string]string{"baseUrl": "http://localhost", "tenant": "acme", "user": "{{tenant}}-admin"},
+		map[string]string{"tenant": "globex"}, // Later scopes win
+		map[string]string{
+			"url":         "{{baseUrl}}/{{user}}",
+			"self":        "{{self}}",
+			"a":           "{{b}}",
+			"b":           "{{c}}",
+			"c":           "{{a}}",
+			"broken":      "{{missing}}",
+			"dotted.name": "ok",
+			"$special":    "dollar",
+		},
+	)
+
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr string
+	}{
+		{name: "plain text", text: "no variables", want: "no variables"},
+		{name: "single", text: "{{baseUrl}}/users", want: "http://localhost/users"},
+		{name: "spaces inside braces", text: "{{ baseUrl }}", want: "http://localhost"},
+		{name: "later scope overrides", text: "{{tenant}}", want: "globex"},
+		{name: "nested references", text: "{{url}}", want: "http://localhost/globex-admin"},
+		{name: "dots and dollars in names", text: "{{dotted.name}} {{$special}}", want: "ok dollar"},
+		{name: "single braces are text", text: "{baseUrl} {{}}", want: "{baseUrl} {{}}"},
+		{name: "undefined", text: "{{nope}}", wantErr: "undefined variable {{nope}}"},
+		{name: "undefined through a reference", text: "{{broken}}", wantErr: "undefined variable {{missing}} (referenced by {{broken}})"},
+		{name: "self reference", text: "{{self}}", wantErr: "cyclic variable reference: self -> self"},
+		{name: "cycle", text: "x {{a}}", wantErr: "cyclic variable reference: a -> b -> c -> a"},
+		{name: "cycle entered midway", text: "{{b}}", wantErr: "cyclic variable reference: b -> c -> a -> b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.Resolve(tt.text)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVariableResolverResolveLenient(t *testing.T) {
+	resolver := NewVariableResolver(map[string]string{"host": "example.com", "loop": "{{loop}}"})
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"https://{{host}}/{{id}}", "https://example.com/{{id}}"},
+		{"{{loop}} and {{host}}", "{{loop}} and example.com"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := resolver.ResolveLenient(tt.text); got != tt.want {
+			t.Errorf("ResolveLenient(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestVariableResolverResolveBody(t *testing.T) {
+	resolver := NewVariableResolver(map[string]string{"id": "42", "name": "Ada"})
+
+	tests := []struct {
+		name    string
+		body    interface{}
+		want    interface{}
+		wantErr string
+	}{
+		{
+			name: "string leaves are resolved, other values kept",
+			body: map[string]interface{}{"id": "{{id}}", "count": 3.0, "active": true, "none": nil},
+			want: map[string]interface{}{"id": "42", "count": 3.0, "active": true, "none": nil},
+		},
+		{
+			name: "nested arrays and objects",
+			body: []interface{}{map[string]interface{}{"user": []interface{}{"{{name}}", "x"}}},
+			want: []interface{}{map[string]interface{}{"user": []interface{}{"Ada", "x"}}},
+		},
+		{
+			name:    "errors name the JSONPath of the value",
+			body:    map[string]interface{}{"items": []interface{}{"ok", "{{missing}}"}},
+			wantErr: "$.items[1]: undefined variable {{missing}}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolver.ResolveBody(tt.body)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// The body of the request file must not change
+	body := map[string]interface{}{"id": "{{id}}"}
+	if _, err := resolver.ResolveBody(body); err != nil {
+		t.Fatal(err)
+	}
+	if body["id"] != "{{id}}" {
+		t.Errorf("ResolveBody modified its input: %v", body)
+	}
+}
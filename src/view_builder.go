@@ -3,9 +3,7 @@ package src
 type ViewBuilderInterface interface {
 	NewListView(title string, op []ListItem, height int) ListItem
 	NewTextFieldView(title, placeHolder string) string
-	NewCollectionsView(collections []Collection, config *ConfigJSON, secret *SecretJSON, configLoader *ConfigLoader, fileManager FileManagerInterface) string
-	NewBodyEditorView(body interface{}) string
-	NewRequestPreviewView(selectedRequest *RequestItem, config *ConfigJSON, secret *SecretJSON, configLoader *ConfigLoader) string
+	NewAppView(collections []Collection, config *ConfigJSON, secret *SecretJSON, configLoader *ConfigLoader, fileManager FileManagerInterface, utils UtilsInterface)
 }
 
 type ViewBuilder struct{}
@@ -26,20 +24,6 @@ func (b *ViewBuilder) NewTextFieldView(title, placeHolder string) string {
 	return endValue
 }
 
-func (b *ViewBuilder) NewCollectionsView(collections []Collection, config *ConfigJSON, secret *SecretJSON, configLoader *ConfigLoader, fileManager FileManagerInterface) string {
-	selected := ""
-	CollectionsView(collections, config, secret, configLoader, fileManager, &selected)
-	return selected
-}
-
-func (b *ViewBuilder) NewBodyEditorView(body interface{}) string {
-	selected := ""
-	BodyEditorView(body, &selected)
-	return selected
-}
-
-func (b *ViewBuilder) NewRequestPreviewView(selectedRequest *RequestItem, config *ConfigJSON, secret *SecretJSON, configLoader *ConfigLoader) string {
-	action := ""
-	RequestPreviewView(selectedRequest, config, secret, configLoader, &action)
-	return action
+func (b *ViewBuilder) NewAppView(collections []Collection, config *ConfigJSON, secret *SecretJSON, configLoader *ConfigLoader, fileManager FileManagerInterface, utils UtilsInterface) {
+	AppView(collections, config, secret, configLoader, fileManager, utils)
 }
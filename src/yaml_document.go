@@ -0,0 +1,163 @@
+package src
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UpdateYAMLContent writes value into a YAML file's content through its node tree, changing only
+// the values that differ from the data the content already holds (as decoded into the same type).
+// Comments, key order and the style of untouched values are kept.
+func UpdateYAMLContent[T any](content string, value *T) (string, error) {
+	previous, err := ParseDocumentContent[T](content, true)
+	if err != nil {
+		return "", fmt.Errorf("UpdateYAMLContent -> %v", err)
+	}
+	before, err := yamlValueNode(previous)
+	if err != nil {
+		return "", fmt.Errorf("UpdateYAMLContent -> %v", err)
+	}
+	after, err := yamlValueNode(value)
+	if err != nil {
+		return "", fmt.Errorf("UpdateYAMLContent -> %v", err)
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &document); err != nil {
+		return "", fmt.Errorf("UpdateYAMLContent -> %v", err)
+	}
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return "", fmt.Errorf("UpdateYAMLContent -> the file holds no YAML document")
+	}
+	updateYAMLNode(document.Content[0], before, after)
+
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(yamlIndent(content))
+	if err := encoder.Encode(&document); err != nil {
+		return "", fmt.Errorf("UpdateYAMLContent -> %v", err)
+	}
+	return buffer.String(), nil
+}
+
+// yamlValueNode converts a value to a YAML node the way ToYAML writes it
+func yamlValueNode(v interface{}) (*yaml.Node, error) {
+	content, err := ToJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &document); err != nil {
+		return nil, err
+	}
+	clearYAMLStyle(&document)
+	return document.Content[0], nil
+}
+
+// updateYAMLNode applies the differences between before and after to node. Mappings are updated
+// key by key, anything else that changed is replaced as a whole.
+func updateYAMLNode(node, before, after *yaml.Node) {
+	if yamlNodesEqual(before, after) {
+		return
+	}
+	if node.Kind != yaml.MappingNode || before.Kind != yaml.MappingNode || after.Kind != yaml.MappingNode {
+		replaceYAMLNode(node, after)
+		return
+	}
+
+	for i := 0; i+1 < len(after.Content); i += 2 {
+		key, value := after.Content[i].Value, after.Content[i+1]
+		previous := yamlMember(before, key)
+		current := yamlMember(node, key)
+		switch {
+		case previous != nil && yamlNodesEqual(previous, value):
+			// Unchanged, including defaults the file leaves out
+			continue
+		case current == nil:
+			// New keys go after the key they follow in the new version
+			position := len(node.Content)
+			if i > 0 {
+				if index := yamlMemberIndex(node, after.Content[i-2].Value); index >= 0 {
+					position = index + 2
+				}
+			}
+			node.Content = append(node.Content[:position], append([]*yaml.Node{after.Content[i], value}, node.Content[position:]...)...)
+		case previous == nil:
+			replaceYAMLNode(current, value)
+		default:
+			updateYAMLNode(current, previous, value)
+		}
+	}
+
+	for i := 0; i+1 < len(before.Content); i += 2 {
+		key := before.Content[i].Value
+		if yamlMember(after, key) != nil {
+			continue
+		}
+		if index := yamlMemberIndex(node, key); index >= 0 {
+			node.Content = append(node.Content[:index], node.Content[index+2:]...)
+		}
+	}
+}
+
+// replaceYAMLNode swaps the value of a node, keeping its comments and, for strings that stay
+// strings, its quoting or block style
+func replaceYAMLNode(node, value *yaml.Node) {
+	head, line, foot := node.HeadComment, node.LineComment, node.FootComment
+	style := node.Style
+	wasString := node.Kind == yaml.ScalarNode && node.ShortTag() == "!!str"
+
+	*node = *value
+	node.HeadComment, node.LineComment, node.FootComment = head, line, foot
+	if wasString && node.Kind == yaml.ScalarNode && node.ShortTag() == "!!str" {
+		// A literal or folded block only fits values with several lines
+		if style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0 || strings.Contains(node.Value, "\n") {
+			node.Style = style
+		}
+	}
+}
+
+// yamlMemberIndex finds the key of a mapping, exactly or ignoring case like JSON decoding does
+func yamlMemberIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if strings.EqualFold(mapping.Content[i].Value, key) {
+			return i
+		}
+	}
+	return -1
+}
+
+func yamlMember(mapping *yaml.Node, key string) *yaml.Node {
+	if index := yamlMemberIndex(mapping, key); index >= 0 {
+		return mapping.Content[index+1]
+	}
+	return nil
+}
+
+func yamlNodesEqual(a, b *yaml.Node) bool {
+	var valueA, valueB interface{}
+	if a.Decode(&valueA) != nil || b.Decode(&valueB) != nil {
+		return false
+	}
+	return reflect.DeepEqual(valueA, valueB)
+}
+
+// yamlIndent guesses the indentation of a YAML file from its first indented line
+func yamlIndent(content string) int {
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if indent := len(line) - len(trimmed); indent > 0 && trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			return max(indent, 2)
+		}
+	}
+	return 2
+}
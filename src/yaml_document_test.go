@@ -0,0 +1,112 @@
+package src
+
+import "testing"
+
+func TestUpdateYAMLContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		edit    func(*RequestJSON)
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "comments and quoting of untouched values are kept",
+			content: "# Users\nname: 'Get'   # shown in the list\nmethod: GET\nurl: \"/a\"\n",
+			edit:    func(r *RequestJSON) {},
+			want:    "# Users\nname: 'Get' # shown in the list\nmethod: GET\nurl: \"/a\"\n",
+		},
+		{
+			name:    "changed string keeps its comment and quotes",
+			content: "name: Get\nmethod: GET\nurl: \"/a\" # the list\n",
+			edit:    func(r *RequestJSON) { r.URL = "/b" },
+			want:    "name: Get\nmethod: GET\nurl: \"/b\" # the list\n",
+		},
+		{
+			name:    "new key goes after the key it follows",
+			content: "name: Get\nmethod: GET\nurl: /a\nskipAuth: false\n",
+			edit:    func(r *RequestJSON) { r.Headers = map[string]string{"X-Id": "1"} },
+			want:    "name: Get\nmethod: GET\nurl: /a\nskipAuth: false\nheaders:\n  X-Id: \"1\"\n",
+		},
+		{
+			name:    "removed key is deleted",
+			content: "name: Get\nmethod: GET\nrawBody: x\nurl: /a\n",
+			edit:    func(r *RequestJSON) { r.RawBody = "" },
+			want:    "name: Get\nmethod: GET\nurl: /a\n",
+		},
+		{
+			name:    "keys spelled with another case are updated, not duplicated",
+			content: "Name: Get\nmethod: GET\nURL: /a\nHeaders:\n  A: \"1\"\n",
+			edit:    func(r *RequestJSON) { r.URL = "/b"; r.Headers = nil },
+			want:    "Name: Get\nmethod: GET\nURL: /b\n",
+		},
+		{
+			name:    "nested mappings are updated key by key",
+			content: "name: Get\nmethod: GET\nurl: /a\nheaders:\n    A: \"1\" # first\n    B: \"2\"\n",
+			edit:    func(r *RequestJSON) { r.Headers["B"] = "3" },
+			want:    "name: Get\nmethod: GET\nurl: /a\nheaders:\n    A: \"1\" # first\n    B: \"3\"\n",
+		},
+		{
+			name:    "literal blocks stay blocks while they have several lines",
+			content: "name: Post\nmethod: POST\nurl: /a\nrawBody: |\n  a=1\n  b=2\n",
+			edit:    func(r *RequestJSON) { r.RawBody = "a=1\nb=3\n" },
+			want:    "name: Post\nmethod: POST\nurl: /a\nrawBody: |\n  a=1\n  b=3\n",
+		},
+		{
+			name:    "literal blocks become plain strings on one line",
+			content: "name: Post\nmethod: POST\nurl: /a\nrawBody: |\n  a=1\n  b=2\n",
+			edit:    func(r *RequestJSON) { r.RawBody = "a=1" },
+			want:    "name: Post\nmethod: POST\nurl: /a\nrawBody: a=1\n",
+		},
+		{
+			name:    "empty file",
+			content: "",
+			edit:    func(r *RequestJSON) { r.Name = "Get" },
+			wantErr: "UpdateYAMLContent -> the file holds no YAML document",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := ParseDocumentContent[RequestJSON](tt.content, true)
+			if err != nil {
+				t.Fatalf("ParseDocumentContent: %v", err)
+			}
+			tt.edit(request)
+			got, err := UpdateYAMLContent(tt.content, request)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpdateYAMLContent: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYAMLIndent(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+	}{
+		{name: "first indented line", content: "headers:\n    A: \"1\"\nbody:\n  a: 1\n", want: 4},
+		{name: "comments and blank lines are skipped", content: "a:\n      # note\n   \n  b: 1\n", want: 2},
+		{name: "one space is too narrow", content: "a:\n b: 1\n", want: 2},
+		{name: "flat file", content: "a: 1\n", want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := yamlIndent(tt.content); got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}